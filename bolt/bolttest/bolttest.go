@@ -0,0 +1,413 @@
+// Package bolttest sends requests to a Bolt app through the production
+// request path, without sockets.
+//
+// App.ServeHTTP with net/http/httptest exercises the net/http adapter.
+// Production traffic instead goes through Shockwave: its HTTP/1.1 parser,
+// response writer and keep-alive connection handling. A Client dials the app
+// over in-memory pipes (net.Pipe) served by App.ServeConn, so tests see
+// exactly the bytes a real client would, including response framing.
+//
+// Example:
+//
+//	func TestUsers(t *testing.T) {
+//	    app := bolt.New()
+//	    app.Post("/login", login)
+//	    app.Get("/users/:id", getUser)
+//
+//	    client := bolttest.New(t, app)
+//	    client.Post("/login").WithForm(url.Values{"user": {"ada"}}).Expect(204).
+//	        ExpectCookie("session", "")
+//
+//	    var user User
+//	    client.Get("/users/42").WithHeader("Accept", "application/json").
+//	        Expect(200).
+//	        JSONBody(&user)
+//	}
+package bolttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bolt/core"
+)
+
+// BaseURL is the URL requests are sent to. Its host is the Host header.
+const BaseURL = "http://bolt.test"
+
+// DefaultTimeout bounds each request, including reading the response body.
+// Responses without framing (no Content-Length, not chunked) never end on
+// keep-alive connections and fail after it.
+const DefaultTimeout = 5 * time.Second
+
+// Client sends requests to an App over in-memory connections.
+//
+// Cookies set by responses are stored and sent with later requests, like a
+// browser (Secure cookies included). Connections are kept alive and reused.
+// Clients are safe for concurrent use.
+type Client struct {
+	t    testing.TB
+	base *url.URL
+	jar  *secureJar
+
+	// HTTP is the underlying client. Its Timeout defaults to DefaultTimeout.
+	HTTP *http.Client
+}
+
+// New returns a client for app. Connections are closed when the test ends.
+func New(t testing.TB, app *core.App) *Client {
+	t.Helper()
+
+	base, _ := url.Parse(BaseURL)
+	jar, _ := cookiejar.New(nil)
+	dialer := &pipeDialer{app: app}
+	dialer.port.Store(49151)
+
+	transport := &http.Transport{
+		DialContext:         dialer.dial,
+		DisableCompression:  true, // Show Content-Encoding as sent
+		MaxIdleConnsPerHost: 8,
+	}
+	c := &Client{
+		t:    t,
+		base: base,
+		jar:  &secureJar{jar: jar},
+	}
+	c.HTTP = &http.Client{
+		Transport: transport,
+		Jar:       c.jar,
+		Timeout:   DefaultTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse // Redirects are responses to assert on
+		},
+	}
+
+	t.Cleanup(func() {
+		transport.CloseIdleConnections()
+		dialer.conns.Wait()
+	})
+	return c
+}
+
+// WithT returns a client sharing c's connections and cookies that reports
+// failures to t, for subtests.
+//
+// Example:
+//
+//	for _, tt := range tests {
+//	    t.Run(tt.name, func(t *testing.T) {
+//	        client.WithT(t).Get(tt.path).Expect(tt.status)
+//	    })
+//	}
+func (c *Client) WithT(t testing.TB) *Client {
+	clone := *c
+	clone.t = t
+	return &clone
+}
+
+// Get starts a GET request for path (with an optional query string).
+func (c *Client) Get(path string) *Request { return c.Request("GET", path) }
+
+// Head starts a HEAD request.
+func (c *Client) Head(path string) *Request { return c.Request("HEAD", path) }
+
+// Post starts a POST request.
+func (c *Client) Post(path string) *Request { return c.Request("POST", path) }
+
+// Put starts a PUT request.
+func (c *Client) Put(path string) *Request { return c.Request("PUT", path) }
+
+// Patch starts a PATCH request.
+func (c *Client) Patch(path string) *Request { return c.Request("PATCH", path) }
+
+// Delete starts a DELETE request.
+func (c *Client) Delete(path string) *Request { return c.Request("DELETE", path) }
+
+// Options starts an OPTIONS request.
+func (c *Client) Options(path string) *Request { return c.Request("OPTIONS", path) }
+
+// Request starts a request with any method.
+func (c *Client) Request(method, path string) *Request {
+	return &Request{
+		client: c,
+		method: method,
+		path:   path,
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+}
+
+// Cookie returns the value of a stored cookie, or "" if there is none.
+func (c *Client) Cookie(name string) string {
+	for _, cookie := range c.jar.Cookies(c.base) {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// SetCookie stores a cookie, sent with later requests.
+func (c *Client) SetCookie(name, value string) {
+	c.jar.SetCookies(c.base, []*http.Cookie{{Name: name, Value: value, Path: "/"}})
+}
+
+// ClearCookies forgets every stored cookie.
+func (c *Client) ClearCookies() {
+	jar, _ := cookiejar.New(nil)
+	c.jar.mu.Lock()
+	c.jar.jar = jar
+	c.jar.mu.Unlock()
+}
+
+// Request is a request being built. Finish it with Do or Expect.
+type Request struct {
+	client *Client
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	body   []byte
+	err    error
+}
+
+// WithHeader sets a request header.
+func (r *Request) WithHeader(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// WithQuery adds a query parameter.
+func (r *Request) WithQuery(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// WithCookie sends a cookie with this request only.
+func (r *Request) WithCookie(name, value string) *Request {
+	r.header.Add("Cookie", (&http.Cookie{Name: name, Value: value}).String())
+	return r
+}
+
+// WithBody sets the request body and its Content-Type.
+func (r *Request) WithBody(contentType string, body []byte) *Request {
+	r.header.Set("Content-Type", contentType)
+	r.body = body
+	return r
+}
+
+// WithJSON sets v, encoded as JSON, as the request body.
+func (r *Request) WithJSON(v any) *Request {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.err = fmt.Errorf("encoding JSON body: %w", err)
+	}
+	return r.WithBody("application/json", body)
+}
+
+// WithForm sets an urlencoded form as the request body.
+func (r *Request) WithForm(values url.Values) *Request {
+	return r.WithBody("application/x-www-form-urlencoded", []byte(values.Encode()))
+}
+
+// Do sends the request and reads the whole response.
+// The test fails immediately if no response arrives.
+func (r *Request) Do() *Response {
+	t := r.client.t
+	t.Helper()
+	if r.err != nil {
+		t.Fatalf("bolttest: %s %s: %v", r.method, r.path, r.err)
+	}
+
+	target, err := r.client.base.Parse(r.path)
+	if err != nil {
+		t.Fatalf("bolttest: %s %s: %v", r.method, r.path, err)
+	}
+	if len(r.query) > 0 {
+		query := target.Query()
+		for key, values := range r.query {
+			query[key] = append(query[key], values...)
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req, err := http.NewRequest(r.method, target.String(), body)
+	if err != nil {
+		t.Fatalf("bolttest: %s %s: %v", r.method, r.path, err)
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+
+	res, err := r.client.HTTP.Do(req)
+	if err != nil {
+		t.Fatalf("bolttest: %s %s: %v", r.method, r.path, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("bolttest: %s %s: reading body: %v", r.method, r.path, err)
+	}
+
+	return &Response{
+		t:        t,
+		name:     r.method + " " + r.path,
+		Response: res,
+		Body:     data,
+	}
+}
+
+// Expect sends the request and checks the response status.
+func (r *Request) Expect(status int) *Response {
+	r.client.t.Helper()
+	return r.Do().ExpectStatus(status)
+}
+
+// Response is a received response. Expect methods report failures with
+// t.Errorf and return the response for chaining.
+type Response struct {
+	t    testing.TB
+	name string
+
+	// Response is the received response. Its Body is already read and closed.
+	*http.Response
+
+	// Body is the response body.
+	Body []byte
+}
+
+// Text returns the body as a string.
+func (r *Response) Text() string {
+	return string(r.Body)
+}
+
+// ExpectStatus checks the status code.
+func (r *Response) ExpectStatus(status int) *Response {
+	r.t.Helper()
+	if r.StatusCode != status {
+		r.t.Errorf("%s: expected status %d, got %d: %s", r.name, status, r.StatusCode, r.Body)
+	}
+	return r
+}
+
+// ExpectHeader checks a response header ("" expects it to be absent).
+func (r *Response) ExpectHeader(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Header.Get(key); got != value {
+		r.t.Errorf("%s: expected %s %q, got %q", r.name, key, value, got)
+	}
+	return r
+}
+
+// ExpectBody checks the whole body.
+func (r *Response) ExpectBody(body string) *Response {
+	r.t.Helper()
+	if string(r.Body) != body {
+		r.t.Errorf("%s: expected body %q, got %q", r.name, body, r.Body)
+	}
+	return r
+}
+
+// ExpectBodyContains checks that the body contains s.
+func (r *Response) ExpectBodyContains(s string) *Response {
+	r.t.Helper()
+	if !strings.Contains(string(r.Body), s) {
+		r.t.Errorf("%s: expected body containing %q, got %q", r.name, s, r.Body)
+	}
+	return r
+}
+
+// ExpectCookie checks that the response sets a cookie. An empty value
+// accepts any value; an expired cookie (deletion) never matches.
+func (r *Response) ExpectCookie(name, value string) *Response {
+	r.t.Helper()
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name && cookie.MaxAge >= 0 && (value == "" || cookie.Value == value) {
+			return r
+		}
+	}
+	r.t.Errorf("%s: expected cookie %s=%q in %q", r.name, name, value, r.Header.Values("Set-Cookie"))
+	return r
+}
+
+// JSONBody decodes the body into v.
+func (r *Response) JSONBody(v any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		r.t.Errorf("%s: decoding JSON body %q: %v", r.name, r.Body, err)
+	}
+	return r
+}
+
+// pipeDialer connects to an app over in-memory pipes.
+type pipeDialer struct {
+	app   *core.App
+	conns sync.WaitGroup // ServeConn goroutines
+	port  atomic.Int32   // Client port of the last connection
+}
+
+// dial returns the client end of a new pipe served by the app.
+func (d *pipeDialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	clientEnd, serverEnd := net.Pipe()
+	client := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: int(d.port.Add(1))}
+	server := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}
+
+	d.conns.Add(1)
+	go func() {
+		defer d.conns.Done()
+		_ = d.app.ServeConn(&pipeConn{Conn: serverEnd, local: server, remote: client})
+	}()
+	return &pipeConn{Conn: clientEnd, local: client, remote: server}, nil
+}
+
+// pipeConn is an in-memory connection with TCP addresses, so handlers see
+// a loopback client as in production.
+type pipeConn struct {
+	net.Conn
+	local, remote net.Addr
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.local }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.remote }
+
+// secureJar stores and sends cookies as if the connection used TLS, so
+// Secure cookies (sessions, CSRF tokens) work over the plain pipe.
+type secureJar struct {
+	mu  sync.Mutex
+	jar *cookiejar.Jar
+}
+
+func (j *secureJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(secureURL(u), cookies)
+}
+
+func (j *secureJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(secureURL(u))
+}
+
+// secureURL returns u with the https scheme.
+func secureURL(u *url.URL) *url.URL {
+	secure := *u
+	secure.Scheme = "https"
+	return &secure
+}
@@ -0,0 +1,229 @@
+package bolttest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bolt/core"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// newApp returns an app covering the response writers.
+func newApp() *core.App {
+	app := core.New()
+	app.Get("/users/:id", func(c *core.Context) error {
+		return c.JSON(200, user{ID: 42, Name: c.Param("id") + ":" + c.Query("q")})
+	})
+	app.Post("/users", func(c *core.Context) error {
+		var u user
+		if err := c.BindJSON(&u); err != nil {
+			return err
+		}
+		return c.JSON(201, u)
+	})
+	app.Post("/login", func(c *core.Context) error {
+		c.SetCookie(&core.Cookie{Name: "session", Value: c.FormValue("user"), Path: "/", Secure: true, HttpOnly: true})
+		return c.NoContent()
+	})
+	app.Get("/whoami", func(c *core.Context) error {
+		return c.Text(200, c.Cookie("session"))
+	})
+	app.Delete("/users/:id", func(c *core.Context) error {
+		return c.JSON(204, user{}) // The body must be dropped
+	})
+	app.Get("/text", func(c *core.Context) error {
+		return c.Text(200, "hello")
+	})
+	app.Head("/text", func(c *core.Context) error {
+		return c.Text(200, "hello")
+	})
+	app.Get("/html", func(c *core.Context) error {
+		return c.HTML(200, "<p>hello</p>")
+	})
+	app.Get("/blob", func(c *core.Context) error {
+		return c.Blob(200, "application/octet-stream", []byte{1, 2, 3})
+	})
+	app.Get("/ok", func(c *core.Context) error {
+		return c.JSONOK()
+	})
+	app.Get("/empty", func(c *core.Context) error {
+		return nil
+	})
+	app.Get("/header", func(c *core.Context) error {
+		c.SetHeader("X-Echo", c.GetHeader("X-Request"))
+		return c.NoContent()
+	})
+	app.Get("/events", func(c *core.Context) error {
+		return c.SSE(func(stream *core.SSEStream) error {
+			return stream.Send("", "", "a")
+		})
+	})
+	return app
+}
+
+// TestClient tests requests, bodies and assertions.
+func TestClient(t *testing.T) {
+	client := New(t, newApp())
+
+	var u user
+	client.Get("/users/ada").WithQuery("q", "x").Expect(200).
+		ExpectHeader("Content-Type", "application/json").
+		JSONBody(&u)
+	if u.ID != 42 || u.Name != "ada:x" {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	client.Post("/users").WithJSON(user{ID: 7, Name: "grace"}).Expect(201).
+		ExpectBody("{\"id\":7,\"name\":\"grace\"}\n")
+	client.Post("/users").WithBody("application/json", []byte("{")).Expect(400)
+	client.Get("/missing").Expect(404).ExpectBody(`{"error":"Not Found"}`)
+	client.Put("/users/1").Expect(405)
+	client.Get("/header").WithHeader("X-Request", "abc").Expect(204).ExpectHeader("X-Echo", "abc")
+	client.Get("/events").Expect(200).ExpectBody("data: a\n\n")
+}
+
+// TestClientKeepAlive tests that every response is framed: a keep-alive
+// connection only gets its next response if the previous one ended, and
+// bodies of 204 and HEAD responses are dropped.
+func TestClientKeepAlive(t *testing.T) {
+	client := New(t, newApp())
+	client.HTTP.Timeout = time.Second
+
+	paths := map[string]string{
+		"/text":  "hello",
+		"/html":  "<p>hello</p>",
+		"/blob":  "\x01\x02\x03",
+		"/ok":    `{"ok":true}`,
+		"/empty": "",
+	}
+	for range 3 {
+		for path, body := range paths {
+			client.Get(path).Expect(200).ExpectBody(body)
+		}
+		client.Delete("/users/1").Expect(204).ExpectBody("")
+		client.Head("/text").Expect(200).ExpectHeader("Content-Length", "5").ExpectBody("")
+	}
+}
+
+// TestClientCookies tests cookie persistence.
+func TestClientCookies(t *testing.T) {
+	client := New(t, newApp())
+
+	client.Post("/login").WithForm(url.Values{"user": {"ada"}}).Expect(204).
+		ExpectCookie("session", "ada").
+		ExpectCookie("session", "")
+	if client.Cookie("session") != "ada" {
+		t.Errorf("expected the session cookie to be stored, got %q", client.Cookie("session"))
+	}
+	client.Get("/whoami").Expect(200).ExpectBody("ada")
+	client.Get("/whoami").WithCookie("session", "grace").Expect(200).ExpectBody("grace")
+
+	client.SetCookie("session", "linus")
+	client.Get("/whoami").Expect(200).ExpectBody("linus")
+
+	client.ClearCookies()
+	client.Get("/whoami").Expect(200).ExpectBody("")
+}
+
+// TestClientConcurrent tests concurrent requests over several connections.
+func TestClientConcurrent(t *testing.T) {
+	client := New(t, newApp())
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			client.Get(fmt.Sprintf("/users/%d", i)).Expect(200).ExpectBodyContains(fmt.Sprintf(`"name":"%d:"`, i))
+		})
+	}
+	wg.Wait()
+}
+
+// TestClientRemoteAddr tests the peer and forwarded addresses on Shockwave.
+func TestClientRemoteAddr(t *testing.T) {
+	app := core.NewWithConfig(core.Config{TrustedProxies: []string{"127.0.0.1"}})
+	app.Get("/whoami", func(c *core.Context) error {
+		return c.Text(200, c.RealIP()+" "+c.Scheme()+"://"+c.Host())
+	})
+	client := New(t, app)
+
+	client.Get("/whoami").Expect(200).ExpectBody("127.0.0.1 http://bolt.test")
+	client.Get("/whoami").
+		WithHeader("X-Forwarded-For", "203.0.113.7").
+		WithHeader("X-Forwarded-Proto", "https").
+		WithHeader("X-Forwarded-Host", "example.com").
+		Expect(200).ExpectBody("203.0.113.7 https://example.com")
+}
+
+// TestClientRequestInfo tests the route, protocol and body sizes on Shockwave.
+func TestClientRequestInfo(t *testing.T) {
+	app := core.New()
+	var route, proto string
+	var in, out int64
+	app.Use(func(next core.Handler) core.Handler {
+		return func(c *core.Context) error {
+			err := next(c)
+			route, proto, in, out = c.Route(), c.Proto(), c.ContentLength(), c.BytesWritten()
+			return err
+		}
+	})
+	app.Post("/echo/:id", func(c *core.Context) error {
+		return c.Text(200, "id="+c.Param("id"))
+	})
+	client := New(t, app)
+
+	client.Post("/echo/42").WithBody("text/plain", []byte("hello")).Expect(200).ExpectBody("id=42")
+	if route != "/echo/:id" || proto != "HTTP/1.1" || in != 5 || out != 5 {
+		t.Errorf("unexpected request info %q %q %d %d", route, proto, in, out)
+	}
+}
+
+// recorder is a testing.TB recording failures.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// TestClientFailures tests that failed expectations are reported.
+func TestClientFailures(t *testing.T) {
+	client := New(t, newApp())
+	rec := &recorder{TB: t}
+
+	var u user
+	client.WithT(rec).Get("/text").Expect(201).
+		ExpectHeader("Content-Type", "application/json").
+		ExpectBody("bye").
+		ExpectBodyContains("bye").
+		ExpectCookie("session", "").
+		JSONBody(&u)
+
+	want := []string{
+		"GET /text: expected status 201, got 200",
+		"GET /text: expected Content-Type",
+		`GET /text: expected body "bye"`,
+		`GET /text: expected body containing "bye"`,
+		"GET /text: expected cookie session",
+		"GET /text: decoding JSON body",
+	}
+	if len(rec.errors) != len(want) {
+		t.Fatalf("expected %d failures, got %q", len(want), rec.errors)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(rec.errors[i], prefix) {
+			t.Errorf("expected failure %q, got %q", prefix, rec.errors[i])
+		}
+	}
+}
@@ -2,15 +2,20 @@ package core
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"reflect"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/yourusername/bolt/shockwave"
+	"github.com/yourusername/shockwave/pkg/shockwave/http11"
 )
 
 // App is the main Bolt application.
@@ -34,9 +39,22 @@ type App struct {
 	contextPool  *ContextPool
 	config       Config
 	middleware   []Middleware
+	routes       []*RouteInfo          // Registered routes (re-composed when middleware changes)
+	namedRoutes  map[string]*RouteInfo // Route name → route (see ChainLink.Name)
 	errorHandler ErrorHandler
+	fastErrors   bool // DefaultErrorHandler in use: 404/405 send pre-compiled bodies
 	server       *shockwave.Server
 	serverMu     sync.RWMutex // Protects server field from concurrent access
+
+	cookieKeys     *cookieKeyring // Derived from Config.CookieKeys (nil if unset)
+	trustedProxies []netip.Prefix // Parsed Config.TrustedProxies (see Context.RealIP)
+	codecs         *codecRegistry // Response encoders and request decoders (see RegisterEncoder)
+	metrics        *httpMetrics   // Request metrics (see Metrics), nil until enabled
+
+	// Application lifetime context (parent of every request context)
+	// Cancelled by Shutdown or when Config.ShutdownContext is done
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
 }
 
 // New creates a new Bolt application with default configuration.
@@ -50,6 +68,15 @@ func NewWithConfig(config Config) *App {
 		config.ErrorHandler = DefaultErrorHandler
 	}
 
+	cookieKeys, err := newCookieKeyring(config.CookieKeys)
+	if err != nil {
+		panic("bolt: " + err.Error())
+	}
+	trustedProxies, err := parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		panic("bolt: " + err.Error())
+	}
+
 	// Create context pool
 	contextPool := NewContextPool()
 
@@ -67,18 +94,32 @@ func NewWithConfig(config Config) *App {
 		router = NewRouter()
 	}
 
+	// Application lifetime context (request contexts are cancelled with it)
+	parent := config.ShutdownContext
+	if parent == nil {
+		parent = context.Background()
+	}
+	baseCtx, cancelBase := context.WithCancel(parent)
+
 	return &App{
-		router:       router,
-		contextPool:  contextPool,
-		config:       config,
-		middleware:   make([]Middleware, 0),
-		errorHandler: config.ErrorHandler,
+		router:         router,
+		contextPool:    contextPool,
+		config:         config,
+		middleware:     make([]Middleware, 0),
+		errorHandler:   config.ErrorHandler,
+		fastErrors:     reflect.ValueOf(config.ErrorHandler).Pointer() == reflect.ValueOf(DefaultErrorHandler).Pointer(),
+		cookieKeys:     cookieKeys,
+		trustedProxies: trustedProxies,
+		codecs:         newCodecRegistry(),
+		baseCtx:        baseCtx,
+		cancelBase:     cancelBase,
 	}
 }
 
 // Use adds global middleware to the application.
 //
-// Middleware is executed in the order it's registered.
+// Middleware is executed in the order it's registered. Global middleware
+// applies to every route, including routes registered before Use was called.
 //
 // Example:
 //
@@ -87,6 +128,11 @@ func NewWithConfig(config Config) *App {
 //	app.Use(Recovery())
 func (app *App) Use(middleware ...Middleware) {
 	app.middleware = append(app.middleware, middleware...)
+
+	// Re-compose already registered routes so registration order doesn't matter
+	for _, route := range app.routes {
+		app.register(route)
+	}
 }
 
 // Get registers a GET route.
@@ -145,39 +191,83 @@ func (app *App) Options(path string, handler Handler) *ChainLink {
 }
 
 // NOTE: Generic methods are not supported in Go as methods cannot have type parameters
-// independent of the receiver type. The generic API would need to be implemented as
-// standalone functions or using a different pattern (e.g., builder pattern).
-//
-// For now, users can use the Data[T] types manually with the standard API:
+// independent of the receiver type. Typed handlers are registered with the standalone
+// functions GetT, PostT, PutT, PatchT and DeleteT instead (see generics_routes.go).
 //
 // Example:
-//	app.Get("/users/:id", func(c *bolt.Context) error {
-//	    data := bolt.OK(user)  // Returns Data[User]
-//	    return c.sendData(data)
-//	})
 //
-// TODO: Implement generic API using standalone functions or builder pattern
+//	bolt.GetT(app, "/users/:id", func(c *bolt.Context) bolt.Data[User] {
+//	    return bolt.OK(user)
+//	})
 
 // addRoute registers a route with the router.
 func (app *App) addRoute(method HTTPMethod, path string, handler Handler) *ChainLink {
+	return app.addGroupRoute(nil, method, path, handler)
+}
+
+// addGroupRoute registers a route owned by the given group (nil for top-level routes).
+func (app *App) addGroupRoute(group *Group, method HTTPMethod, path string, handler Handler) *ChainLink {
+	route := &RouteInfo{
+		Method:  method,
+		Path:    path,
+		handler: handler,
+		group:   group,
+	}
+	app.addRouteInfo(route)
+	app.register(route)
+
+	// Return chain link for fluent API
+	return &ChainLink{
+		app:       app,
+		lastRoute: route,
+	}
+}
+
+// register composes the route's middleware chain and (re-)registers it with the router.
+//
+// Composition order (outermost first):
+//   - Route middleware added via ChainLink.Use (last Use call outermost)
+//   - Global middleware (App.Use)
+//   - Group middleware (outer group first)
+//   - Route handler
+//
+// The outermost handler also records the route pattern (Context.Route).
+// Middleware is baked into the handler here so requests pay no extra indirection.
+func (app *App) register(route *RouteInfo) {
+	finalHandler := route.handler
+
+	// Wrap handler with group middleware (innermost group first)
+	for g := route.group; g != nil; g = g.parent {
+		for i := len(g.middleware) - 1; i >= 0; i-- {
+			finalHandler = g.middleware[i](finalHandler)
+		}
+	}
+
 	// Wrap handler with global middleware
-	finalHandler := handler
 	for i := len(app.middleware) - 1; i >= 0; i-- {
 		finalHandler = app.middleware[i](finalHandler)
 	}
 
-	// Register with router
-	app.router.Add(method, path, finalHandler)
+	// Wrap handler with route-specific middleware
+	for i := len(route.middleware) - 1; i >= 0; i-- {
+		finalHandler = route.middleware[i](finalHandler)
+	}
 
-	// Return chain link for fluent API
-	return &ChainLink{
-		app: app,
-		lastRoute: &RouteInfo{
-			Method:  method,
-			Path:    path,
-			Handler: finalHandler,
-		},
+	// Record the route pattern before any middleware runs (see Context.Route)
+	pattern, handler := route.Path, finalHandler
+	finalHandler = func(c *Context) error {
+		c.route = pattern
+		return handler(c)
 	}
+
+	route.Handler = finalHandler
+	route.Middleware = len(app.middleware) + len(route.middleware)
+	for g := route.group; g != nil; g = g.parent {
+		route.Middleware += len(g.middleware)
+	}
+
+	// Register with router (overwrites any previous registration)
+	app.router.Add(route.Method, route.Path, finalHandler)
 }
 
 // Listen starts the HTTP server on the specified address.
@@ -267,8 +357,12 @@ func (app *App) Run(addr string) error {
 
 // Shutdown gracefully shuts down the server.
 //
-// It waits for active connections to finish (up to context deadline).
+// It cancels the context of every in-flight request (see Context.Context),
+// so long-running handlers return promptly, then waits for active
+// connections to finish (up to context deadline).
 func (app *App) Shutdown(ctx context.Context) error {
+	app.cancelBase()
+
 	app.serverMu.RLock()
 	srv := app.server
 	app.serverMu.RUnlock()
@@ -298,6 +392,7 @@ func (app *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Map http.Request to Bolt Context (ZERO-ALLOC: unsafe string→[]byte)
 	ctx.httpReq = r
 	ctx.httpRes = w
+	ctx.app = app
 	// SAFE: Read-only references, valid for request lifetime
 	ctx.methodBytes = stringToBytes(r.Method)
 	ctx.pathBytes = stringToBytes(r.URL.Path)
@@ -334,22 +429,76 @@ func (app *App) handleShockwaveRequest(res *shockwave.ResponseWriter, req *shock
 	// Direct pointer assignment - no allocations
 	ctx.shockwaveReq = req
 	ctx.shockwaveRes = res
-	ctx.methodBytes = req.MethodBytes()  // Zero-copy reference to Shockwave buffer
-	ctx.pathBytes = req.PathBytes()      // Zero-copy reference to Shockwave buffer
-	ctx.queryBytes = req.QueryBytes()    // Zero-copy reference to Shockwave buffer
+	ctx.app = app                       // Pointer copy - config and lifetime context
+	ctx.methodBytes = req.MethodBytes() // Zero-copy reference to Shockwave buffer
+	ctx.pathBytes = req.PathBytes()     // Zero-copy reference to Shockwave buffer
+	ctx.queryBytes = req.QueryBytes()   // Zero-copy reference to Shockwave buffer
 
 	// Route and execute handler
 	err := app.router.ServeHTTP(ctx)
 
 	// ✅ FAST PATH: Handle 404 directly (most common error)
-	if err == ErrNotFound {
+	if err == ErrNotFound && app.fastErrors {
 		// Use pre-compiled 404 response (0 allocs)
 		_ = ctx.JSONNotFound()
 		return
 	}
 
+	// ✅ FAST PATH: 405 (router already set the Allow header)
+	if err == ErrMethodNotAllowed && app.fastErrors {
+		_ = ctx.JSONMethodNotAllowed()
+		return
+	}
+
 	// Slow path: Other errors
 	if err != nil {
 		app.errorHandler(ctx, err)
 	}
+
+	// No body sent (handler returned nil without writing): frame the empty
+	// response, or keep-alive clients wait for the connection to close
+	if !res.HeaderWritten() && !res.Hijacked() {
+		if status := res.Status(); status >= 200 && status != 204 && status != 304 &&
+			len(res.Header().Get(headerContentLength)) == 0 && len(res.Header().Get(headerTransferEncoding)) == 0 {
+			_ = res.Header().Set(headerContentLength, zeroBytes)
+		}
+	}
 }
+
+// ServeConn serves HTTP/1.1 requests on a single connection with Shockwave,
+// as Listen does for each accepted connection: keep-alive, pipelining and
+// the same request handling. It returns when the client closes the
+// connection or sends "Connection: close", and closes conn.
+//
+// Use it to serve connections from custom listeners or in-memory pipes
+// (see the bolttest package).
+//
+// Example:
+//
+//	for {
+//	    conn, err := listener.Accept()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    go app.ServeConn(conn)
+//	}
+func (app *App) ServeConn(conn net.Conn) error {
+	defer conn.Close()
+
+	conn11 := http11.NewConnection(conn, http11.DefaultConnectionConfig(), func(req *http11.Request, res *http11.ResponseWriter) error {
+		app.handleShockwaveRequest(res, req)
+		if req.Close {
+			return errConnectionClose
+		}
+		return nil
+	})
+	defer conn11.Close()
+
+	if err := conn11.Serve(); err != nil && err != errConnectionClose {
+		return err
+	}
+	return nil
+}
+
+// errConnectionClose ends ServeConn after a "Connection: close" request.
+var errConnectionClose = errors.New("bolt: connection close requested")
@@ -0,0 +1,533 @@
+package core
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+// Struct tags understood by the binders.
+const (
+	tagPath     = "path"     // URL parameter (c.Param)
+	tagQuery    = "query"    // Query parameter (c.Query)
+	tagHeader   = "header"   // Request header (c.GetHeader)
+	tagForm     = "form"     // urlencoded / multipart field
+	tagJSON     = "json"     // JSON body field (decoded by BindJSON)
+	tagValidate = "validate" // Validation rules (see Validate)
+)
+
+// Bind decodes the request into v based on the Content-Type header.
+//
+// Dispatch:
+//   - application/json, */*+json       → BindJSON (json tags)
+//   - application/x-www-form-urlencoded → BindForm (form tags)
+//   - multipart/form-data               → BindForm (form tags, files)
+//   - no Content-Type (e.g. GET)        → BindQuery (query tags)
+//   - any other registered media type   → its Decoder (see App.RegisterDecoder),
+//     e.g. application/xml, application/msgpack, application/cbor,
+//     application/x-protobuf
+//
+// Other content types return ErrUnsupportedMediaType (415).
+// Malformed input returns an error wrapping ErrBadRequest (400).
+//
+// Example:
+//
+//	type SearchRequest struct {
+//	    Term  string   `json:"term" form:"term" query:"term"`
+//	    Limit int      `json:"limit" form:"limit" query:"limit"`
+//	    Tags  []string `json:"tags" form:"tag" query:"tag"`
+//	}
+//
+//	var req SearchRequest
+//	if err := c.Bind(&req); err != nil {
+//	    return err
+//	}
+func (c *Context) Bind(v interface{}) error {
+	mediaType, _ := c.mediaType()
+	switch {
+	case mediaType == "":
+		return c.BindQuery(v)
+	case mediaType == mimeJSON || strings.HasSuffix(mediaType, "+json"):
+		return c.BindJSON(v)
+	case mediaType == mimeFormURLEncoded || mediaType == mimeMultipartForm:
+		return c.BindForm(v)
+	default:
+		dec := c.codecs().decoder(mediaType)
+		if dec == nil {
+			return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+		}
+		return c.decodeBody(dec, v)
+	}
+}
+
+// decodeBody decodes the request body with dec.
+func (c *Context) decodeBody(dec Decoder, v interface{}) error {
+	body := c.bodyReader()
+	if body == nil {
+		return ErrBadRequest
+	}
+	if err := dec.Decode(body, v); err != nil {
+		if errors.Is(err, ErrUnsupportedValue) {
+			return err
+		}
+		if exceeded(body) {
+			return ErrRequestTooLarge
+		}
+		return bodyError(err)
+	}
+	return nil
+}
+
+// BindForm populates v from urlencoded or multipart form values.
+//
+// Fields are matched by their `form` tag (or field name if untagged).
+// Fields of type *multipart.FileHeader or []*multipart.FileHeader
+// receive uploaded files.
+//
+// Example:
+//
+//	type Upload struct {
+//	    Title  string                `form:"title"`
+//	    Avatar *multipart.FileHeader `form:"avatar"`
+//	}
+func (c *Context) BindForm(v interface{}) error {
+	if err := c.parseForm(); err != nil {
+		return err
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if c.multipartForm != nil {
+		files = c.multipartForm.File
+	}
+	return bindValues(v, tagForm, c.form, files)
+}
+
+// BindQuery populates v from query parameters.
+//
+// Fields are matched by their `query` tag (or field name if untagged).
+//
+// Example:
+//
+//	type ListRequest struct {
+//	    Page  int    `query:"page"`
+//	    Order string `query:"order"`
+//	}
+func (c *Context) BindQuery(v interface{}) error {
+	// Copy query bytes: they reference a reused request buffer
+	query, err := url.ParseQuery(string(c.queryBytes))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+	return bindValues(v, tagQuery, query, nil)
+}
+
+// Bind creates a T from every part of the request and validates it.
+//
+// Sources, in order (later sources override earlier ones):
+//  1. Body, if the request has a Content-Type (see Context.Bind). Fields
+//     tagged only `path`, `query` or `header` are never taken from the body
+//  2. Query parameters (`query` tag)
+//  3. Request headers (`header` tag)
+//  4. URL parameters (`path` tag)
+//
+// Then the `validate` tag rules are checked (see Validate).
+//
+// Conversion and validation failures for all fields are collected into a
+// single *capacitor.ValidationError wrapping ErrBadRequest, so the error
+// handler answers 400 with the list of failing fields - the same error
+// shape the capacitor DAL uses.
+//
+// Example:
+//
+//	type UpdateUser struct {
+//	    ID     int64  `path:"id" validate:"required,min=1"`
+//	    Tenant string `header:"X-Tenant" validate:"required"`
+//	    DryRun bool   `query:"dry_run"`
+//	    Name   string `json:"name" validate:"required,min=2,max=64"`
+//	    Email  string `json:"email" validate:"omitempty,email"`
+//	}
+//
+//	app.Put("/users/:id", func(c *bolt.Context) error {
+//	    req, err := bolt.Bind[UpdateUser](c)
+//	    if err != nil {
+//	        return err // 400 with field errors
+//	    }
+//	    ...
+//	})
+func Bind[T any](c *Context) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Struct {
+		return v, fmt.Errorf("bolt: Bind requires a struct type, got %T", v)
+	}
+	info := structInfoOf(rv.Type())
+	verr := newValidationError(rv.Type())
+
+	// 1. Body (JSON or form), only if the client sent one
+	if mediaType, _ := c.mediaType(); mediaType != "" {
+		if err := c.Bind(&v); err != nil && !mergeValidationError(verr, err) {
+			return v, err
+		}
+
+		// Decoders match fields by Go name too: a body must not set a
+		// header (e.g. tenant) or path value when the request lacks it
+		for i := range info.fields {
+			if f := &info.fields[i]; f.requestOnly {
+				rv.FieldByIndex(f.index).SetZero()
+			}
+		}
+	}
+
+	// 2-4. Query, headers, URL parameters (explicit tags only)
+	var query url.Values
+	if len(c.queryBytes) > 0 {
+		query, _ = url.ParseQuery(string(c.queryBytes))
+	}
+	info.bind(rv, tagQuery, false, func(name string) []string {
+		return query[name]
+	}, nil, verr)
+	info.bind(rv, tagHeader, false, func(name string) []string {
+		if h := c.GetHeader(name); h != "" {
+			return []string{h}
+		}
+		return nil
+	}, nil, verr)
+	info.bind(rv, tagPath, false, func(name string) []string {
+		if p := c.Param(name); p != "" {
+			// Clone: Param is backed by the request buffer
+			return []string{strings.Clone(p)}
+		}
+		return nil
+	}, nil, verr)
+
+	// Only validate once every value could be converted
+	if len(verr.Errors) == 0 {
+		info.validate(rv, "", verr)
+	}
+
+	if len(verr.Errors) > 0 {
+		return v, verr
+	}
+	return v, nil
+}
+
+// bindValues populates the struct pointed to by v from string values.
+//
+// Fields are looked up by the given tag name, falling back to the field name.
+// A tag of "-" skips the field. Embedded structs are bound recursively.
+func bindValues(v interface{}, tag string, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bolt: bind target must be a non-nil pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+
+	verr := newValidationError(rv.Type())
+	structInfoOf(rv.Type()).bind(rv, tag, true, func(name string) []string {
+		return values[name]
+	}, files, verr)
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// newValidationError creates an empty validation error for a struct type.
+//
+// It wraps ErrBadRequest so DefaultErrorHandler answers 400.
+func newValidationError(t reflect.Type) *capacitor.ValidationError {
+	return &capacitor.ValidationError{Type: t.Name(), Err: ErrBadRequest}
+}
+
+// mergeValidationError appends field errors from err into verr.
+//
+// Returns false if err is not a validation error.
+func mergeValidationError(verr *capacitor.ValidationError, err error) bool {
+	var other *capacitor.ValidationError
+	if !errors.As(err, &other) {
+		return false
+	}
+	verr.Errors = append(verr.Errors, other.Errors...)
+	return true
+}
+
+// structInfo is the cached binding/validation metadata of a struct type.
+type structInfo struct {
+	fields []fieldInfo
+	err    error // First invalid `validate` tag (see CheckTags)
+}
+
+// fieldInfo describes one bindable field (embedded struct fields are flattened).
+type fieldInfo struct {
+	index   []int             // Path for reflect.Value.FieldByIndex
+	name    string            // Go field name
+	tags    map[string]string // Source tag → name (options stripped)
+	display string            // Name used in error messages
+	rules   []validationRule  // Parsed `validate` tag
+	nested  reflect.Type      // Struct type validated recursively (nil if none)
+
+	// requestOnly fields are tagged path, query or header but not json or
+	// form: Bind[T] doesn't take them from the body
+	requestOnly bool
+}
+
+// structInfoCache maps reflect.Type → *structInfo.
+var structInfoCache sync.Map
+
+// structInfoOf returns the (cached) metadata for struct type t.
+//
+// Invalid `validate` tags are programming errors and panic, like
+// regexp.MustCompile (see CheckTags to catch them at startup).
+//
+// Performance: Reflection walk once per type, then a map lookup
+func structInfoOf(t reflect.Type) *structInfo {
+	info := loadStructInfo(t)
+	if info.err != nil {
+		panic(info.err.Error())
+	}
+	return info
+}
+
+// loadStructInfo returns the (cached) metadata for struct type t, which
+// records invalid tags instead of panicking.
+func loadStructInfo(t reflect.Type) *structInfo {
+	if info, ok := structInfoCache.Load(t); ok {
+		return info.(*structInfo)
+	}
+
+	info := &structInfo{}
+	collectFields(t, nil, info)
+	actual, _ := structInfoCache.LoadOrStore(t, info)
+	return actual.(*structInfo)
+}
+
+// collectFields appends the exported fields of t to info, flattening embedded structs.
+func collectFields(t reflect.Type, index []int, info *structInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		tags := make(map[string]string)
+		for _, tag := range [...]string{tagPath, tagQuery, tagHeader, tagForm, tagJSON} {
+			if value, ok := field.Tag.Lookup(tag); ok {
+				name, _, _ := strings.Cut(value, ",")
+				tags[tag] = name
+			}
+		}
+
+		// Embedded struct without explicit tags: promote its fields
+		if field.Anonymous && len(tags) == 0 && field.Type.Kind() == reflect.Struct {
+			collectFields(field.Type, fieldIndex, info)
+			continue
+		}
+
+		rules, err := parseRules(field, field.Tag.Get(tagValidate))
+		if err != nil && info.err == nil {
+			info.err = err
+		}
+		f := fieldInfo{
+			index:   fieldIndex,
+			name:    field.Name,
+			tags:    tags,
+			display: displayName(field.Name, tags),
+			rules:   rules,
+		}
+
+		_, json := tags[tagJSON]
+		_, form := tags[tagForm]
+		f.requestOnly = len(tags) > 0 && !json && !form
+
+		// Resolved lazily: the type may refer to itself
+		if ft := indirectType(field.Type); ft.Kind() == reflect.Struct && !isScalarStruct(ft) {
+			f.nested = ft
+		}
+		info.fields = append(info.fields, f)
+	}
+}
+
+// displayName picks the name clients know a field by.
+func displayName(goName string, tags map[string]string) string {
+	for _, tag := range [...]string{tagPath, tagQuery, tagHeader, tagForm, tagJSON} {
+		if name := tags[tag]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return goName
+}
+
+// bind assigns values from one source to the fields tagged for it.
+//
+// lookup returns the raw values for a source name. If fallback is true,
+// untagged fields are looked up by Go field name. Conversion failures are
+// collected into verr.
+func (info *structInfo) bind(rv reflect.Value, tag string, fallback bool, lookup func(string) []string, files map[string][]*multipart.FileHeader, verr *capacitor.ValidationError) {
+	for i := range info.fields {
+		f := &info.fields[i]
+
+		name, ok := f.tags[tag]
+		if !ok {
+			if !fallback {
+				continue
+			}
+			name = f.name
+		}
+		if name == "-" || name == "" {
+			continue
+		}
+		fv := rv.FieldByIndex(f.index)
+
+		// Uploaded files
+		switch fv.Type() {
+		case fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs[0]))
+			}
+			continue
+		case fileHeaderSliceType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs))
+			}
+			continue
+		}
+
+		vals := lookup(name)
+		if len(vals) == 0 {
+			continue
+		}
+		if err := setField(fv, vals); err != nil {
+			verr.AddFieldError(name, conversionMessage(fv.Type()))
+		}
+	}
+}
+
+var (
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeaderSliceType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// indirectType strips pointer and slice wrappers from t.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isScalarStruct reports whether a struct type is decoded from a single value (time.Time, ...).
+func isScalarStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// conversionMessage describes the expected format of a field that failed to convert.
+func conversionMessage(t reflect.Type) string {
+	t = indirectType(t)
+	switch {
+	case t == durationType:
+		return "must be a valid duration"
+	case t == timeType:
+		return "must be a valid RFC 3339 time"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "must be a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "must be an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "must be a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "must be a number"
+	default:
+		return "has an invalid format"
+	}
+}
+
+// setField assigns string values to a field, converting to its type.
+//
+// Slices receive every value; other kinds receive the first one.
+func setField(fv reflect.Value, vals []string) error {
+	// Types with custom text decoding (time.Time, net.IP, ...)
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(vals[0]))
+	}
+
+	switch fv.Kind() {
+	case reflect.Pointer:
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setField(fv.Elem(), vals)
+
+	case reflect.Slice:
+		slice := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, s := range vals {
+			if err := setField(slice.Index(i), []string{s}); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setScalar(fv, vals[0])
+}
+
+// setScalar converts a single string into a scalar field.
+func setScalar(fv reflect.Value, s string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+type bindTestRequest struct {
+	Name    string        `json:"name" form:"name" query:"name"`
+	Age     int           `json:"age" form:"age" query:"age"`
+	Tags    []string      `json:"tags" form:"tag" query:"tag"`
+	Active  *bool         `json:"active" form:"active" query:"active"`
+	Timeout time.Duration `json:"-" form:"timeout" query:"timeout"`
+	Ignored string        `json:"-" form:"-" query:"-"`
+}
+
+// TestContextBind tests Content-Type dispatch.
+func TestContextBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"json", "POST", "/bind", "application/json", `{"name":"bob","age":30,"tags":["a","b"],"active":true}`, 200},
+		{"json suffix", "POST", "/bind", "application/vnd.api+json; charset=utf-8", `{"name":"bob","age":30,"tags":["a","b"],"active":true}`, 200},
+		{"form", "POST", "/bind", "application/x-www-form-urlencoded", "name=bob&age=30&tag=a&tag=b&active=true&timeout=1s&Ignored=x", 200},
+		{"query", "GET", "/bind?name=bob&age=30&tag=a&tag=b&active=true&timeout=1s", "", "", 200},
+		{"invalid number", "POST", "/bind", "application/x-www-form-urlencoded", "name=bob&age=thirty", 400},
+		{"invalid json", "POST", "/bind", "application/json", `{"name":`, 400},
+		{"unsupported", "POST", "/bind", "text/csv", "name\nbob", 415},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			handler := func(c *Context) error {
+				var req bindTestRequest
+				if err := c.Bind(&req); err != nil {
+					return err
+				}
+				if req.Name != "bob" || req.Age != 30 || len(req.Tags) != 2 || req.Active == nil || !*req.Active {
+					t.Errorf("unexpected bind result: %+v", req)
+				}
+				if req.Ignored != "" {
+					t.Error("expected field tagged \"-\" to be skipped")
+				}
+				if tt.name != "json" && tt.name != "json suffix" && req.Timeout != time.Second {
+					t.Errorf("expected timeout 1s, got %v", req.Timeout)
+				}
+				return c.JSON(200, req)
+			}
+			app.Get("/bind", handler)
+			app.Post("/bind", handler)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestContextBindFormFiles tests binding uploaded files to FileHeader fields.
+func TestContextBindFormFiles(t *testing.T) {
+	type upload struct {
+		Title  string                  `form:"title"`
+		Photo  *multipart.FileHeader   `form:"photo"`
+		Photos []*multipart.FileHeader `form:"photo"`
+	}
+
+	app := New()
+	app.Post("/upload", func(c *Context) error {
+		var u upload
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		if u.Title != "holiday" {
+			t.Errorf("expected title=holiday, got %q", u.Title)
+		}
+		if u.Photo == nil || u.Photo.Filename != "beach.jpg" {
+			t.Errorf("expected photo file header, got %+v", u.Photo)
+		}
+		if len(u.Photos) != 1 {
+			t.Errorf("expected 1 photo, got %d", len(u.Photos))
+		}
+		return c.NoContent()
+	})
+
+	body, contentType := newMultipartBody(t, "data")
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+}
+
+// TestBindValuesInvalidTarget tests that non-struct targets are rejected.
+func TestBindValuesInvalidTarget(t *testing.T) {
+	var s string
+	for _, v := range []interface{}{nil, s, &s, (*bindTestRequest)(nil)} {
+		if err := bindValues(v, "query", nil, nil); err == nil {
+			t.Errorf("expected error for target %T", v)
+		}
+	}
+
+	var req bindTestRequest
+	err := bindValues(&req, "query", map[string][]string{"age": {"x"}}, nil)
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+type bindTUpdateUser struct {
+	ID     int64  `path:"id" validate:"required,min=1"`
+	Tenant string `header:"X-Tenant" validate:"required"`
+	DryRun bool   `query:"dry_run"`
+	Name   string `json:"name" validate:"required,min=2,max=64"`
+	Email  string `json:"email" validate:"omitempty,email"`
+}
+
+// TestBindGeneric tests Bind[T] across path, query, header and body sources.
+func TestBindGeneric(t *testing.T) {
+	app := New()
+	var got bindTUpdateUser
+	app.Put("/users/:id", func(c *Context) error {
+		req, err := Bind[bindTUpdateUser](c)
+		if err != nil {
+			return err
+		}
+		got = req
+		return c.NoContent()
+	})
+
+	req := httptest.NewRequest("PUT", "/users/42?dry_run=true", strings.NewReader(`{"name":"Ada","email":"ada@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant", "acme")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	expected := bindTUpdateUser{ID: 42, Tenant: "acme", DryRun: true, Name: "Ada", Email: "ada@example.com"}
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+// TestBindGenericBodySpoofing tests that the body can't set header, path
+// or query fields.
+func TestBindGenericBodySpoofing(t *testing.T) {
+	type request struct {
+		Tenant string `header:"X-Tenant" validate:"required"`
+		ID     int64  `path:"id"`
+		DryRun bool   `query:"dry_run"`
+		Name   string `json:"name"`
+		Both   string `json:"both" query:"both"`
+	}
+
+	app := New()
+	var got request
+	app.Put("/users/:id", func(c *Context) error {
+		req, err := Bind[request](c)
+		got = req
+		return err
+	})
+
+	for _, tt := range []struct {
+		contentType, body string
+	}{
+		{"application/json", `{"name":"a","tenant":"evil","id":7,"dryrun":true,"both":"body"}`},
+		{"application/x-www-form-urlencoded", "Name=a&Tenant=evil&ID=7&DryRun=true&Both=body"},
+	} {
+		got = request{}
+		req := httptest.NewRequest("PUT", "/users/42", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 400 || !strings.Contains(w.Body.String(), "X-Tenant") {
+			t.Errorf("%s: expected 400 for the missing header, got %d: %s", tt.contentType, w.Code, w.Body.String())
+		}
+		if got.Tenant != "" || got.ID != 42 || got.DryRun || got.Both != "body" {
+			t.Errorf("%s: body set request-only fields: %+v", tt.contentType, got)
+		}
+	}
+}
+
+// TestBindGenericErrors tests that every failing field is reported.
+func TestBindGenericErrors(t *testing.T) {
+	app := New()
+	app.Put("/users/:id", func(c *Context) error {
+		_, err := Bind[bindTUpdateUser](c)
+		return err
+	})
+
+	req := httptest.NewRequest("PUT", "/users/-3", strings.NewReader(`{"name":"A","email":"not-an-email"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	var body struct {
+		Error  string `json:"error"`
+		Fields []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	expected := map[string]string{
+		"id":       "must be at least 1",
+		"X-Tenant": "is required",
+		"name":     "must be at least 2 characters",
+		"email":    "must be a valid email address",
+	}
+	if len(body.Fields) != len(expected) {
+		t.Fatalf("expected %d field errors, got %+v", len(expected), body.Fields)
+	}
+	for _, f := range body.Fields {
+		if expected[f.Field] != f.Message {
+			t.Errorf("field %s: expected %q, got %q", f.Field, expected[f.Field], f.Message)
+		}
+	}
+}
+
+// TestBindGenericConversionError tests that unconvertible values are field errors.
+func TestBindGenericConversionError(t *testing.T) {
+	c := &Context{}
+	c.setParam("id", "abc")
+	c.SetRequestHeader("X-Tenant", "acme")
+
+	_, err := Bind[bindTUpdateUser](c)
+
+	var verr *capacitor.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *capacitor.ValidationError, got %v", err)
+	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Error("expected error to wrap ErrBadRequest")
+	}
+	if len(verr.Errors) != 1 || verr.Errors[0].Field != "id" || verr.Errors[0].Message != "must be an integer" {
+		t.Errorf("unexpected field errors: %+v", verr.Errors)
+	}
+}
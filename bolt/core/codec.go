@@ -0,0 +1,324 @@
+package core
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	json "github.com/goccy/go-json"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+)
+
+// Response encoders and request decoders by media type.
+//
+// Every App starts with the built-in codecs below; RegisterEncoder and
+// RegisterDecoder add formats or replace them (e.g. a different JSON
+// library). Negotiate picks an encoder from the Accept header, Bind picks a
+// decoder from the Content-Type header, and c.JSON / c.BindJSON use the
+// registered application/json codec.
+//
+// Built-in (in server preference order):
+//   - application/json            goccy/go-json
+//   - application/xml             encoding/xml
+//   - application/msgpack         MessagePack (ugorji/go/codec), also application/x-msgpack
+//   - application/cbor            CBOR (ugorji/go/codec)
+//   - application/x-protobuf      proto.Message values only, also application/protobuf
+//   - text/plain                  string, []byte, fmt.Stringer and error values only
+
+// Encoder encodes response values for a media type.
+type Encoder interface {
+	// Encode writes v to w. Return ErrUnsupportedValue if v can't be
+	// represented in this format, so Negotiate tries the next acceptable one.
+	Encode(w io.Writer, v any) error
+}
+
+// Decoder decodes request bodies for a media type.
+type Decoder interface {
+	// Decode reads r into v (a pointer).
+	Decode(r io.Reader, v any) error
+}
+
+// EncoderFunc adapts a function to Encoder.
+type EncoderFunc func(w io.Writer, v any) error
+
+// Encode calls f(w, v).
+func (f EncoderFunc) Encode(w io.Writer, v any) error { return f(w, v) }
+
+// DecoderFunc adapts a function to Decoder.
+type DecoderFunc func(r io.Reader, v any) error
+
+// Decode calls f(r, v).
+func (f DecoderFunc) Decode(r io.Reader, v any) error { return f(r, v) }
+
+// ErrUnsupportedValue is returned by encoders and decoders for values their
+// format can't represent (e.g. a struct for text/plain, a non-protobuf
+// message for application/x-protobuf).
+var ErrUnsupportedValue = errors.New("value not supported by codec")
+
+// RegisterEncoder registers the response encoder for a media type, replacing
+// an existing one. contentType is sent as the Content-Type header and may
+// carry parameters ("text/csv; charset=utf-8"); matching ignores them.
+//
+// Encoders registered first are preferred when the client accepts several
+// formats equally. Register at startup, before serving requests.
+//
+// Example:
+//
+//	app.RegisterEncoder("text/csv; charset=utf-8", bolt.EncoderFunc(func(w io.Writer, v any) error {
+//	    rows, ok := v.([][]string)
+//	    if !ok {
+//	        return bolt.ErrUnsupportedValue
+//	    }
+//	    return csv.NewWriter(w).WriteAll(rows)
+//	}))
+func (app *App) RegisterEncoder(contentType string, enc Encoder) {
+	app.codecs.registerEncoder(contentType, enc)
+}
+
+// RegisterDecoder registers the request body decoder used by Bind for a
+// media type, replacing an existing one.
+//
+// Example:
+//
+//	// Use the standard library for JSON bodies
+//	app.RegisterDecoder("application/json", bolt.DecoderFunc(func(r io.Reader, v any) error {
+//	    return stdjson.NewDecoder(r).Decode(v)
+//	}))
+func (app *App) RegisterDecoder(mediaType string, dec Decoder) {
+	app.codecs.decoders[mediaTypeKey(mediaType)] = dec
+}
+
+// codecRegistry holds an App's encoders and decoders.
+type codecRegistry struct {
+	encoders []registeredEncoder // Server preference order
+	decoders map[string]Decoder  // Media type → decoder
+}
+
+// registeredEncoder is an encoder and the media type it produces.
+type registeredEncoder struct {
+	mediaType   string // Lowercase type/subtype, used for matching
+	contentType string // Content-Type header value
+	enc         Encoder
+}
+
+// Built-in codecs.
+var (
+	msgpackHandle = func() *codec.MsgpackHandle {
+		h := &codec.MsgpackHandle{}
+		h.WriteExt = true    // str8/bin types (MessagePack 2013+ spec)
+		h.RawToString = true // Decode raw bytes into string, not []byte
+		h.MapType = reflect.TypeOf(map[string]any(nil))
+		return h
+	}()
+	cborHandle = func() *codec.CborHandle {
+		h := &codec.CborHandle{}
+		h.MapType = reflect.TypeOf(map[string]any(nil))
+		return h
+	}()
+)
+
+// goccyJSON is the built-in JSON codec. c.JSON and c.BindJSON recognize it
+// and keep their pooled goccy/go-json fast path.
+type goccyJSON struct{}
+
+func (goccyJSON) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (goccyJSON) Decode(r io.Reader, v any) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// defaultCodecs is used by contexts without an App (unit tests).
+var defaultCodecs = newCodecRegistry()
+
+// newCodecRegistry returns a registry with the built-in codecs.
+func newCodecRegistry() *codecRegistry {
+	r := &codecRegistry{decoders: make(map[string]Decoder)}
+
+	r.registerEncoder(mimeJSON, goccyJSON{})
+	r.registerEncoder("application/xml; charset=utf-8", EncoderFunc(encodeXML))
+	r.registerEncoder("application/msgpack", ugorjiEncoder(msgpackHandle))
+	r.registerEncoder("application/x-msgpack", ugorjiEncoder(msgpackHandle))
+	r.registerEncoder("application/cbor", ugorjiEncoder(cborHandle))
+	r.registerEncoder("application/x-protobuf", EncoderFunc(encodeProtobuf))
+	r.registerEncoder("application/protobuf", EncoderFunc(encodeProtobuf))
+	r.registerEncoder("text/plain; charset=utf-8", EncoderFunc(encodeText))
+
+	r.decoders[mimeJSON] = goccyJSON{}
+	r.decoders["application/xml"] = DecoderFunc(decodeXML)
+	r.decoders["text/xml"] = DecoderFunc(decodeXML)
+	r.decoders["application/msgpack"] = ugorjiDecoder(msgpackHandle)
+	r.decoders["application/x-msgpack"] = ugorjiDecoder(msgpackHandle)
+	r.decoders["application/cbor"] = ugorjiDecoder(cborHandle)
+	r.decoders["application/x-protobuf"] = DecoderFunc(decodeProtobuf)
+	r.decoders["application/protobuf"] = DecoderFunc(decodeProtobuf)
+	return r
+}
+
+// registerEncoder adds or replaces the encoder for contentType.
+func (r *codecRegistry) registerEncoder(contentType string, enc Encoder) {
+	mediaType := mediaTypeKey(contentType)
+	for i := range r.encoders {
+		if r.encoders[i].mediaType == mediaType {
+			r.encoders[i] = registeredEncoder{mediaType, contentType, enc}
+			return
+		}
+	}
+	r.encoders = append(r.encoders, registeredEncoder{mediaType, contentType, enc})
+}
+
+// encoder returns the encoder registered for mediaType (nil if none).
+func (r *codecRegistry) encoder(mediaType string) Encoder {
+	for i := range r.encoders {
+		if r.encoders[i].mediaType == mediaType {
+			return r.encoders[i].enc
+		}
+	}
+	return nil
+}
+
+// decoder returns the decoder for a request media type (nil if none).
+// Structured syntax suffixes fall back to their base format
+// (application/problem+json → application/json).
+func (r *codecRegistry) decoder(mediaType string) Decoder {
+	if dec, ok := r.decoders[mediaType]; ok {
+		return dec
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		switch mediaType[i+1:] {
+		case "json":
+			return r.decoders[mimeJSON]
+		case "xml":
+			return r.decoders["application/xml"]
+		case "cbor":
+			return r.decoders["application/cbor"]
+		}
+	}
+	return nil
+}
+
+// codecs returns the registry of the owning App.
+func (c *Context) codecs() *codecRegistry {
+	if c.app != nil {
+		return c.app.codecs
+	}
+	return defaultCodecs
+}
+
+// jsonEncoder returns the application/json encoder if the App replaced the
+// built-in one (nil otherwise).
+func (c *Context) jsonEncoder() Encoder {
+	if c.app == nil {
+		return nil
+	}
+	if enc := c.app.codecs.encoder(mimeJSON); enc != nil {
+		if _, builtin := enc.(goccyJSON); !builtin {
+			return enc
+		}
+	}
+	return nil
+}
+
+// jsonDecoder returns the application/json decoder if the App replaced the
+// built-in one (nil otherwise).
+func (c *Context) jsonDecoder() Decoder {
+	if c.app == nil {
+		return nil
+	}
+	if dec, ok := c.app.codecs.decoders[mimeJSON]; ok {
+		if _, builtin := dec.(goccyJSON); !builtin {
+			return dec
+		}
+	}
+	return nil
+}
+
+// mediaTypeKey returns the lowercase type/subtype of a media type.
+func mediaTypeKey(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// encodeXML encodes v with encoding/xml.
+func encodeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	err := xml.NewEncoder(w).Encode(v)
+	var unsupported *xml.UnsupportedTypeError
+	if errors.As(err, &unsupported) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedValue, err)
+	}
+	return err
+}
+
+// decodeXML decodes an XML body.
+func decodeXML(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// encodeText writes string-like values as plain text.
+func encodeText(w io.Writer, v any) error {
+	var err error
+	switch v := v.(type) {
+	case string:
+		_, err = io.WriteString(w, v)
+	case []byte:
+		_, err = w.Write(v)
+	case fmt.Stringer:
+		_, err = io.WriteString(w, v.String())
+	case error:
+		_, err = io.WriteString(w, v.Error())
+	default:
+		return fmt.Errorf("%w: %T as text/plain", ErrUnsupportedValue, v)
+	}
+	return err
+}
+
+// encodeProtobuf encodes protobuf messages.
+func encodeProtobuf(w io.Writer, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T is not a proto.Message", ErrUnsupportedValue, v)
+	}
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// decodeProtobuf decodes a protobuf body into a proto.Message.
+func decodeProtobuf(r io.Reader, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T is not a proto.Message", ErrUnsupportedValue, v)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// ugorjiEncoder returns an encoder for a ugorji/go/codec format.
+func ugorjiEncoder(h codec.Handle) Encoder {
+	return EncoderFunc(func(w io.Writer, v any) error {
+		return codec.NewEncoder(w, h).Encode(v)
+	})
+}
+
+// ugorjiDecoder returns a decoder for a ugorji/go/codec format.
+func ugorjiDecoder(h codec.Handle) Decoder {
+	return DecoderFunc(func(r io.Reader, v any) error {
+		return codec.NewDecoder(r, h).Decode(v)
+	})
+}
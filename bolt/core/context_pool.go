@@ -1,6 +1,9 @@
 package core
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 // ContextPool manages a pool of Context objects for reuse.
 //
@@ -15,18 +18,26 @@ import "sync"
 //	defer pool.Release(ctx)
 //	// Use ctx...
 type ContextPool struct {
-	pool sync.Pool
+	pool      sync.Pool
+	allocated atomic.Uint64 // Contexts created by pool.New (see Stats)
+}
+
+// ContextPoolStats are the statistics of a ContextPool.
+//
+// Acquire and Release are not counted, to keep them off shared cache lines;
+// one context is acquired per request.
+type ContextPoolStats struct {
+	Allocated uint64 // Contexts allocated because the pool was empty
 }
 
 // NewContextPool creates a new context pool.
 func NewContextPool() *ContextPool {
-	return &ContextPool{
-		pool: sync.Pool{
-			New: func() interface{} {
-				return &Context{}
-			},
-		},
+	p := &ContextPool{}
+	p.pool.New = func() interface{} {
+		p.allocated.Add(1)
+		return &Context{}
 	}
+	return p
 }
 
 // Acquire retrieves a Context from the pool.
@@ -48,6 +59,14 @@ func (p *ContextPool) Release(ctx *Context) {
 	p.pool.Put(ctx)
 }
 
+// Stats returns the pool statistics.
+//
+// Allocated growing with the request count means contexts are not reused
+// (e.g. the GC cleared the pool, or bursts exceed the warmup size).
+func (p *ContextPool) Stats() ContextPoolStats {
+	return ContextPoolStats{Allocated: p.allocated.Load()}
+}
+
 // Warmup pre-allocates contexts to eliminate cold start allocations.
 //
 // This should be called during app initialization to pre-populate
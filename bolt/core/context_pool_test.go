@@ -239,3 +239,13 @@ func BenchmarkContextPoolReset(b *testing.B) {
 		ctx.Set("key", "value")
 	}
 }
+
+// TestContextPoolStats tests pool statistics.
+func TestContextPoolStats(t *testing.T) {
+	pool := NewContextPool()
+	pool.Warmup(10)
+
+	if allocated := pool.Stats().Allocated; allocated != 10 {
+		t.Errorf("expected 10 allocated contexts, got %d", allocated)
+	}
+}
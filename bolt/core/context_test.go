@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // TestContextParam tests parameter storage and retrieval.
@@ -595,3 +598,92 @@ func TestContextJSONBytes(t *testing.T) {
 		t.Error("expected response to be written")
 	}
 }
+
+// TestContextContext tests lazy creation and cancellation on request completion.
+func TestContextContext(t *testing.T) {
+	app := New()
+	var reqCtx context.Context
+	app.Get("/ctx", func(c *Context) error {
+		reqCtx = c.Context()
+		if c.Context() != reqCtx {
+			t.Error("expected Context() to return the same context")
+		}
+		if err := reqCtx.Err(); err != nil {
+			t.Errorf("expected live context in handler, got %v", err)
+		}
+		return c.NoContent()
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ctx", nil))
+
+	if reqCtx == nil {
+		t.Fatal("handler was not called")
+	}
+	if reqCtx.Err() == nil {
+		t.Error("expected context to be cancelled after the request completes")
+	}
+}
+
+// TestContextContextShutdown tests that App.Shutdown cancels in-flight request contexts.
+func TestContextContextShutdown(t *testing.T) {
+	app := New()
+	app.Get("/wait", func(c *Context) error {
+		ctx := c.Context()
+		if err := app.Shutdown(context.Background()); err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Error("expected request context to be cancelled by Shutdown")
+		}
+		return nil
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/wait", nil))
+}
+
+// TestContextContextHTTPRequest tests that the net/http request context is the parent.
+func TestContextContextHTTPRequest(t *testing.T) {
+	app := New()
+	app.Get("/parent", func(c *Context) error {
+		if c.Context().Value(testCtxKey{}) != "from-request" {
+			t.Error("expected value from http.Request context")
+		}
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/parent", nil)
+	req = req.WithContext(context.WithValue(req.Context(), testCtxKey{}, "from-request"))
+	app.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// TestContextWithContext tests replacing the request context.
+func TestContextWithContext(t *testing.T) {
+	c := &Context{}
+	base := c.Context()
+
+	ctx, cancel := context.WithCancel(context.WithValue(base, testCtxKey{}, "v"))
+	if c.WithContext(ctx) != c {
+		t.Error("expected WithContext to return the same Context")
+	}
+	if c.Context().Value(testCtxKey{}) != "v" {
+		t.Error("expected replaced context")
+	}
+
+	cancel()
+	if c.Context().Err() == nil {
+		t.Error("expected replaced context to be cancelled")
+	}
+	if base.Err() != nil {
+		t.Error("expected base context to stay live until reset")
+	}
+
+	c.FastReset()
+	if base.Err() == nil {
+		t.Error("expected base context to be cancelled on reset")
+	}
+}
+
+type testCtxKey struct{}
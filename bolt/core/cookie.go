@@ -0,0 +1,480 @@
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cookies (RFC 6265).
+//
+// Request cookies are looked up directly in the Cookie header bytes without
+// parsing them into a map. Signed and encrypted cookies use keys derived from
+// Config.CookieKeys: the first key signs/encrypts new cookies, every key is
+// tried when reading, so keys can be rotated without logging users out.
+
+// SameSite controls when browsers send a cookie with cross-site requests.
+type SameSite int
+
+// SameSite modes.
+const (
+	SameSiteDefaultMode SameSite = iota // No SameSite attribute (browser default, usually Lax)
+	SameSiteLaxMode                     // Sent with top-level navigations
+	SameSiteStrictMode                  // Never sent with cross-site requests
+	SameSiteNoneMode                    // Always sent (requires Secure)
+)
+
+// Cookie is an HTTP cookie sent with SetCookie.
+type Cookie struct {
+	Name  string
+	Value string
+
+	Path    string    // Default: the request path "directory"
+	Domain  string    // Default: the request host only
+	Expires time.Time // Zero means a session cookie (unless MaxAge is set)
+
+	// MaxAge in seconds: 0 omits the attribute, negative deletes the cookie
+	// (Max-Age=0), positive sets the lifetime
+	MaxAge int
+
+	Secure      bool     // Only sent over HTTPS
+	HttpOnly    bool     // Not accessible from JavaScript
+	SameSite    SameSite // Cross-site policy
+	Partitioned bool     // CHIPS: partitioned by top-level site (requires Secure)
+}
+
+// String returns the Set-Cookie header value, or "" if the name is invalid.
+// Invalid bytes in other attributes are dropped.
+func (ck *Cookie) String() string {
+	hc := http.Cookie{
+		Name:        ck.Name,
+		Value:       ck.Value,
+		Path:        ck.Path,
+		Domain:      ck.Domain,
+		Expires:     ck.Expires,
+		MaxAge:      ck.MaxAge,
+		Secure:      ck.Secure,
+		HttpOnly:    ck.HttpOnly,
+		Partitioned: ck.Partitioned,
+	}
+	switch ck.SameSite {
+	case SameSiteLaxMode:
+		hc.SameSite = http.SameSiteLaxMode
+	case SameSiteStrictMode:
+		hc.SameSite = http.SameSiteStrictMode
+	case SameSiteNoneMode:
+		hc.SameSite = http.SameSiteNoneMode
+	}
+	return hc.String()
+}
+
+var (
+	// ErrNoCookie is returned when the request has no cookie with the given name.
+	ErrNoCookie = errors.New("cookie not found")
+
+	// ErrInvalidCookie is returned when a signed or encrypted cookie fails
+	// verification (tampered, forged or signed with a retired key).
+	// It wraps ErrBadRequest, so returning it from a handler sends 400.
+	ErrInvalidCookie = fmt.Errorf("%w: invalid cookie", ErrBadRequest)
+
+	// errNoCookieKeys is returned by signed/encrypted cookie helpers when
+	// Config.CookieKeys is empty.
+	errNoCookieKeys = errors.New("bolt: signed and encrypted cookies need Config.CookieKeys")
+)
+
+const (
+	// minCookieKeyLen is the minimum length of a Config.CookieKeys entry.
+	minCookieKeyLen = 32
+
+	// maxCookieSize is the largest Set-Cookie value browsers reliably store.
+	maxCookieSize = 4096
+)
+
+// Cookie returns the value of the named request cookie ("" if absent).
+//
+// Example:
+//
+//	theme := c.Cookie("theme")
+//
+// Performance: 1 alloc/op (the returned string); see CookieBytes
+func (c *Context) Cookie(name string) string {
+	return string(c.CookieBytes(name))
+}
+
+// CookieBytes returns the value of the named request cookie as a zero-copy
+// byte slice (nil if absent). The slice is only valid during the request.
+//
+// Performance: 0 allocs/op (scans the Cookie header in place)
+func (c *Context) CookieBytes(name string) []byte {
+	// Standard http.Request (testing/compatibility)
+	if c.httpReq != nil {
+		for _, line := range c.httpReq.Header["Cookie"] {
+			if value, ok := findCookie(stringToBytes(line), name); ok {
+				return value
+			}
+		}
+		return nil
+	}
+
+	// Shockwave Request (production)
+	if c.shockwaveReq != nil {
+		value, _ := findCookie(c.shockwaveReq.Header.Get(headerCookie), name)
+		return value
+	}
+
+	// Test mode
+	value, _ := findCookie(stringToBytes(c.GetHeader("Cookie")), name)
+	return value
+}
+
+// HasCookie reports whether the request has the named cookie.
+func (c *Context) HasCookie(name string) bool {
+	return c.CookieBytes(name) != nil
+}
+
+// SetCookie adds a Set-Cookie header to the response.
+// Setting a cookie again (same name, path and domain) replaces the earlier
+// Set-Cookie header. Cookies with invalid names are ignored.
+//
+// Example:
+//
+//	c.SetCookie(&bolt.Cookie{
+//	    Name:     "theme",
+//	    Value:    "dark",
+//	    Path:     "/",
+//	    MaxAge:   365 * 24 * 3600,
+//	    SameSite: bolt.SameSiteLaxMode,
+//	})
+func (c *Context) SetCookie(cookie *Cookie) {
+	if v := cookie.String(); v != "" {
+		c.setCookieHeader(cookie, v)
+	}
+}
+
+// ClearCookie tells the client to delete the named cookie.
+// path and domain must match the ones the cookie was set with.
+func (c *Context) ClearCookie(name, path, domain string) {
+	c.SetCookie(&Cookie{Name: name, Path: path, Domain: domain, MaxAge: -1, Expires: time.Unix(0, 0)})
+}
+
+// SetSignedCookie sets a cookie whose value is signed with HMAC-SHA256.
+//
+// The value stays readable by the client but can't be modified: SignedCookie
+// rejects it if it was. The signature covers the cookie name, so a value
+// can't be moved to another cookie. Requires Config.CookieKeys.
+//
+// Example:
+//
+//	c.SetSignedCookie(&bolt.Cookie{Name: "user", Value: userID, Path: "/", HttpOnly: true})
+func (c *Context) SetSignedCookie(cookie *Cookie) error {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return err
+	}
+	signed := *cookie
+	signed.Value = keys.sign(cookie.Name, cookie.Value)
+	return c.setSecureCookie(&signed)
+}
+
+// SignedCookie returns the value of a cookie set with SetSignedCookie.
+//
+// Returns ErrNoCookie if the cookie is absent and ErrInvalidCookie if the
+// signature doesn't match any of Config.CookieKeys.
+func (c *Context) SignedCookie(name string) (string, error) {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return "", err
+	}
+	raw := c.CookieBytes(name)
+	if raw == nil {
+		return "", ErrNoCookie
+	}
+	return keys.verify(name, raw)
+}
+
+// SetEncryptedCookie sets a cookie whose value is encrypted and
+// authenticated with AES-256-GCM, so the client can neither read nor modify it.
+// Requires Config.CookieKeys.
+//
+// Example:
+//
+//	c.SetEncryptedCookie(&bolt.Cookie{Name: "prefs", Value: string(prefsJSON), Path: "/", Secure: true})
+func (c *Context) SetEncryptedCookie(cookie *Cookie) error {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return err
+	}
+	value, err := keys.encrypt(cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+	encrypted := *cookie
+	encrypted.Value = value
+	return c.setSecureCookie(&encrypted)
+}
+
+// EncryptedCookie returns the decrypted value of a cookie set with SetEncryptedCookie.
+//
+// Returns ErrNoCookie if the cookie is absent and ErrInvalidCookie if it
+// can't be decrypted with any of Config.CookieKeys.
+func (c *Context) EncryptedCookie(name string) (string, error) {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return "", err
+	}
+	raw := c.CookieBytes(name)
+	if raw == nil {
+		return "", ErrNoCookie
+	}
+	return keys.decrypt(name, raw)
+}
+
+// setSecureCookie sets a signed/encrypted cookie, rejecting values that
+// browsers would silently drop.
+func (c *Context) setSecureCookie(cookie *Cookie) error {
+	v := cookie.String()
+	if v == "" {
+		return fmt.Errorf("bolt: invalid cookie name %q", cookie.Name)
+	}
+	if len(v) > maxCookieSize {
+		return fmt.Errorf("bolt: cookie %q is %d bytes (browsers store up to %d)", cookie.Name, len(v), maxCookieSize)
+	}
+	c.setCookieHeader(cookie, v)
+	return nil
+}
+
+// cookieKeys returns the keyring of the owning App.
+func (c *Context) cookieKeys() (*cookieKeyring, error) {
+	if c.app == nil || c.app.cookieKeys == nil {
+		return nil, errNoCookieKeys
+	}
+	return c.app.cookieKeys, nil
+}
+
+// setCookieHeader adds a Set-Cookie header, replacing a previous one for the
+// same cookie (RFC 6265 Section 4.1.1: at most one per cookie-name).
+// Test mode keeps only the last Set-Cookie header.
+func (c *Context) setCookieHeader(cookie *Cookie, value string) {
+	sameCookie := func(line string) bool {
+		prev, err := http.ParseSetCookie(line)
+		return err == nil && prev.Name == cookie.Name && prev.Path == cookie.Path &&
+			strings.EqualFold(strings.TrimPrefix(prev.Domain, "."), strings.TrimPrefix(cookie.Domain, "."))
+	}
+
+	// Standard http.ResponseWriter (testing/compatibility)
+	if c.httpRes != nil {
+		header := c.httpRes.Header()
+		for i, line := range header["Set-Cookie"] {
+			if sameCookie(line) {
+				header["Set-Cookie"][i] = value
+				return
+			}
+		}
+		header.Add("Set-Cookie", value)
+		return
+	}
+
+	// Shockwave ResponseWriter (production)
+	if c.shockwaveRes != nil {
+		header := c.shockwaveRes.Header()
+		var lines []string
+		replaced := false
+		header.VisitAll(func(name, v []byte) bool {
+			if bytesToString(name) == "Set-Cookie" {
+				if line := string(v); !replaced && sameCookie(line) {
+					lines = append(lines, value)
+					replaced = true
+				} else {
+					lines = append(lines, line)
+				}
+			}
+			return true
+		})
+		if !replaced {
+			_ = header.Add(headerSetCookie, []byte(value))
+			return
+		}
+		header.Del(headerSetCookie)
+		for _, line := range lines {
+			_ = header.Add(headerSetCookie, []byte(line))
+		}
+		return
+	}
+
+	// Test mode
+	c.SetHeader("Set-Cookie", value)
+}
+
+// findCookie returns the value of the named cookie in a Cookie header
+// ("a=1; b=2"). Surrounding double quotes are removed from the value.
+//
+// Performance: 0 allocs/op
+func findCookie(header []byte, name string) ([]byte, bool) {
+	for len(header) > 0 {
+		var pair []byte
+		if i := indexByte(header, ';'); i >= 0 {
+			pair, header = header[:i], header[i+1:]
+		} else {
+			pair, header = header, nil
+		}
+
+		pair = trimSpace(pair)
+		eq := indexByte(pair, '=')
+		if eq < 0 || bytesToString(trimSpace(pair[:eq])) != name {
+			continue
+		}
+		value := trimSpace(pair[eq+1:])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// indexByte returns the index of the first b in s, or -1.
+func indexByte(s []byte, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// trimSpace removes leading and trailing spaces and tabs.
+func trimSpace(s []byte) []byte {
+	for len(s) > 0 && (s[0] == ' ' || s[0] == '\t') {
+		s = s[1:]
+	}
+	for len(s) > 0 && (s[len(s)-1] == ' ' || s[len(s)-1] == '\t') {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// cookieKeyring holds the keys derived from Config.CookieKeys.
+type cookieKeyring struct {
+	signing [][]byte      // HMAC-SHA256 keys, newest first
+	aeads   []cipher.AEAD // AES-256-GCM, newest first
+}
+
+// cookieEncoding encodes signed and encrypted values (cookie-safe, unpadded).
+var cookieEncoding = base64.RawURLEncoding
+
+// newCookieKeyring derives independent signing and encryption keys from each
+// secret, so the same secret is never used for two purposes.
+func newCookieKeyring(secrets [][]byte) (*cookieKeyring, error) {
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+	keys := &cookieKeyring{}
+	for i, secret := range secrets {
+		if len(secret) < minCookieKeyLen {
+			return nil, fmt.Errorf("cookie key %d is %d bytes, need at least %d", i, len(secret), minCookieKeyLen)
+		}
+		signing, err := hkdf.Key(sha256.New, secret, nil, "bolt cookie signing", 32)
+		if err != nil {
+			return nil, err
+		}
+		encryption, err := hkdf.Key(sha256.New, secret, nil, "bolt cookie encryption", 32)
+		if err != nil {
+			return nil, err
+		}
+		block, err := aes.NewCipher(encryption)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keys.signing = append(keys.signing, signing)
+		keys.aeads = append(keys.aeads, aead)
+	}
+	return keys, nil
+}
+
+// sign returns "base64(value).base64(mac)" using the newest key.
+func (k *cookieKeyring) sign(name, value string) string {
+	payload := cookieEncoding.EncodeToString([]byte(value))
+	return payload + "." + cookieEncoding.EncodeToString(cookieMAC(k.signing[0], name, payload))
+}
+
+// verify checks a signed value against every key and returns the original value.
+func (k *cookieKeyring) verify(name string, raw []byte) (string, error) {
+	dot := -1
+	for i := len(raw) - 1; i >= 0; i-- {
+		if raw[i] == '.' {
+			dot = i
+			break
+		}
+	}
+	if dot < 0 {
+		return "", ErrInvalidCookie
+	}
+	payload := bytesToString(raw[:dot])
+	mac, err := cookieEncoding.DecodeString(bytesToString(raw[dot+1:]))
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+
+	for _, key := range k.signing {
+		if hmac.Equal(mac, cookieMAC(key, name, payload)) {
+			value, err := cookieEncoding.DecodeString(payload)
+			if err != nil {
+				return "", ErrInvalidCookie
+			}
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+// cookieMAC computes HMAC-SHA256(key, name "=" payload).
+func cookieMAC(key []byte, name, payload string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name))
+	h.Write([]byte{'='})
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
+
+// encrypt returns base64(nonce || ciphertext) using the newest key.
+// The cookie name is authenticated as additional data.
+func (k *cookieKeyring) encrypt(name, value string) (string, error) {
+	aead := k.aeads[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return cookieEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens an encrypted value with every key.
+func (k *cookieKeyring) decrypt(name string, raw []byte) (string, error) {
+	sealed, err := cookieEncoding.DecodeString(bytesToString(raw))
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, aead := range k.aeads {
+		if len(sealed) < aead.NonceSize()+aead.Overhead() {
+			return "", ErrInvalidCookie
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if value, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
@@ -0,0 +1,260 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/shockwave/pkg/shockwave/http11"
+)
+
+// TestFindCookie tests Cookie header parsing.
+func TestFindCookie(t *testing.T) {
+	header := []byte(`a=1; session = abc ;quoted="x y";empty=; flag; a=2`)
+	tests := []struct {
+		name  string
+		value string
+		found bool
+	}{
+		{"a", "1", true},
+		{"session", "abc", true},
+		{"quoted", "x y", true},
+		{"empty", "", true},
+		{"flag", "", false},
+		{"missing", "", false},
+		{"sess", "", false},
+	}
+	for _, tt := range tests {
+		value, found := findCookie(header, tt.name)
+		if found != tt.found || string(value) != tt.value {
+			t.Errorf("findCookie(%q) = %q, %v; want %q, %v", tt.name, value, found, tt.value, tt.found)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		findCookie(header, "quoted")
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs, got %v", allocs)
+	}
+}
+
+// TestCookieString tests Set-Cookie serialization.
+func TestCookieString(t *testing.T) {
+	ck := &Cookie{
+		Name:        "id",
+		Value:       "a1",
+		Path:        "/",
+		Domain:      "example.com",
+		Expires:     time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		MaxAge:      3600,
+		Secure:      true,
+		HttpOnly:    true,
+		SameSite:    SameSiteNoneMode,
+		Partitioned: true,
+	}
+	want := "id=a1; Path=/; Domain=example.com; Expires=Wed, 02 Jan 2030 03:04:05 GMT; Max-Age=3600; HttpOnly; Secure; SameSite=None; Partitioned"
+	if got := ck.String(); got != want {
+		t.Errorf("unexpected cookie\n%s\nwant\n%s", got, want)
+	}
+
+	if got := (&Cookie{Name: "x", MaxAge: -1, SameSite: SameSiteStrictMode}).String(); got != "x=; Max-Age=0; SameSite=Strict" {
+		t.Errorf("unexpected delete cookie %q", got)
+	}
+	if got := (&Cookie{Name: "bad name", Value: "v"}).String(); got != "" {
+		t.Errorf("expected invalid name to be rejected, got %q", got)
+	}
+}
+
+// TestContextCookies tests plain, signed and encrypted cookies over net/http.
+func TestContextCookies(t *testing.T) {
+	oldKey := bytes.Repeat([]byte("o"), 32)
+	newKey := bytes.Repeat([]byte("n"), 32)
+
+	config := DefaultConfig()
+	config.CookieKeys = [][]byte{oldKey}
+	oldApp := NewWithConfig(config)
+	config.CookieKeys = [][]byte{newKey, oldKey}
+	app := NewWithConfig(config)
+
+	for _, a := range []*App{oldApp, app} {
+		a.Get("/set", func(c *Context) error {
+			c.SetCookie(&Cookie{Name: "theme", Value: "dark", Path: "/"})
+			if err := c.SetSignedCookie(&Cookie{Name: "user", Value: "ada; admin=1", Path: "/"}); err != nil {
+				return err
+			}
+			return c.SetEncryptedCookie(&Cookie{Name: "secret", Value: "s3cr3t", Path: "/", HttpOnly: true})
+		})
+		a.Get("/get", func(c *Context) error {
+			user, err := c.SignedCookie("user")
+			if err != nil {
+				return err
+			}
+			secret, err := c.EncryptedCookie("secret")
+			if err != nil {
+				return err
+			}
+			return c.Blob(200, "text/plain", []byte(c.Cookie("theme")+"|"+user+"|"+secret))
+		})
+	}
+
+	set := func(a *App) []*http.Cookie {
+		w := httptest.NewRecorder()
+		a.ServeHTTP(w, httptest.NewRequest("GET", "/set", nil))
+		return w.Result().Cookies()
+	}
+	get := func(a *App, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/get", nil)
+		for _, ck := range cookies {
+			r.AddCookie(ck)
+		}
+		w := httptest.NewRecorder()
+		a.ServeHTTP(w, r)
+		return w
+	}
+
+	cookies := set(app)
+	if len(cookies) != 3 {
+		t.Fatalf("expected 3 cookies, got %d", len(cookies))
+	}
+	if strings.Contains(cookies[2].Value, "s3cr3t") {
+		t.Error("encrypted cookie leaks its value")
+	}
+	if w := get(app, cookies); w.Code != 200 || w.Body.String() != "dark|ada; admin=1|s3cr3t" {
+		t.Errorf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+
+	// Cookies set with a rotated-out key are still accepted
+	if w := get(app, set(oldApp)); w.Code != 200 {
+		t.Errorf("expected old key to verify, got %d", w.Code)
+	}
+	// ...but not the other way around
+	if w := get(oldApp, cookies); w.Code != 400 {
+		t.Errorf("expected unknown key to be rejected, got %d", w.Code)
+	}
+
+	// Tampering
+	tampered := set(app)
+	tampered[1].Value = strings.Replace(tampered[1].Value, tampered[1].Value[:2], "AA", 1)
+	if w := get(app, tampered); w.Code != 400 {
+		t.Errorf("expected tampered signed cookie to be rejected, got %d", w.Code)
+	}
+	swapped := set(app)
+	swapped[1].Value, swapped[2].Value = swapped[2].Value, swapped[1].Value
+	if w := get(app, swapped); w.Code != 400 {
+		t.Errorf("expected swapped cookies to be rejected, got %d", w.Code)
+	}
+}
+
+// TestSignedCookieErrors tests errors of the signed/encrypted helpers.
+func TestSignedCookieErrors(t *testing.T) {
+	c := &Context{}
+	c.SetRequestHeader("Cookie", "user=x")
+	if _, err := c.SignedCookie("user"); !errors.Is(err, errNoCookieKeys) {
+		t.Errorf("expected errNoCookieKeys, got %v", err)
+	}
+
+	config := DefaultConfig()
+	config.CookieKeys = [][]byte{bytes.Repeat([]byte("k"), 32)}
+	c.app = NewWithConfig(config)
+	if _, err := c.SignedCookie("missing"); !errors.Is(err, ErrNoCookie) {
+		t.Errorf("expected ErrNoCookie, got %v", err)
+	}
+	if _, err := c.EncryptedCookie("user"); !errors.Is(err, ErrInvalidCookie) || !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected ErrInvalidCookie, got %v", err)
+	}
+	if err := c.SetEncryptedCookie(&Cookie{Name: "big", Value: strings.Repeat("x", maxCookieSize)}); err == nil {
+		t.Error("expected oversized cookie to be rejected")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for short cookie key")
+		}
+	}()
+	config.CookieKeys = [][]byte{[]byte("short")}
+	NewWithConfig(config)
+}
+
+// TestCookieShockwave tests cookies through the Shockwave server.
+func TestCookieShockwave(t *testing.T) {
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+
+	ts.app.Get("/cookies", func(c *Context) error {
+		c.SetCookie(&Cookie{Name: "a", Value: "0", Path: "/"})
+		c.SetCookie(&Cookie{Name: "b", Value: "2", Path: "/", SameSite: SameSiteLaxMode})
+		c.SetCookie(&Cookie{Name: "a", Value: "1", Path: "/"}) // Replaces a=0
+		c.SetCookie(&Cookie{Name: "large1", Value: strings.Repeat("x", 200)})
+		c.SetCookie(&Cookie{Name: "large2", Value: strings.Repeat("y", 200)})
+		return c.Text(200, c.Cookie("session")+"|"+c.Cookie("other"))
+	})
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	req, _ := http.NewRequest("GET", ts.url+"/cookies", nil)
+	req.Header.Set("Cookie", "other=2; session=abc")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	cookies := resp.Cookies()
+	if len(cookies) != 4 || cookies[0].Value != "1" || cookies[1].SameSite != http.SameSiteLaxMode ||
+		cookies[2].Name != "large1" || cookies[3].Name != "large2" {
+		t.Errorf("unexpected cookies %v", cookies)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "abc|2" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+// TestCookieBytesNoAlloc tests that cookies are read without allocating
+// from a parsed request with a large (overflow storage) Cookie header.
+func TestCookieBytesNoAlloc(t *testing.T) {
+	cookie := "_ga=GA1.2.1234567890.1700000000; _gid=GA1.2.987654321.1700000000; theme=dark; " +
+		"consent=analytics%3Dtrue%26ads%3Dfalse; csrf_token=4f3c2a1b9e8d7c6b5a4f3e2d1c0b9a8f; " +
+		"session=eyJ1c2VyIjo0Mn0.ZXhwaXJlcw.c2lnbmF0dXJlLXNpZ25hdHVyZQ"
+	raw := "GET / HTTP/1.1\r\nHost: example.com\r\nCookie: " + cookie + "\r\n\r\n"
+	req, err := http11.NewParser().Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &Context{shockwaveReq: req}
+
+	if len(cookie) < 200 {
+		t.Fatalf("expected a 200+ byte Cookie header, got %d", len(cookie))
+	}
+	if got := string(ctx.CookieBytes("session")); got != "eyJ1c2VyIjo0Mn0.ZXhwaXJlcw.c2lnbmF0dXJlLXNpZ25hdHVyZQ" {
+		t.Fatalf("unexpected session cookie %q", got)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = ctx.CookieBytes("session") }); allocs != 0 {
+		t.Errorf("CookieBytes allocs = %v, want 0", allocs)
+	}
+}
+
+// TestSetCookieReplace tests that setting a cookie twice keeps one header.
+func TestSetCookieReplace(t *testing.T) {
+	app := New()
+	app.Get("/", func(c *Context) error {
+		c.SetCookie(&Cookie{Name: "id", Value: "1", Path: "/"})
+		c.SetCookie(&Cookie{Name: "id", Value: "2", Path: "/admin"}) // Different cookie
+		c.SetCookie(&Cookie{Name: "other", Value: "x"})
+		c.SetCookie(&Cookie{Name: "id", Value: "3", Path: "/"})
+		return c.NoContent()
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	want := []string{"id=3; Path=/", "id=2; Path=/admin", "other=x"}
+	if got := w.Header().Values("Set-Cookie"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected Set-Cookie headers %q", got)
+	}
+}
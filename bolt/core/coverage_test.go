@@ -351,14 +351,7 @@ func TestGenericsAPIHelpers(t *testing.T) {
 		ctx := &Context{}
 		data := BadRequest[TestData](errors.New("bad request"))
 
-		err := sendData(ctx, data)
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
-		}
-
-		if ctx.statusCode != 400 {
-			t.Errorf("expected status 400, got %d", ctx.statusCode)
-		}
+		assertDataError(t, sendData(ctx, data), 400, "bad request")
 	})
 
 	// Test sendData with custom headers
@@ -397,36 +390,30 @@ func TestGenericsAPIHelpers(t *testing.T) {
 		}
 	})
 
-	// Test sendErrorData
-	t.Run("sendErrorData", func(t *testing.T) {
-		ctx := &Context{}
+	// Test dataError
+	t.Run("dataError", func(t *testing.T) {
 		data := Data[TestData]{
 			Error:  errors.New("test error"),
 			Status: 500,
 		}
 
-		err := sendErrorData(ctx, data)
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
-		}
-
-		if ctx.statusCode != 500 {
-			t.Errorf("expected status 500, got %d", ctx.statusCode)
+		// Same status as mapped: passed to the error handler as is
+		if err := dataError(data); err != data.Error {
+			t.Errorf("expected the handler's error, got %v", err)
 		}
 	})
 
-	// Test sendErrorData with metadata
-	t.Run("sendErrorData with metadata", func(t *testing.T) {
-		ctx := &Context{}
+	// Test dataError with metadata
+	t.Run("dataError with metadata", func(t *testing.T) {
 		data := Data[TestData]{
 			Error:    errors.New("test error"),
 			Status:   400,
 			Metadata: map[string]interface{}{"code": "INVALID_INPUT"},
 		}
 
-		err := sendErrorData(ctx, data)
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
+		e := AsHTTPError(dataError(data))
+		if e.Status != 400 || e.Detail != "test error" || e.Extensions["meta"] == nil {
+			t.Errorf("unexpected error: %+v", e)
 		}
 	})
 }
@@ -672,7 +659,7 @@ func TestContextJSONErrorPath(t *testing.T) {
 	}
 }
 
-// TestSendErrorDataWithHeadersAndMeta tests sendErrorData with custom headers and metadata.
+// TestSendErrorDataWithHeadersAndMeta tests sendData with custom headers and metadata.
 func TestSendErrorDataWithHeadersAndMeta(t *testing.T) {
 	type TestData struct {
 		Message string `json:"message"`
@@ -690,14 +677,7 @@ func TestSendErrorDataWithHeadersAndMeta(t *testing.T) {
 		},
 	}
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.statusCode != 403 {
-		t.Errorf("expected status 403, got %d", ctx.statusCode)
-	}
+	assertDataError(t, sendData(ctx, data), 403, "custom error")
 
 	// Verify header was set
 	header := ctx.GetResponseHeader("X-Error-Code")
@@ -0,0 +1,270 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+// MIMEApplicationProblemJSON is the media type of RFC 9457 problem details.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// HTTPError is an error carrying an HTTP status and RFC 9457 problem details.
+//
+// Return it from handlers to control the status and body the error handler
+// sends. Both DefaultErrorHandler and ProblemErrorHandler understand it,
+// including when wrapped with fmt.Errorf("...: %w", err).
+//
+// Example:
+//
+//	app.Get("/accounts/:id", func(c *bolt.Context) error {
+//	    if balance < amount {
+//	        return bolt.NewHTTPError(409, "insufficient_funds", "Balance is 30, but the transfer costs 50.").
+//	            With("balance", 30).
+//	            With("accounts", []string{"/accounts/12345"})
+//	    }
+//	    // ...
+//	})
+type HTTPError struct {
+	// Status is the HTTP status code (default: 500).
+	Status int
+
+	// Code is a stable, machine-readable error code ("insufficient_funds").
+	Code string
+
+	// Type is a URI identifying the problem type (default: "about:blank").
+	Type string
+
+	// Title is a short summary of the problem type (default: the status text).
+	Title string
+
+	// Detail explains this occurrence of the problem to the client.
+	Detail string
+
+	// Instance is a URI identifying this occurrence (default: the request path).
+	Instance string
+
+	// Extensions are additional problem members, sent at the top level.
+	Extensions map[string]any
+
+	// RetryAfter sets the Retry-After header when positive (429, 503).
+	RetryAfter time.Duration
+
+	// Err is the underlying cause. It's never sent to the client.
+	Err error
+}
+
+// NewHTTPError creates an HTTPError.
+//
+// Example:
+//
+//	return bolt.NewHTTPError(404, "user_not_found", "No user with ID 42.")
+func NewHTTPError(status int, code, detail string) *HTTPError {
+	return &HTTPError{Status: status, Code: code, Detail: detail}
+}
+
+// With sets an extension member and returns e for chaining.
+func (e *HTTPError) With(key string, value any) *HTTPError {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// Wrap sets the underlying cause and returns e for chaining.
+//
+// Example:
+//
+//	if err := payments.Charge(ctx, order); err != nil {
+//	    return bolt.NewHTTPError(502, "payment_failed", "The payment provider is unavailable.").Wrap(err)
+//	}
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	msg := strconv.Itoa(e.status()) + " " + e.title()
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying cause.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// status returns the status code, defaulting to 500.
+func (e *HTTPError) status() int {
+	if e.Status < 400 || e.Status > 599 {
+		return 500
+	}
+	return e.Status
+}
+
+// title returns the title, defaulting to the status text.
+func (e *HTTPError) title() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return http.StatusText(e.status())
+}
+
+// Problem returns the problem details object of e (RFC 9457 §3).
+//
+// Extensions are merged at the top level; they can't replace the standard
+// members. Empty members are omitted, except type and title.
+func (e *HTTPError) Problem() map[string]any {
+	problem := make(map[string]any, len(e.Extensions)+6)
+	for key, value := range e.Extensions {
+		problem[key] = value
+	}
+
+	problem["type"] = "about:blank"
+	if e.Type != "" {
+		problem["type"] = e.Type
+	}
+	problem["title"] = e.title()
+	problem["status"] = e.status()
+	if e.Detail != "" {
+		problem["detail"] = e.Detail
+	}
+	if e.Instance != "" {
+		problem["instance"] = e.Instance
+	}
+	if e.Code != "" {
+		problem["code"] = e.Code
+	}
+	return problem
+}
+
+// AsHTTPError maps err to the HTTPError the error handlers send.
+//
+// Mapping, first match wins:
+//   - an *HTTPError in the chain (errors.As) is returned as is
+//   - framework errors (ErrNotFound, ErrBadRequest, ...) get their status;
+//     the message of a wrapped error becomes the detail
+//   - *capacitor.ValidationError: 422 listing the failing fields in
+//     "errors" (400 if it also wraps ErrBadRequest, as Bind does)
+//   - capacitor.IsNotFound: 404
+//   - capacitor.IsRetryable (timeouts, failed connections): 503 with
+//     Retry-After: 1
+//   - anything else: 500, without details (Err keeps the cause for logging)
+//
+// Example:
+//
+//	func errorHandler(c *bolt.Context, err error) {
+//	    if httpErr := bolt.AsHTTPError(err); httpErr.Status >= 500 {
+//	        logger.Error("request failed", "path", c.Path(), "error", err)
+//	    }
+//	    bolt.ProblemErrorHandler(c, err)
+//	}
+func AsHTTPError(err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	e := &HTTPError{Status: 500, Code: "internal_error", Err: err}
+	for _, m := range sentinelErrors {
+		if errors.Is(err, m.err) {
+			e.Status, e.Code, e.Title = m.status, m.code, m.title
+			if err.Error() != m.err.Error() {
+				e.Detail = err.Error()
+			}
+			break
+		}
+	}
+
+	// Validation errors (Bind, Validate, capacitor DAL): list failing fields
+	var validationErr *capacitor.ValidationError
+	if errors.As(err, &validationErr) && len(validationErr.Errors) > 0 {
+		if e.Status == 500 {
+			e.Status, e.Code = 422, "validation_failed"
+		}
+		fields := make([]FieldErrorResponse, len(validationErr.Errors))
+		for i, fe := range validationErr.Errors {
+			fields[i] = FieldErrorResponse{Field: fe.Field, Message: fe.Message}
+		}
+		return e.With("errors", fields)
+	}
+
+	if e.Status == 500 {
+		switch {
+		case capacitor.IsNotFound(err):
+			e.Status, e.Code = 404, "not_found"
+		case capacitor.IsRetryable(err):
+			e.Status, e.Code, e.RetryAfter = 503, "unavailable", time.Second
+		}
+	}
+	return e
+}
+
+// sentinelErrors maps framework errors to statuses, codes and titles.
+var sentinelErrors = [...]struct {
+	err    error
+	status int
+	code   string
+	title  string
+}{
+	{ErrNotFound, 404, "not_found", "Not Found"},
+	{ErrBadRequest, 400, "bad_request", "Bad Request"},
+	{ErrUnauthorized, 401, "unauthorized", "Unauthorized"},
+	{ErrForbidden, 403, "forbidden", "Forbidden"},
+	{ErrMethodNotAllowed, 405, "method_not_allowed", "Method Not Allowed"},
+	{ErrNotAcceptable, 406, "not_acceptable", "Not Acceptable"},
+	{ErrRequestTooLarge, 413, "request_too_large", "Request Too Large"},
+	{ErrUnsupportedMediaType, 415, "unsupported_media_type", "Unsupported Media Type"},
+}
+
+// ProblemErrorHandler is an ErrorHandler sending RFC 9457 problem details
+// as application/problem+json. Errors are mapped by AsHTTPError; the
+// instance defaults to the request path.
+//
+// Example:
+//
+//	app := bolt.NewWithConfig(bolt.Config{ErrorHandler: bolt.ProblemErrorHandler})
+//
+//	// GET /users/42 → 404
+//	// {"code":"user_not_found","detail":"No user with ID 42.","instance":"/users/42",
+//	//  "status":404,"title":"Not Found","type":"about:blank"}
+func ProblemErrorHandler(c *Context, err error) {
+	// The response already started (e.g. a stream): an error body would corrupt it
+	if c.Written() {
+		return
+	}
+
+	e := AsHTTPError(err)
+	problem := e.Problem()
+	if e.Instance == "" && c.Path() != "" {
+		problem["instance"] = c.Path()
+	}
+	setRetryAfter(c, e)
+
+	data, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		// Unencodable extension: send the standard members only
+		data = fmt.Appendf(nil, `{"type":"about:blank","title":%q,"status":%d}`, e.title(), e.status())
+	}
+	c.Blob(e.status(), MIMEApplicationProblemJSON, data)
+}
+
+// setRetryAfter sets the Retry-After header from e.RetryAfter (whole seconds).
+func setRetryAfter(c *Context, e *HTTPError) {
+	if e.RetryAfter > 0 {
+		seconds := int64((e.RetryAfter + time.Second - 1) / time.Second)
+		c.SetHeader("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+}
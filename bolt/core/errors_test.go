@@ -0,0 +1,178 @@
+package core
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+// TestAsHTTPError tests the mapping of errors to statuses.
+func TestAsHTTPError(t *testing.T) {
+	conflict := NewHTTPError(409, "insufficient_funds", "Balance is 30.")
+	fields := []capacitor.FieldError{{Field: "email", Message: "is required"}}
+
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		code       string
+		detail     string
+		retryAfter time.Duration
+		fields     int
+	}{
+		{"http error", conflict, 409, "insufficient_funds", "Balance is 30.", 0, 0},
+		{"wrapped http error", fmt.Errorf("transfer: %w", conflict), 409, "insufficient_funds", "Balance is 30.", 0, 0},
+		{"sentinel", ErrNotFound, 404, "not_found", "", 0, 0},
+		{"wrapped sentinel", fmt.Errorf("%w: content encoding %q", ErrUnsupportedMediaType, "br"), 415, "unsupported_media_type", `unsupported media type: content encoding "br"`, 0, 0},
+		{"validation", &capacitor.ValidationError{Type: "User", Errors: fields}, 422, "validation_failed", "", 0, 1},
+		{"bind validation", &capacitor.ValidationError{Type: "User", Errors: fields, Err: ErrBadRequest}, 400, "bad_request", "validation failed for User: email: is required", 0, 1},
+		{"capacitor not found", &capacitor.CacheError{Layer: "L1", Op: "Get", Key: "user:1", Err: capacitor.ErrNotFound}, 404, "not_found", "", 0, 0},
+		{"retryable", &capacitor.NetworkError{Host: "db", Op: "Get", Err: errors.New("reset"), Retryable: true}, 503, "unavailable", "", time.Second, 0},
+		{"timeout", fmt.Errorf("query: %w", capacitor.ErrTimeout), 503, "unavailable", "", time.Second, 0},
+		{"not retryable", &capacitor.NetworkError{Host: "db", Op: "Get", Err: errors.New("refused")}, 500, "internal_error", "", 0, 0},
+		{"unknown", errors.New("secret database password"), 500, "internal_error", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := AsHTTPError(tt.err)
+			if e.Status != tt.status || e.Code != tt.code || e.Detail != tt.detail || e.RetryAfter != tt.retryAfter {
+				t.Errorf("unexpected mapping %d %q %q %v", e.Status, e.Code, e.Detail, e.RetryAfter)
+			}
+			if got, _ := e.Extensions["errors"].([]FieldErrorResponse); len(got) != tt.fields {
+				t.Errorf("expected %d fields, got %v", tt.fields, got)
+			}
+			if tt.status == 500 && !errors.Is(e, tt.err) {
+				t.Error("expected the cause to be kept for logging")
+			}
+		})
+	}
+}
+
+// TestProblemErrorHandler tests RFC 9457 problem+json responses.
+func TestProblemErrorHandler(t *testing.T) {
+	app := NewWithConfig(Config{ErrorHandler: ProblemErrorHandler})
+	app.Get("/accounts/:id", func(c *Context) error {
+		return NewHTTPError(409, "insufficient_funds", "Balance is 30.").
+			With("balance", 30).
+			With("status", "ignored") // Standard members can't be replaced
+	})
+	app.Get("/users", func(c *Context) error {
+		return &capacitor.ValidationError{Type: "User", Errors: []capacitor.FieldError{{Field: "email", Message: "is required"}}}
+	})
+	app.Get("/db", func(c *Context) error {
+		return fmt.Errorf("load: %w", capacitor.ErrConnectionFailed)
+	})
+	app.Get("/typed", func(c *Context) error {
+		return &HTTPError{Status: 403, Type: "https://example.com/probs/out-of-credit", Title: "You do not have enough credit.", Instance: "/account/12345/msgs/abc"}
+	})
+
+	tests := []struct {
+		path       string
+		status     int
+		retryAfter string
+		want       map[string]any
+	}{
+		{"/accounts/1", 409, "", map[string]any{
+			"type": "about:blank", "title": "Conflict", "status": 409.0, "detail": "Balance is 30.",
+			"instance": "/accounts/1", "code": "insufficient_funds", "balance": 30.0,
+		}},
+		{"/users", 422, "", map[string]any{
+			"type": "about:blank", "title": "Unprocessable Entity", "status": 422.0, "instance": "/users",
+			"code": "validation_failed", "errors": []any{map[string]any{"field": "email", "message": "is required"}},
+		}},
+		{"/db", 503, "1", map[string]any{
+			"type": "about:blank", "title": "Service Unavailable", "status": 503.0, "instance": "/db", "code": "unavailable",
+		}},
+		{"/typed", 403, "", map[string]any{
+			"type": "https://example.com/probs/out-of-credit", "title": "You do not have enough credit.",
+			"status": 403.0, "instance": "/account/12345/msgs/abc",
+		}},
+		{"/missing", 404, "", map[string]any{
+			"type": "about:blank", "title": "Not Found", "status": 404.0, "instance": "/missing", "code": "not_found",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != tt.status || w.Header().Get("Content-Type") != MIMEApplicationProblemJSON {
+				t.Fatalf("unexpected response %d %q", w.Code, w.Header().Get("Content-Type"))
+			}
+			if w.Header().Get("Retry-After") != tt.retryAfter {
+				t.Errorf("expected Retry-After %q, got %q", tt.retryAfter, w.Header().Get("Retry-After"))
+			}
+			var got map[string]any
+			if err := stdjson.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// Shockwave answers unmatched routes without the error handler only
+	// when it is DefaultErrorHandler
+	if !New().fastErrors || app.fastErrors {
+		t.Error("expected pre-compiled 404/405 bodies with DefaultErrorHandler only")
+	}
+}
+
+// TestDefaultErrorHandlerBodies tests DefaultErrorHandler response bodies.
+func TestDefaultErrorHandlerBodies(t *testing.T) {
+	app := New()
+	app.Get("/conflict", func(c *Context) error {
+		return NewHTTPError(409, "insufficient_funds", "Balance is 30.")
+	})
+	app.Get("/wrapped", func(c *Context) error {
+		return fmt.Errorf("%w: content encoding %q", ErrUnsupportedMediaType, "br")
+	})
+	app.Get("/validation", func(c *Context) error {
+		return &capacitor.ValidationError{Type: "User", Errors: []capacitor.FieldError{{Field: "email", Message: "is required"}}}
+	})
+	app.Get("/db", func(c *Context) error {
+		return capacitor.ErrTimeout
+	})
+	app.Get("/panic", func(c *Context) error {
+		return errors.New("secret")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/conflict", 409, `{"error":"Conflict","code":"insufficient_funds","detail":"Balance is 30."}`},
+		{"/wrapped", 415, `{"error":"Unsupported Media Type"}`},
+		{"/validation", 422, `{"error":"Unprocessable Entity","fields":[{"field":"email","message":"is required"}]}`},
+		{"/db", 503, `{"error":"Service Unavailable"}`},
+		{"/panic", 500, `{"error":"Internal Server Error"}`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != tt.status || w.Body.String() != tt.body+"\n" {
+			t.Errorf("%s: unexpected response %d %s", tt.path, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestHTTPErrorString tests HTTPError messages.
+func TestHTTPErrorString(t *testing.T) {
+	err := NewHTTPError(502, "payment_failed", "Provider down.").Wrap(errors.New("dial tcp: timeout"))
+	if got := err.Error(); got != "502 Bad Gateway: Provider down.: dial tcp: timeout" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := (&HTTPError{}).Error(); got != "500 Internal Server Error" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
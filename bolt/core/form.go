@@ -0,0 +1,369 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+)
+
+// Request body access: urlencoded forms, multipart forms and file uploads.
+//
+// Bodies are parsed lazily on first access and cached on the Context for the
+// rest of the request. Every body read is capped at Config.MaxRequestBodySize;
+// multipart file parts larger than Config.MaxMultipartMemory are spooled to
+// temporary files, which are removed when the Context is released.
+
+const (
+	mimeJSON           = "application/json"
+	mimeFormURLEncoded = "application/x-www-form-urlencoded"
+	mimeMultipartForm  = "multipart/form-data"
+
+	// defaultMaxMultipartMemory is used when Config.MaxMultipartMemory is unset.
+	defaultMaxMultipartMemory = 32 << 20 // 32MB
+)
+
+// ErrMissingFile is returned by FormFile when the form has no file for the field.
+var ErrMissingFile = errors.New("missing file")
+
+// errBodyConsumed is returned when the body was already handed to MultipartReader.
+var errBodyConsumed = errors.New("request body already consumed")
+
+// FormValue returns the first value for the named form field.
+//
+// Values from urlencoded and multipart bodies take precedence over query
+// parameters. Parse errors are ignored; use Form() to inspect them.
+//
+// Example:
+//
+//	name := c.FormValue("name")
+//
+// Performance: Body parsed once on first call, cached afterwards
+func (c *Context) FormValue(key string) string {
+	_ = c.parseForm()
+	if vs := c.form[key]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// Form returns all form values (urlencoded or multipart body, then query).
+//
+// Example:
+//
+//	form, err := c.Form()
+//	if err != nil {
+//	    return err
+//	}
+//	tags := form["tag"]
+func (c *Context) Form() (url.Values, error) {
+	err := c.parseForm()
+	return c.form, err
+}
+
+// MultipartForm parses a multipart/form-data body.
+//
+// File parts larger than Config.MaxMultipartMemory are written to temporary
+// files; they are deleted automatically when the request completes.
+//
+// Example:
+//
+//	form, err := c.MultipartForm()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, fh := range form.File["attachments"] {
+//	    // fh.Open(), fh.Size, fh.Filename
+//	}
+func (c *Context) MultipartForm() (*multipart.Form, error) {
+	if c.multipartForm != nil {
+		return c.multipartForm, nil
+	}
+
+	reader, err := c.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	form, err := reader.ReadForm(c.maxMultipartMemory())
+	if err != nil {
+		return nil, bodyError(err)
+	}
+	c.multipartForm = form
+	return form, nil
+}
+
+// FormFile returns the first uploaded file for the named multipart field.
+//
+// Example:
+//
+//	fh, err := c.FormFile("avatar")
+//	if err != nil {
+//	    return err
+//	}
+//	f, err := fh.Open()
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+
+	files := form.File[name]
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %w %q", ErrBadRequest, ErrMissingFile, name)
+	}
+	return files[0], nil
+}
+
+// MultipartReader returns a streaming reader over the parts of a
+// multipart/form-data body.
+//
+// Use it instead of MultipartForm to process large uploads without buffering:
+// each part is read directly from the connection. The body can only be
+// consumed once, so MultipartForm, FormValue and Bind are unavailable after.
+//
+// Example:
+//
+//	reader, err := c.MultipartReader()
+//	if err != nil {
+//	    return err
+//	}
+//	for {
+//	    part, err := reader.NextPart()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    io.Copy(storage.Writer(part.FileName()), part)
+//	}
+func (c *Context) MultipartReader() (*multipart.Reader, error) {
+	if c.bodyConsumed {
+		return nil, errBodyConsumed
+	}
+
+	mediaType, params := c.mediaType()
+	if mediaType != mimeMultipartForm {
+		return nil, fmt.Errorf("%w: expected %s", ErrUnsupportedMediaType, mimeMultipartForm)
+	}
+
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, fmt.Errorf("%w: missing multipart boundary", ErrBadRequest)
+	}
+
+	body := c.bodyReader()
+	if body == nil {
+		return nil, fmt.Errorf("%w: missing request body", ErrBadRequest)
+	}
+
+	c.bodyConsumed = true
+	return multipart.NewReader(body, boundary), nil
+}
+
+// parseForm parses the request body and query into c.form (once per request).
+func (c *Context) parseForm() error {
+	if c.form != nil {
+		return nil
+	}
+	c.form = make(url.Values)
+
+	var err error
+	mediaType, _ := c.mediaType()
+	switch mediaType {
+	case mimeFormURLEncoded:
+		err = c.parseURLEncoded()
+	case mimeMultipartForm:
+		var form *multipart.Form
+		if form, err = c.MultipartForm(); err == nil {
+			for k, vs := range form.Value {
+				c.form[k] = append(c.form[k], vs...)
+			}
+		}
+	}
+
+	// Query parameters after body values (body takes precedence)
+	// Copy query bytes: they reference a reused request buffer
+	if len(c.queryBytes) > 0 {
+		query, qerr := url.ParseQuery(string(c.queryBytes))
+		for k, vs := range query {
+			c.form[k] = append(c.form[k], vs...)
+		}
+		if err == nil && qerr != nil {
+			err = fmt.Errorf("%w: %v", ErrBadRequest, qerr)
+		}
+	}
+	return err
+}
+
+// parseURLEncoded reads an application/x-www-form-urlencoded body into c.form.
+func (c *Context) parseURLEncoded() error {
+	if c.bodyConsumed {
+		return errBodyConsumed
+	}
+
+	body := c.bodyReader()
+	if body == nil {
+		return nil
+	}
+	c.bodyConsumed = true
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return bodyError(err)
+	}
+
+	values, err := url.ParseQuery(string(data))
+	for k, vs := range values {
+		c.form[k] = append(c.form[k], vs...)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+	return nil
+}
+
+// mediaType returns the lowercase request media type and its parameters.
+func (c *Context) mediaType() (string, map[string]string) {
+	contentType := c.GetHeader("Content-Type")
+	if contentType == "" {
+		return "", nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Malformed parameters - still dispatch on the type itself
+		mediaType, _, _ = strings.Cut(contentType, ";")
+		return strings.ToLower(strings.TrimSpace(mediaType)), nil
+	}
+	return mediaType, params
+}
+
+// SetRequestBodyDecoder installs a decoder the request body is read through
+// (Bind, forms, multipart), e.g. decompression (see middleware.Decompress).
+//
+// The raw body is still capped at Config.MaxRequestBodySize; the decoded body
+// is capped at maxSize bytes (0: Config.MaxRequestBodySize, negative: no
+// limit) so small compressed bodies can't expand without bound. Reads past
+// either limit fail with ErrRequestTooLarge (413).
+//
+// Example:
+//
+//	c.SetRequestBodyDecoder(func(raw io.Reader) io.Reader {
+//	    return base64.NewDecoder(base64.StdEncoding, raw)
+//	}, 0)
+func (c *Context) SetRequestBodyDecoder(decode func(raw io.Reader) io.Reader, maxSize int64) {
+	c.bodyDecoder = decode
+	c.bodyDecodedMax = maxSize
+}
+
+// bodyReader returns the request body capped at Config.MaxRequestBodySize,
+// decoded by the request body decoder if one is installed.
+//
+// Returns nil if the request has no body.
+func (c *Context) bodyReader() io.Reader {
+	var body io.Reader
+	switch {
+	case c.shockwaveReq != nil:
+		if c.shockwaveReq.Body == nil {
+			return nil
+		}
+		body = c.shockwaveReq.Body
+	case c.httpReq != nil:
+		if c.httpReq.Body == nil {
+			return nil
+		}
+		body = c.httpReq.Body
+	default:
+		return nil
+	}
+
+	var limit int64
+	if c.app != nil {
+		limit = int64(c.app.config.MaxRequestBodySize)
+	}
+	if limit > 0 {
+		body = &maxBytesReader{r: body, remaining: limit}
+	}
+
+	if c.bodyDecoder != nil {
+		body = c.bodyDecoder(body)
+		if c.bodyDecodedMax != 0 {
+			limit = c.bodyDecodedMax
+		}
+		if limit > 0 {
+			body = &maxBytesReader{r: body, remaining: limit}
+		}
+	}
+	return body
+}
+
+// maxMultipartMemory returns the multipart in-memory threshold.
+func (c *Context) maxMultipartMemory() int64 {
+	if c.app != nil && c.app.config.MaxMultipartMemory > 0 {
+		return c.app.config.MaxMultipartMemory
+	}
+	return defaultMaxMultipartMemory
+}
+
+// maxBytesReader limits a body, failing with ErrRequestTooLarge past the limit.
+//
+// Unlike io.LimitReader it reports the overflow instead of a silent EOF,
+// so truncated bodies are never mistaken for complete ones.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// Read implements io.Reader.
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrRequestTooLarge
+	}
+
+	// Read one byte past the limit to detect overflow
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	if errors.Is(err, ErrRequestTooLarge) {
+		m.remaining = -1 // An inner limit (e.g. before decoding) was hit
+	}
+
+	if int64(n) > m.remaining {
+		n = int(m.remaining)
+		m.remaining = -1
+		return n, ErrRequestTooLarge
+	}
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// exceeded reports whether a body from bodyReader hit its size limit.
+//
+// Some decoders (goccy/go-json streams) report read errors as syntax
+// errors, so the limit is checked on the reader as well as the error.
+func exceeded(body io.Reader) bool {
+	m, ok := body.(*maxBytesReader)
+	return ok && m.remaining < 0
+}
+
+// bodyError maps body read/parse errors to framework errors.
+func bodyError(err error) error {
+	switch {
+	case errors.Is(err, ErrRequestTooLarge):
+		return ErrRequestTooLarge
+	case errors.Is(err, multipart.ErrMessageTooLarge):
+		return fmt.Errorf("%w: %v", ErrRequestTooLarge, err)
+	default:
+		return fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+}
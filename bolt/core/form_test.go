@@ -0,0 +1,254 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newMultipartBody builds a multipart body with one field and one file.
+func newMultipartBody(t *testing.T, fileContent string) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	if err := w.WriteField("title", "holiday"); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := w.CreateFormFile("photo", "beach.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(fileContent))
+	w.Close()
+	return body, w.FormDataContentType()
+}
+
+// TestContextFormValueURLEncoded tests urlencoded body parsing with query fallback.
+func TestContextFormValueURLEncoded(t *testing.T) {
+	app := New()
+	app.Post("/form", func(c *Context) error {
+		if got := c.FormValue("name"); got != "alice" {
+			t.Errorf("expected name=alice, got %q", got)
+		}
+		if got := c.FormValue("page"); got != "2" {
+			t.Errorf("expected query fallback page=2, got %q", got)
+		}
+
+		form, err := c.Form()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(form["tag"]) != 2 {
+			t.Errorf("expected 2 tags, got %v", form["tag"])
+		}
+		return c.NoContent()
+	})
+
+	req := httptest.NewRequest("POST", "/form?page=2", strings.NewReader("name=alice&tag=a&tag=b"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+}
+
+// TestContextMultipartForm tests multipart parsing with files spooled to disk.
+func TestContextMultipartForm(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxMultipartMemory = 16 // Force spooling to a temp file
+	app := NewWithConfig(config)
+
+	content := strings.Repeat("x", 1024)
+	app.Post("/upload", func(c *Context) error {
+		if got := c.FormValue("title"); got != "holiday" {
+			t.Errorf("expected title=holiday, got %q", got)
+		}
+
+		fh, err := c.FormFile("photo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fh.Filename != "beach.jpg" || fh.Size != int64(len(content)) {
+			t.Errorf("unexpected file header: %s (%d bytes)", fh.Filename, fh.Size)
+		}
+
+		f, err := fh.Open()
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		if string(data) != content {
+			t.Error("file content mismatch")
+		}
+
+		if _, err := c.FormFile("missing"); !errors.Is(err, ErrMissingFile) || !errors.Is(err, ErrBadRequest) {
+			t.Errorf("expected ErrMissingFile wrapping ErrBadRequest, got %v", err)
+		}
+		return c.NoContent()
+	})
+
+	body, contentType := newMultipartBody(t, content)
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+}
+
+// TestContextMultipartReader tests streaming part iteration.
+func TestContextMultipartReader(t *testing.T) {
+	app := New()
+	app.Post("/stream", func(c *Context) error {
+		reader, err := c.MultipartReader()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var names []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("next part: %v", err)
+			}
+			names = append(names, part.FormName())
+		}
+		assertLog(t, names, []string{"title", "photo"})
+
+		if _, err := c.MultipartForm(); err == nil {
+			t.Error("expected error after body was consumed by MultipartReader")
+		}
+		return c.NoContent()
+	})
+
+	body, contentType := newMultipartBody(t, "data")
+	req := httptest.NewRequest("POST", "/stream", body)
+	req.Header.Set("Content-Type", contentType)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// TestContextBodyLimit tests that MaxRequestBodySize is enforced while reading.
+func TestContextBodyLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRequestBodySize = 8
+	app := NewWithConfig(config)
+	app.Post("/form", func(c *Context) error {
+		_, err := c.Form()
+		return err
+	})
+
+	req := httptest.NewRequest("POST", "/form", strings.NewReader("name=a-very-long-value"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 413 {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
+
+// TestRequestBodyDecoder tests decoded bodies and their size limits.
+func TestRequestBodyDecoder(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRequestBodySize = 16
+	app := NewWithConfig(config)
+	app.Use(func(next Handler) Handler {
+		return func(c *Context) error {
+			// Decoding quadruples the body with leading whitespace
+			maxSize, _ := strconv.ParseInt(c.Query("max"), 10, 64)
+			c.SetRequestBodyDecoder(func(raw io.Reader) io.Reader {
+				data, err := io.ReadAll(raw)
+				if err != nil {
+					return errReader{err}
+				}
+				return strings.NewReader(strings.Repeat(" ", 3*len(data)) + string(data))
+			}, maxSize)
+			return next(c)
+		}
+	})
+	app.Post("/json", func(c *Context) error {
+		var v []int
+		if err := c.BindJSON(&v); err != nil {
+			return err
+		}
+		return c.JSON(200, len(v))
+	})
+
+	tests := []struct {
+		query  string
+		body   string
+		status int
+	}{
+		{"", "[12]", 200},                         // Decoded 16 bytes
+		{"", "[1,2]", 413},                        // Decoded past the default 16 bytes
+		{"?max=8", "[1]", 413},                    // Decoded past 8 bytes
+		{"?max=-1", "[1,2,3,4,5,6,7]", 200},       // No decoded limit
+		{"?max=1024", "[1,2,3,4,5,6,7,8,9]", 413}, // Raw body past 16 bytes
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/json"+tt.query, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %q: expected %d, got %d", tt.query, tt.body, tt.status, w.Code)
+		}
+	}
+}
+
+// errReader returns err from every Read.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// TestContextBodyLimitJSON tests that limits hit while decoding JSON are 413.
+func TestContextBodyLimitJSON(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRequestBodySize = 8
+	app := NewWithConfig(config)
+	app.Post("/json", func(c *Context) error {
+		var v map[string]string
+		return c.BindJSON(&v)
+	})
+
+	req := httptest.NewRequest("POST", "/json", strings.NewReader(`{"name": "a-very-long-value"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 413 {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
+
+// TestMaxBytesReader tests the body limit reader at the boundary.
+func TestMaxBytesReader(t *testing.T) {
+	r := &maxBytesReader{r: strings.NewReader("12345"), remaining: 5}
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "12345" {
+		t.Errorf("expected full body within limit, got %q, %v", data, err)
+	}
+
+	r = &maxBytesReader{r: strings.NewReader("123456"), remaining: 5}
+	data, err = io.ReadAll(r)
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Errorf("expected ErrRequestTooLarge, got %v", err)
+	}
+	if string(data) != "12345" {
+		t.Errorf("expected bytes up to the limit, got %q", data)
+	}
+}
@@ -12,24 +12,32 @@
 // Methods cannot have type parameters independent of the receiver type.
 // This means we CANNOT create generic methods like app.Get[T]() or app.Post[T]().
 //
-// Instead, use the Data[T] wrapper with standalone sendData helper function:
+// Instead, register typed handlers with the standalone functions GetT, PostT,
+// PutT, PatchT and DeleteT (see generics_routes.go):
 //
 //	app := bolt.New()
-//	app.Get("/users/:id", func(c *bolt.Context) error {
+//	bolt.GetT(app, "/users/:id", func(c *bolt.Context) bolt.Data[User] {
 //	    user, err := db.GetUser(c.Param("id"))
 //	    if err != nil {
-//	        return sendData(c, bolt.NotFound[User](err))
+//	        return bolt.NotFound[User](err)
 //	    }
-//	    data := bolt.OK(user).
+//	    return bolt.OK(user).
 //	        WithMeta("cached", true).
 //	        WithHeader("X-Cache-Hit", "true")
-//	    return sendData(c, data)
 //	})
 //	app.Listen(":8080")
 //
+// In regular handlers, send Data[T] values with SendData.
+//
 // See examples/hello/main.go for complete working example.
 package core
 
+import (
+	"errors"
+	"maps"
+	"reflect"
+)
+
 // Data wraps a response value with metadata and error handling.
 //
 // The Data[T] pattern provides:
@@ -49,7 +57,8 @@ type Data[T any] struct {
 	// Value is the actual response data
 	Value T `json:"data,omitempty"`
 
-	// Error is any error that occurred during request processing
+	// Error is any error that occurred during request processing.
+	// It's sent by the app error handler, with Status (if set) as the status
 	Error error `json:"error,omitempty"`
 
 	// Metadata contains additional information about the response
@@ -63,6 +72,19 @@ type Data[T any] struct {
 	Headers map[string]string `json:"-"`
 }
 
+// dataEnvelope is implemented by every Data[T].
+//
+// OpenAPI generation uses it to document the {"data", "meta"} envelope
+// written by sendData instead of the Go struct layout.
+type dataEnvelope interface {
+	envelopeValueType() reflect.Type
+}
+
+// envelopeValueType returns the type of Data.Value.
+func (Data[T]) envelopeValueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
 // Result wraps a Data[T] with an additional error field.
 //
 // This is useful for operations that may return either data or an error,
@@ -96,7 +118,7 @@ type Result[T any] struct {
 //
 // Example:
 //
-//	app.Get[User]("/users/:id", func(c *bolt.Context) bolt.Data[User] {
+//	bolt.GetT(app, "/users/:id", func(c *bolt.Context) bolt.Data[User] {
 //	    return bolt.OK(user)
 //	})
 type GenericHandler[T any] func(*Context) Data[T]
@@ -104,14 +126,14 @@ type GenericHandler[T any] func(*Context) Data[T]
 // GenericTypedHandler defines a handler with automatic JSON parsing.
 //
 // The framework automatically:
-//   - Parses request body into Req type
-//   - Validates JSON structure
+//   - Parses request into Req type (see Bind)
+//   - Validates Req (`validate` tags)
 //   - Passes parsed Req to handler
 //   - Handles response as with GenericHandler
 //
 // Example:
 //
-//	app.PostJSON[CreateUserRequest, User]("/users",
+//	bolt.PostT(app, "/users",
 //	    func(c *bolt.Context, req CreateUserRequest) bolt.Data[User] {
 //	        user := createUser(req)
 //	        return bolt.Created(user)
@@ -385,7 +407,8 @@ func (d Data[T]) WithHeaders(headers map[string]string) Data[T] {
 // This is used internally to handle Data[T] responses.
 //
 // Behavior:
-//   - If Data.Error != nil: Returns error response with Data.Status
+//   - If Data.Error != nil: Returns the error for the app error handler
+//     (see dataError), after setting Data.Headers
 //   - If Data.Status == 0: Defaults to 200
 //   - Serializes Data.Value to JSON
 //   - Includes Data.Metadata if present
@@ -397,27 +420,25 @@ func (d Data[T]) WithHeaders(headers map[string]string) Data[T] {
 //	  "data": <value>,
 //	  "meta": <metadata>  // if present
 //	}
-//
-// Response format (error):
-//
-//	{
-//	  "error": "<error message>",
-//	  "meta": <metadata>  // if present
-//	}
 func sendData[T any](c *Context, data Data[T]) error {
-	// Set status code (default 200)
-	if data.Status == 0 {
-		data.Status = 200
+	// Set custom headers (even for errors)
+	for key, value := range data.Headers {
+		c.SetHeader(key, value)
 	}
 
 	// Handle error case
 	if data.Error != nil {
-		return sendErrorData(c, data)
+		return dataError(data)
 	}
 
-	// Set custom headers
-	for key, value := range data.Headers {
-		c.SetHeader(key, value)
+	// Set status code (default 200)
+	if data.Status == 0 {
+		data.Status = 200
+	}
+
+	// No body for 204 No Content
+	if data.Status == 204 {
+		return c.NoContent()
 	}
 
 	// Build response
@@ -432,21 +453,36 @@ func sendData[T any](c *Context, data Data[T]) error {
 	return c.JSON(data.Status, response)
 }
 
-// sendErrorData is a helper function that sends an error response to the client.
-func sendErrorData[T any](c *Context, data Data[T]) error {
-	// Set custom headers (even for errors)
-	for key, value := range data.Headers {
-		c.SetHeader(key, value)
+// dataError returns the error of data for the app error handler, so typed
+// handlers send the same error responses as regular ones (no 500 details).
+//
+// An explicit Data.Status overrides the status AsHTTPError maps the error
+// to. The message of client errors (4xx) becomes the detail, as handlers
+// write it for clients: bolt.NotFound[User](errors.New("no user 42")).
+// Data.Metadata becomes the "meta" extension.
+func dataError[T any](data Data[T]) error {
+	mapped := AsHTTPError(data.Error)
+	override := data.Status != 0 && data.Status != mapped.status()
+	if !override && len(data.Metadata) == 0 {
+		return data.Error
 	}
 
-	// Build error response
-	response := make(map[string]interface{}, 2)
-	response["error"] = data.Error.Error()
-
-	// Include metadata if present
+	e := *mapped // Copy: the handler's error may be shared
+	e.Err = data.Error
+	if override {
+		var httpErr *HTTPError
+		if !errors.As(data.Error, &httpErr) {
+			// Mapped members describe the mapped status
+			e.Code, e.Title, e.Detail = "", "", ""
+		}
+		e.Status = data.Status
+		if e.Detail == "" && e.status() < 500 {
+			e.Detail = data.Error.Error()
+		}
+	}
 	if len(data.Metadata) > 0 {
-		response["meta"] = data.Metadata
+		e.Extensions = maps.Clone(e.Extensions)
+		e.With("meta", data.Metadata)
 	}
-
-	return c.JSON(data.Status, response)
+	return &e
 }
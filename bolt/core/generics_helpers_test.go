@@ -106,99 +106,94 @@ func TestSendDataWithCustomStatus(t *testing.T) {
 	}
 }
 
-// TestSendErrorDataNotFound tests sendErrorData with 404 error.
+// assertDataError checks the error sendData returns for the error handler.
+func assertDataError(t *testing.T, err error, status int, detail string) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("expected an error for the error handler")
+	}
+	e := AsHTTPError(err)
+	if e.status() != status || e.Detail != detail {
+		t.Errorf("expected %d with detail %q, got %d with %q", status, detail, e.status(), e.Detail)
+	}
+}
+
+// TestSendErrorDataNotFound tests sendData with 404 error.
 func TestSendErrorDataNotFound(t *testing.T) {
 	ctx := &Context{}
 	data := NotFound[string](errors.New("resource not found"))
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 404 {
-		t.Errorf("expected status 404, got %d", ctx.StatusCode())
-	}
+	err := sendData(ctx, data)
+	assertDataError(t, err, 404, "resource not found")
 
-	if !ctx.Written() {
-		t.Error("expected response to be written")
+	if ctx.Written() {
+		t.Error("expected the error handler to write the response")
 	}
 }
 
-// TestSendErrorDataWithMetadata tests sendErrorData with metadata.
+// TestSendErrorDataWithMetadata tests sendData with error metadata.
 func TestSendErrorDataWithMetadata(t *testing.T) {
 	ctx := &Context{}
 	data := BadRequest[int](errors.New("validation failed")).
 		WithMeta("field", "email").
 		WithMeta("reason", "invalid format")
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+	err := sendData(ctx, data)
+	assertDataError(t, err, 400, "validation failed")
 
-	if ctx.StatusCode() != 400 {
-		t.Errorf("expected status 400, got %d", ctx.StatusCode())
+	meta, _ := AsHTTPError(err).Extensions["meta"].(map[string]interface{})
+	if meta["field"] != "email" {
+		t.Errorf("expected meta extension, got %v", meta)
 	}
 }
 
-// TestSendErrorDataUnauthorized tests sendErrorData with 401.
+// TestSendErrorDataUnauthorized tests sendData with 401.
 func TestSendErrorDataUnauthorized(t *testing.T) {
 	ctx := &Context{}
 	data := Unauthorized[any](errors.New("invalid credentials"))
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 401 {
-		t.Errorf("expected status 401, got %d", ctx.StatusCode())
-	}
+	assertDataError(t, sendData(ctx, data), 401, "invalid credentials")
 }
 
-// TestSendErrorDataForbidden tests sendErrorData with 403.
+// TestSendErrorDataForbidden tests sendData with 403.
 func TestSendErrorDataForbidden(t *testing.T) {
 	ctx := &Context{}
 	data := Forbidden[any](errors.New("access denied"))
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 403 {
-		t.Errorf("expected status 403, got %d", ctx.StatusCode())
-	}
+	assertDataError(t, sendData(ctx, data), 403, "access denied")
 }
 
-// TestSendErrorDataConflict tests sendErrorData with 409.
+// TestSendErrorDataConflict tests sendData with 409.
 func TestSendErrorDataConflict(t *testing.T) {
 	ctx := &Context{}
 	data := Conflict[any](errors.New("resource already exists"))
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 409 {
-		t.Errorf("expected status 409, got %d", ctx.StatusCode())
-	}
+	assertDataError(t, sendData(ctx, data), 409, "resource already exists")
 }
 
-// TestSendErrorDataInternalServerError tests sendErrorData with 500.
+// TestSendErrorDataInternalServerError tests that 500 messages are not
+// sent to clients.
 func TestSendErrorDataInternalServerError(t *testing.T) {
 	ctx := &Context{}
 	data := InternalServerError[any](errors.New("database connection failed"))
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+	assertDataError(t, sendData(ctx, data), 500, "")
+}
 
-	if ctx.StatusCode() != 500 {
-		t.Errorf("expected status 500, got %d", ctx.StatusCode())
+// TestSendErrorDataHTTPError tests that Data.Status overrides the status of
+// an HTTPError without modifying it.
+func TestSendErrorDataHTTPError(t *testing.T) {
+	ctx := &Context{}
+	cause := NewHTTPError(404, "user_not_found", "No user 42.")
+	data := Data[any]{Error: cause, Status: 410}
+
+	err := sendData(ctx, data)
+	assertDataError(t, err, 410, "No user 42.")
+	if e := AsHTTPError(err); e.Code != "user_not_found" || !errors.Is(err, cause) {
+		t.Errorf("expected the code and cause to be kept, got %+v", e)
+	}
+	if cause.Status != 404 {
+		t.Error("expected the handler's error to be unchanged")
 	}
 }
 
@@ -237,15 +232,10 @@ func TestSendDataWithBothValueAndError(t *testing.T) {
 	ctx := &Context{}
 	data := OK("value").WithError(errors.New("but also error"))
 
-	// When error is set, should use error handling path
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	// Error was set, so should have been handled as error
-	if ctx.StatusCode() != 200 {
-		t.Errorf("expected status from error handling, got %d", ctx.StatusCode())
+	// When error is set, should use error handling path (a 200 error is a 500)
+	assertDataError(t, sendData(ctx, data), 500, "")
+	if ctx.Written() {
+		t.Error("expected the value not to be sent")
 	}
 }
 
@@ -334,36 +324,22 @@ func TestSendDataWithAllFeatures(t *testing.T) {
 	}
 }
 
-// TestSendErrorDataNoMetadata tests sendErrorData without metadata.
+// TestSendErrorDataNoMetadata tests sendData without metadata.
 func TestSendErrorDataNoMetadata(t *testing.T) {
 	ctx := &Context{}
 	data := BadRequest[string](errors.New("validation failed"))
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 400 {
-		t.Errorf("expected status 400, got %d", ctx.StatusCode())
-	}
+	assertDataError(t, sendData(ctx, data), 400, "validation failed")
 }
 
-// TestSendErrorDataWithHeaders tests sendErrorData with custom headers.
+// TestSendErrorDataWithHeaders tests sendData with custom headers.
 func TestSendErrorDataWithHeaders(t *testing.T) {
 	ctx := &Context{}
 	data := NotFound[string](errors.New("not found")).
 		WithHeader("X-Error-ID", "12345").
 		WithHeader("X-Trace-ID", "trace-123")
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 404 {
-		t.Errorf("expected status 404, got %d", ctx.StatusCode())
-	}
+	assertDataError(t, sendData(ctx, data), 404, "not found")
 
 	if val := ctx.GetResponseHeader("X-Error-ID"); val != "12345" {
 		t.Errorf("expected header '12345', got '%s'", val)
@@ -374,7 +350,7 @@ func TestSendErrorDataWithHeaders(t *testing.T) {
 	}
 }
 
-// TestSendErrorDataAllStatusCodes tests sendErrorData with all error status codes.
+// TestSendErrorDataAllStatusCodes tests sendData with all error status codes.
 func TestSendErrorDataAllStatusCodes(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -392,19 +368,16 @@ func TestSendErrorDataAllStatusCodes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := &Context{}
-			err := sendErrorData(ctx, tt.data)
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-			}
-
-			if ctx.StatusCode() != tt.status {
-				t.Errorf("expected status %d, got %d", tt.status, ctx.StatusCode())
+			detail := tt.data.Error.Error()
+			if tt.status >= 500 {
+				detail = ""
 			}
+			assertDataError(t, sendData(ctx, tt.data), tt.status, detail)
 		})
 	}
 }
 
-// TestSendErrorDataWithMetadataAndHeaders tests sendErrorData with both metadata and headers.
+// TestSendErrorDataWithMetadataAndHeaders tests sendData with both metadata and headers.
 func TestSendErrorDataWithMetadataAndHeaders(t *testing.T) {
 	ctx := &Context{}
 	data := BadRequest[int](errors.New("validation failed")).
@@ -414,14 +387,7 @@ func TestSendErrorDataWithMetadataAndHeaders(t *testing.T) {
 		WithHeader("X-Error-Code", "VALIDATION_ERROR").
 		WithHeader("X-Request-ID", "req-123")
 
-	err := sendErrorData(ctx, data)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if ctx.StatusCode() != 400 {
-		t.Errorf("expected status 400, got %d", ctx.StatusCode())
-	}
+	assertDataError(t, sendData(ctx, data), 400, "validation failed")
 
 	// Verify headers
 	if val := ctx.GetResponseHeader("X-Error-Code"); val != "VALIDATION_ERROR" {
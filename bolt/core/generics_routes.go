@@ -0,0 +1,165 @@
+package core
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Typed route registration for GenericHandler and GenericTypedHandler.
+//
+// Go methods cannot have their own type parameters, so app.Get[T] is not
+// possible. These standalone functions register typed handlers on an App
+// or Group and generate the glue: request decoding and validation, then
+// serialization of the Data[T] envelope (status, headers, metadata).
+
+// RouteRegistrar is implemented by *App and *Group.
+type RouteRegistrar interface {
+	Get(path string, handler Handler) *ChainLink
+	Post(path string, handler Handler) *ChainLink
+	Put(path string, handler Handler) *ChainLink
+	Delete(path string, handler Handler) *ChainLink
+	Patch(path string, handler Handler) *ChainLink
+}
+
+var (
+	_ RouteRegistrar = (*App)(nil)
+	_ RouteRegistrar = (*Group)(nil)
+)
+
+// GetT registers a GET route with a handler returning Data[Res].
+//
+// Example:
+//
+//	bolt.GetT(app, "/users/:id", func(c *bolt.Context) bolt.Data[User] {
+//	    user, err := users.Get(c.Context(), c.Param("id"))
+//	    if err != nil {
+//	        return bolt.NotFound[User](err)
+//	    }
+//	    return bolt.OK(user).WithMeta("cached", false)
+//	})
+func GetT[Res any](r RouteRegistrar, path string, handler GenericHandler[Res]) *ChainLink {
+	return documentTyped[Res](r.Get(path, wrapGeneric(handler, 200)), nil, 200)
+}
+
+// DeleteT registers a DELETE route with a handler returning Data[Res].
+//
+// Example:
+//
+//	bolt.DeleteT(app, "/users/:id", func(c *bolt.Context) bolt.Data[any] {
+//	    return bolt.NoContent[any]()
+//	})
+func DeleteT[Res any](r RouteRegistrar, path string, handler GenericHandler[Res]) *ChainLink {
+	return documentTyped[Res](r.Delete(path, wrapGeneric(handler, 200)), nil, 200)
+}
+
+// PostT registers a POST route that decodes and validates Req.
+//
+// Req is populated with Bind[Req] (body, query, headers, path parameters)
+// and checked against its `validate` tags; invalid requests never reach the
+// handler and produce a 400 listing the failing fields. Malformed
+// `validate` tags of Req panic here, at registration (see CheckTags).
+// Responses default to 201 Created.
+//
+// Example:
+//
+//	type CreateUser struct {
+//	    Name  string `json:"name" validate:"required,min=2"`
+//	    Email string `json:"email" validate:"required,email"`
+//	}
+//
+//	bolt.PostT(app, "/users", func(c *bolt.Context, req CreateUser) bolt.Data[User] {
+//	    return bolt.Created(createUser(req))
+//	})
+func PostT[Req any, Res any](r RouteRegistrar, path string, handler GenericTypedHandler[Req, Res]) *ChainLink {
+	return documentTyped[Res](r.Post(path, wrapTyped(handler, 201)), typeOf[Req](), 201)
+}
+
+// PutT registers a PUT route that decodes and validates Req (see PostT).
+//
+// Responses default to 200 OK.
+func PutT[Req any, Res any](r RouteRegistrar, path string, handler GenericTypedHandler[Req, Res]) *ChainLink {
+	return documentTyped[Res](r.Put(path, wrapTyped(handler, 200)), typeOf[Req](), 200)
+}
+
+// PatchT registers a PATCH route that decodes and validates Req (see PostT).
+//
+// Responses default to 200 OK.
+func PatchT[Req any, Res any](r RouteRegistrar, path string, handler GenericTypedHandler[Req, Res]) *ChainLink {
+	return documentTyped[Res](r.Patch(path, wrapTyped(handler, 200)), typeOf[Req](), 200)
+}
+
+// SendData sends a Data[T] response.
+//
+// Use it in regular handlers that build Data[T] values by hand.
+//
+// Example:
+//
+//	app.Get("/users/:id", func(c *bolt.Context) error {
+//	    return bolt.SendData(c, bolt.OK(user).WithHeader("X-Cache-Hit", "true"))
+//	})
+func SendData[T any](c *Context, data Data[T]) error {
+	return sendData(c, data)
+}
+
+// wrapGeneric adapts a GenericHandler to a Handler.
+func wrapGeneric[Res any](handler GenericHandler[Res], defaultStatus int) Handler {
+	return func(c *Context) error {
+		return sendDataDefault(c, handler(c), defaultStatus)
+	}
+}
+
+// wrapTyped adapts a GenericTypedHandler to a Handler, decoding Req first.
+func wrapTyped[Req any, Res any](handler GenericTypedHandler[Req, Res], defaultStatus int) Handler {
+	// Struct requests get full binding + validation; others ([]T, map) are JSON only
+	isStruct := typeOf[Req]().Kind() == reflect.Struct
+	if err := CheckTags[Req](); err != nil {
+		panic(err.Error())
+	}
+
+	return func(c *Context) error {
+		var req Req
+		if isStruct {
+			var err error
+			if req, err = Bind[Req](c); err != nil {
+				return err
+			}
+		} else if err := c.BindJSON(&req); err != nil {
+			return err
+		}
+
+		return sendDataDefault(c, handler(c, req), defaultStatus)
+	}
+}
+
+// sendDataDefault sends data, using defaultStatus if the handler set none.
+func sendDataDefault[T any](c *Context, data Data[T], defaultStatus int) error {
+	if data.Status == 0 && data.Error == nil {
+		data.Status = defaultStatus
+	}
+	return sendData(c, data)
+}
+
+// documentTyped records the request and Data[Res] response types of a typed
+// route for OpenAPI generation.
+func documentTyped[Res any](cl *ChainLink, req reflect.Type, status int) *ChainLink {
+	doc := &cl.lastRoute.Doc
+	doc.Request = req
+	doc.Responses = append(doc.Responses, ResponseDoc{
+		Status:      status,
+		Description: http.StatusText(status),
+		Type:        typeOf[Data[Res]](),
+	})
+	if req != nil {
+		doc.Responses = append(doc.Responses, ResponseDoc{
+			Status:      400,
+			Description: "Invalid request",
+			Type:        validationErrorBodyType,
+		})
+	}
+	return cl
+}
+
+// typeOf returns the reflect.Type of T (works for interface types too).
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
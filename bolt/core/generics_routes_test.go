@@ -0,0 +1,170 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type typedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type typedCreateUser struct {
+	Name string `json:"name" validate:"required,min=2"`
+}
+
+// decodeEnvelope decodes a Data[T] response body.
+func decodeEnvelope(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatalf("invalid JSON %q: %v", body, err)
+	}
+	return m
+}
+
+// TestGetT tests typed GET handlers and the Data[T] envelope.
+func TestGetT(t *testing.T) {
+	app := New()
+	GetT(app, "/users/:id", func(c *Context) Data[typedUser] {
+		if c.Param("id") != "7" {
+			return NotFound[typedUser](errors.New("user not found"))
+		}
+		return OK(typedUser{ID: 7, Name: "Ada"}).
+			WithMeta("cached", true).
+			WithHeader("X-Cache-Hit", "true").
+			WithStatus(203)
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/users/7", nil))
+
+	if w.Code != 203 {
+		t.Errorf("expected 203, got %d", w.Code)
+	}
+	if w.Header().Get("X-Cache-Hit") != "true" {
+		t.Error("expected X-Cache-Hit header")
+	}
+	body := decodeEnvelope(t, w.Body.Bytes())
+	if data, _ := body["data"].(map[string]interface{}); data["name"] != "Ada" {
+		t.Errorf("unexpected data: %v", body["data"])
+	}
+	if meta, _ := body["meta"].(map[string]interface{}); meta["cached"] != true {
+		t.Errorf("unexpected meta: %v", body["meta"])
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/users/8", nil))
+	if w.Code != 404 {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+	if body := decodeEnvelope(t, w.Body.Bytes()); body["error"] != "Not Found" || body["detail"] != "user not found" {
+		t.Errorf("unexpected error body: %v", body)
+	}
+}
+
+// TestGetTErrorHandler tests that Data errors go through the app error
+// handler: no 500 details, Data.Status kept, headers and metadata sent.
+func TestGetTErrorHandler(t *testing.T) {
+	app := NewWithConfig(Config{ErrorHandler: ProblemErrorHandler})
+	GetT(app, "/users/:id", func(c *Context) Data[typedUser] {
+		if c.Param("id") == "0" {
+			return InternalError[typedUser](errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+		}
+		return NotFound[typedUser](errors.New("no user "+c.Param("id"))).
+			WithHeader("X-Trace", "abc").
+			WithMeta("retry", false)
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/users/0", nil))
+	if w.Code != 500 || strings.Contains(w.Body.String(), "10.0.0.5") {
+		t.Errorf("expected 500 without details, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/users/8", nil))
+	if w.Code != 404 || w.Header().Get("Content-Type") != MIMEApplicationProblemJSON {
+		t.Errorf("expected a 404 problem, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w.Header().Get("X-Trace") != "abc" {
+		t.Error("expected X-Trace header")
+	}
+	body := decodeEnvelope(t, w.Body.Bytes())
+	if body["detail"] != "no user 8" || body["status"] != float64(404) || body["instance"] != "/users/8" {
+		t.Errorf("unexpected problem: %v", body)
+	}
+	if meta, _ := body["meta"].(map[string]interface{}); meta["retry"] != false {
+		t.Errorf("unexpected meta: %v", body["meta"])
+	}
+}
+
+// TestPostT tests request decoding, validation and the default 201 status.
+func TestPostT(t *testing.T) {
+	app := New()
+	called := 0
+	PostT(app, "/users", func(c *Context, req typedCreateUser) Data[typedUser] {
+		called++
+		return Created(typedUser{ID: 1, Name: req.Name}).WithStatus(0)
+	})
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"Grace"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 201 {
+		t.Errorf("expected default 201, got %d", w.Code)
+	}
+	if data, _ := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{}); data["name"] != "Grace" {
+		t.Errorf("unexpected data: %v", data)
+	}
+
+	// Invalid request never reaches the handler
+	req = httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"G"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 400 {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+	if called != 1 {
+		t.Errorf("expected handler to be called once, got %d", called)
+	}
+}
+
+// TestTypedRoutesOnGroup tests typed registration on groups and non-struct requests.
+func TestTypedRoutesOnGroup(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+
+	PutT(api, "/tags", func(c *Context, tags []string) Data[int] {
+		return OK(len(tags))
+	})
+	DeleteT(api, "/tags", func(c *Context) Data[any] {
+		return NoContent[any]()
+	})
+
+	req := httptest.NewRequest("PUT", "/api/tags", strings.NewReader(`["a","b","c"]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+	if body := decodeEnvelope(t, w.Body.Bytes()); body["data"] != float64(3) {
+		t.Errorf("expected data=3, got %v", body["data"])
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/tags", nil))
+	if w.Code != 204 || w.Body.Len() != 0 {
+		t.Errorf("expected empty 204, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,141 @@
+package core
+
+import "strings"
+
+// Group is a set of routes sharing a path prefix and middleware.
+//
+// Groups can be nested; a nested group inherits the prefix and middleware
+// of its parent. Group middleware runs after global middleware and before
+// the route handler, regardless of the order in which middleware and routes
+// are registered.
+//
+// Example:
+//
+//	api := app.Group("/api/v1", AuthMiddleware())
+//	api.Get("/users", listUsers)         // GET /api/v1/users
+//
+//	admin := api.Group("/admin", AdminMiddleware())
+//	admin.Delete("/users/:id", deleteUser) // DELETE /api/v1/admin/users/:id
+type Group struct {
+	app        *App
+	parent     *Group
+	prefix     string // Full prefix including parent prefixes
+	middleware []Middleware
+}
+
+// Group creates a route group with the given path prefix and middleware.
+//
+// Example:
+//
+//	v1 := app.Group("/api/v1")
+//	v1.Get("/users", listUsers)
+func (app *App) Group(prefix string, middleware ...Middleware) *Group {
+	return newGroup(app, nil, joinPaths("", prefix), middleware)
+}
+
+// newGroup creates a group owned by app.
+func newGroup(app *App, parent *Group, prefix string, middleware []Middleware) *Group {
+	mw := make([]Middleware, len(middleware))
+	copy(mw, middleware)
+
+	return &Group{
+		app:        app,
+		parent:     parent,
+		prefix:     prefix,
+		middleware: mw,
+	}
+}
+
+// Group creates a nested group under this group.
+//
+// Example:
+//
+//	api := app.Group("/api")
+//	v2 := api.Group("/v2") // prefix: /api/v2
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return newGroup(g.app, g, joinPaths(g.prefix, prefix), middleware)
+}
+
+// Use adds middleware to the group.
+//
+// The middleware applies to every route in the group and its nested groups,
+// including routes registered before Use was called.
+func (g *Group) Use(middleware ...Middleware) *Group {
+	g.middleware = append(g.middleware, middleware...)
+
+	// Re-compose routes owned by this group or any nested group
+	for _, route := range g.app.routes {
+		if route.group.within(g) {
+			g.app.register(route)
+		}
+	}
+	return g
+}
+
+// Prefix returns the full path prefix of the group.
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// within reports whether g is ancestor or a group nested inside it.
+func (g *Group) within(ancestor *Group) bool {
+	for cur := g; cur != nil; cur = cur.parent {
+		if cur == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Get registers a GET route in the group.
+func (g *Group) Get(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodGet, joinPaths(g.prefix, path), handler)
+}
+
+// Post registers a POST route in the group.
+func (g *Group) Post(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodPost, joinPaths(g.prefix, path), handler)
+}
+
+// Put registers a PUT route in the group.
+func (g *Group) Put(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodPut, joinPaths(g.prefix, path), handler)
+}
+
+// Delete registers a DELETE route in the group.
+func (g *Group) Delete(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodDelete, joinPaths(g.prefix, path), handler)
+}
+
+// Patch registers a PATCH route in the group.
+func (g *Group) Patch(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodPatch, joinPaths(g.prefix, path), handler)
+}
+
+// Head registers a HEAD route in the group.
+func (g *Group) Head(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodHead, joinPaths(g.prefix, path), handler)
+}
+
+// Options registers an OPTIONS route in the group.
+func (g *Group) Options(path string, handler Handler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodOptions, joinPaths(g.prefix, path), handler)
+}
+
+// joinPaths joins a group prefix and a route path.
+//
+// Example: joinPaths("/api/v1/", "/users") → "/api/v1/users"
+func joinPaths(prefix, path string) string {
+	if path == "" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	if path[0] != '/' {
+		return prefix + "/" + path
+	}
+	return prefix + path
+}
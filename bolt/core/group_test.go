@@ -0,0 +1,186 @@
+package core
+
+import (
+	"testing"
+)
+
+// recordingMiddleware returns middleware that appends name to log before calling next.
+func recordingMiddleware(log *[]string, name string) Middleware {
+	return func(next Handler) Handler {
+		return func(c *Context) error {
+			*log = append(*log, name)
+			return next(c)
+		}
+	}
+}
+
+// TestGroupPrefix tests that group routes are registered under the group prefix.
+func TestGroupPrefix(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		config := DefaultConfig()
+		config.UseLockFreeRouter = lockFree
+		app := NewWithConfig(config)
+
+		api := app.Group("/api/v1")
+		api.Get("/users", func(c *Context) error { return nil })
+		api.Post("users", func(c *Context) error { return nil })
+		api.Get("/users/:id", func(c *Context) error { return nil })
+
+		if h, _ := app.router.Lookup(MethodGet, "/api/v1/users"); h == nil {
+			t.Errorf("lockFree=%v: expected GET /api/v1/users to be registered", lockFree)
+		}
+		if h, _ := app.router.Lookup(MethodPost, "/api/v1/users"); h == nil {
+			t.Errorf("lockFree=%v: expected POST /api/v1/users to be registered", lockFree)
+		}
+		h, params := app.router.Lookup(MethodGet, "/api/v1/users/42")
+		if h == nil {
+			t.Fatalf("lockFree=%v: expected GET /api/v1/users/:id to be registered", lockFree)
+		}
+		if params["id"] != "42" {
+			t.Errorf("lockFree=%v: expected id=42, got %q", lockFree, params["id"])
+		}
+		if h, _ := app.router.Lookup(MethodGet, "/users"); h != nil {
+			t.Errorf("lockFree=%v: expected /users to not be registered", lockFree)
+		}
+	}
+}
+
+// TestNestedGroups tests prefix and middleware inheritance for nested groups.
+func TestNestedGroups(t *testing.T) {
+	app := New()
+	var log []string
+
+	api := app.Group("/api", recordingMiddleware(&log, "api"))
+	admin := api.Group("/admin/", recordingMiddleware(&log, "admin"))
+	admin.Delete("/users/:id", func(c *Context) error {
+		log = append(log, "handler")
+		return nil
+	})
+
+	if admin.Prefix() != "/api/admin/" {
+		t.Errorf("expected prefix /api/admin/, got %s", admin.Prefix())
+	}
+
+	handler, params := app.router.Lookup(MethodDelete, "/api/admin/users/7")
+	if handler == nil {
+		t.Fatal("expected nested group route to be registered")
+	}
+	if params["id"] != "7" {
+		t.Errorf("expected id=7, got %q", params["id"])
+	}
+
+	_ = handler(&Context{})
+
+	expected := []string{"api", "admin", "handler"}
+	assertLog(t, log, expected)
+}
+
+// TestGroupMiddlewareOrder tests that global middleware wraps group middleware
+// regardless of registration order.
+func TestGroupMiddlewareOrder(t *testing.T) {
+	app := New()
+	var log []string
+
+	api := app.Group("/api", recordingMiddleware(&log, "group"))
+	api.Get("/ping", func(c *Context) error {
+		log = append(log, "handler")
+		return nil
+	}).Use(recordingMiddleware(&log, "route"))
+
+	// Registered after the route - must still apply
+	app.Use(recordingMiddleware(&log, "global"))
+	api.Use(recordingMiddleware(&log, "group-late"))
+
+	handler, _ := app.router.Lookup(MethodGet, "/api/ping")
+	if handler == nil {
+		t.Fatal("expected route to be registered")
+	}
+	_ = handler(&Context{})
+
+	expected := []string{"route", "global", "group", "group-late", "handler"}
+	assertLog(t, log, expected)
+}
+
+// TestGlobalMiddlewareAfterRoutes tests that App.Use applies to routes registered earlier.
+func TestGlobalMiddlewareAfterRoutes(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		config := DefaultConfig()
+		config.UseLockFreeRouter = lockFree
+		app := NewWithConfig(config)
+		var log []string
+
+		app.Get("/static", func(c *Context) error { return nil })
+		app.Get("/users/:id", func(c *Context) error { return nil })
+		app.Get("/files/*path", func(c *Context) error { return nil })
+		app.Use(recordingMiddleware(&log, "global"))
+
+		for _, path := range []string{"/static", "/users/1", "/files/a/b"} {
+			handler, _ := app.router.Lookup(MethodGet, path)
+			if handler == nil {
+				t.Fatalf("lockFree=%v: expected %s to be registered", lockFree, path)
+			}
+			_ = handler(&Context{})
+		}
+
+		if len(log) != 3 {
+			t.Errorf("lockFree=%v: expected global middleware to run 3 times, got %d", lockFree, len(log))
+		}
+	}
+}
+
+// TestGroupUseScope tests that group middleware doesn't leak to sibling groups.
+func TestGroupUseScope(t *testing.T) {
+	app := New()
+	var log []string
+
+	v1 := app.Group("/v1")
+	v2 := app.Group("/v2")
+	v1.Get("/x", func(c *Context) error { return nil })
+	v2.Get("/x", func(c *Context) error { return nil })
+	v1.Use(recordingMiddleware(&log, "v1"))
+
+	handler, _ := app.router.Lookup(MethodGet, "/v2/x")
+	_ = handler(&Context{})
+	if len(log) != 0 {
+		t.Errorf("expected v1 middleware to not run for /v2/x, got %v", log)
+	}
+
+	handler, _ = app.router.Lookup(MethodGet, "/v1/x")
+	_ = handler(&Context{})
+	assertLog(t, log, []string{"v1"})
+}
+
+// TestJoinPaths tests prefix/path joining.
+func TestJoinPaths(t *testing.T) {
+	tests := []struct {
+		prefix, path, expected string
+	}{
+		{"", "", "/"},
+		{"", "/api", "/api"},
+		{"", "api", "/api"},
+		{"/api", "", "/api"},
+		{"/api", "/", "/api/"},
+		{"/api", "/users", "/api/users"},
+		{"/api/", "/users", "/api/users"},
+		{"/api", "users/:id", "/api/users/:id"},
+	}
+
+	for _, tt := range tests {
+		if got := joinPaths(tt.prefix, tt.path); got != tt.expected {
+			t.Errorf("joinPaths(%q, %q) = %q, want %q", tt.prefix, tt.path, got, tt.expected)
+		}
+	}
+}
+
+// assertLog compares an execution log against the expected sequence.
+func assertLog(t *testing.T, log, expected []string) {
+	t.Helper()
+	if len(log) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+	for i := range expected {
+		if log[i] != expected[i] {
+			t.Errorf("step %d: expected %s, got %s", i, expected[i], log[i])
+		}
+	}
+}
@@ -20,6 +20,9 @@ var (
 	headerConnection             = []byte("Connection")
 	headerCacheControl           = []byte("Cache-Control")
 	headerAccessControlAllowOrigin = []byte("Access-Control-Allow-Origin")
+	headerCookie                 = []byte("Cookie")
+	headerSetCookie              = []byte("Set-Cookie")
+	headerTransferEncoding       = []byte("Transfer-Encoding")
 )
 
 // Content-Type values (byte slice constants)
@@ -34,6 +37,11 @@ var (
 	contentTypeOctetStream = []byte("application/octet-stream")
 )
 
+// Header values (byte slice constants)
+var (
+	zeroBytes = []byte("0") // Content-Length of empty bodies
+)
+
 // ✅ PHASE 1.3: Pre-allocated header value slices (bypass net/textproto allocation)
 // These are shared, read-only slices that can be assigned directly to http.Header maps
 // without going through Header().Set() which triggers expensive canonicalization.
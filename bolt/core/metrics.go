@@ -0,0 +1,471 @@
+package core
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	shockwavebuf "github.com/yourusername/shockwave/pkg/shockwave"
+)
+
+// HTTP metrics in the Prometheus text exposition format (or OpenMetrics,
+// when the scraper asks for it), without a client library dependency.
+//
+// Request metrics are labelled by method, route pattern ("/users/:id", not
+// the raw path, so the number of series stays bounded) and status class
+// ("2xx"):
+//   - bolt_http_requests_total (counter)
+//   - bolt_http_requests_in_flight (gauge, unlabelled)
+//   - bolt_http_request_duration_seconds (histogram)
+//   - bolt_http_response_size_bytes (histogram)
+//
+// The endpoint also exports the Shockwave server statistics
+// (shockwave_server_*, while Listen or Run is serving), the Shockwave buffer
+// pool metrics (shockwave_buffer_pool_*, named as in the prometheus build of
+// Shockwave) and the context pool statistics (bolt_context_pool_*).
+
+// MetricsConfig defines the config for HTTP metrics.
+type MetricsConfig struct {
+	// DurationBuckets are the upper bounds of the request duration
+	// histogram, in seconds.
+	// Default: 5ms to 10s (the Prometheus client defaults)
+	DurationBuckets []float64
+
+	// SizeBuckets are the upper bounds of the response size histogram,
+	// in bytes.
+	// Default: 100B to 10MB, in powers of 10
+	SizeBuckets []float64
+
+	// SkipPaths are paths not recorded (e.g., /health)
+	SkipPaths []string
+}
+
+// DefaultMetricsConfig returns the default metrics configuration.
+func DefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		DurationBuckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		SizeBuckets:     []float64{100, 1000, 10000, 100000, 1000000, 10000000},
+		SkipPaths:       []string{},
+	}
+}
+
+// Metrics records HTTP metrics for every route and serves them, with the
+// server, buffer pool and context pool statistics, at path.
+//
+// Requests are recorded before any global or group middleware runs, so
+// rejections (rate limits, authentication) are counted too. Requests that
+// match no route (404, 405) are not recorded.
+//
+// Example:
+//
+//	app := bolt.New()
+//	app.Metrics("/metrics").Use(basicAuth) // Restrict access to the endpoint
+func (app *App) Metrics(path string) *ChainLink {
+	return app.MetricsWithConfig(path, DefaultMetricsConfig())
+}
+
+// MetricsWithConfig is Metrics with custom configuration.
+//
+// Example:
+//
+//	app.MetricsWithConfig("/metrics", bolt.MetricsConfig{
+//	    DurationBuckets: []float64{.001, .01, .1, 1},
+//	    SkipPaths:       []string{"/health"},
+//	})
+func (app *App) MetricsWithConfig(path string, config MetricsConfig) *ChainLink {
+	if app.metrics != nil {
+		panic("bolt: metrics are already enabled")
+	}
+
+	// Apply defaults
+	defaults := DefaultMetricsConfig()
+	if len(config.DurationBuckets) == 0 {
+		config.DurationBuckets = defaults.DurationBuckets
+	}
+	if len(config.SizeBuckets) == 0 {
+		config.SizeBuckets = defaults.SizeBuckets
+	}
+	if !slices.IsSorted(config.DurationBuckets) || !slices.IsSorted(config.SizeBuckets) {
+		panic("bolt: metrics buckets must be sorted")
+	}
+
+	m := newHTTPMetrics(config)
+	app.metrics = m
+
+	// Outermost global middleware: re-compose already registered routes
+	app.middleware = append([]Middleware{m.middleware}, app.middleware...)
+	for _, route := range app.routes {
+		app.register(route)
+	}
+
+	return app.Get(path, func(c *Context) error {
+		return app.serveMetrics(c)
+	}).Hidden()
+}
+
+// httpMetrics holds the request metrics of an App.
+type httpMetrics struct {
+	durationBuckets []float64
+	sizeBuckets     []float64
+	skipPaths       map[string]bool
+
+	inFlight atomic.Int64
+
+	// Route pattern → series per method. Copy-on-write: lookups are
+	// lock-free, new series are added under mu.
+	series atomic.Pointer[map[string][]*metricsSeries]
+	mu     sync.Mutex
+}
+
+// metricsSeries holds the metrics of a method and route.
+type metricsSeries struct {
+	method, route string
+	classes       [5]metricsClass // 1xx-5xx
+}
+
+// metricsClass holds the metrics of a status class.
+type metricsClass struct {
+	duration metricsHistogram // Sum in nanoseconds
+	size     metricsHistogram // Sum in bytes
+}
+
+// metricsHistogram is a histogram of integer observations.
+// counts has a bucket per upper bound, then +Inf (not cumulative).
+type metricsHistogram struct {
+	counts []atomic.Uint64
+	sum    atomic.Uint64
+}
+
+// newHTTPMetrics creates the request metrics of an App.
+func newHTTPMetrics(config MetricsConfig) *httpMetrics {
+	skipPaths := make(map[string]bool, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skipPaths[path] = true
+	}
+
+	m := &httpMetrics{
+		durationBuckets: config.DurationBuckets,
+		sizeBuckets:     config.SizeBuckets,
+		skipPaths:       skipPaths,
+	}
+	m.series.Store(&map[string][]*metricsSeries{})
+	return m
+}
+
+// middleware records the request metrics.
+func (m *httpMetrics) middleware(next Handler) Handler {
+	return func(c *Context) error {
+		if len(m.skipPaths) > 0 && m.skipPaths[string(c.PathBytes())] {
+			return next(c)
+		}
+
+		start := time.Now()
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+
+		err := next(c)
+
+		status := c.StatusCode()
+		if status == 0 {
+			status = 200
+			if err != nil && !c.Written() {
+				status = AsHTTPError(err).Status
+			}
+		}
+		elapsed := time.Since(start)
+		class := &m.lookup(c.MethodBytes(), c.route).classes[min(max(status/100, 1), 5)-1]
+		class.duration.observe(m.durationBuckets, elapsed.Seconds(), uint64(elapsed))
+		size := max(c.BytesWritten(), 0)
+		class.size.observe(m.sizeBuckets, float64(size), uint64(size))
+		return err
+	}
+}
+
+// lookup returns the series of a method and route, creating it if needed.
+//
+// ✅ OPTIMIZATION: 0 allocs/op once the series exists (string(method) in a
+// comparison doesn't allocate)
+func (m *httpMetrics) lookup(method []byte, route string) *metricsSeries {
+	for _, s := range (*m.series.Load())[route] {
+		if s.method == string(method) {
+			return s
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := *m.series.Load()
+	for _, s := range current[route] {
+		if s.method == string(method) {
+			return s
+		}
+	}
+
+	s := &metricsSeries{method: string(method), route: route}
+	for i := range s.classes {
+		s.classes[i].duration.counts = make([]atomic.Uint64, len(m.durationBuckets)+1)
+		s.classes[i].size.counts = make([]atomic.Uint64, len(m.sizeBuckets)+1)
+	}
+
+	next := make(map[string][]*metricsSeries, len(current)+1)
+	for route, series := range current {
+		next[route] = series
+	}
+	next[route] = append(slices.Clip(current[route]), s)
+	m.series.Store(&next)
+	return s
+}
+
+// observe records a value: v picks the bucket, n is added to the sum.
+func (h *metricsHistogram) observe(buckets []float64, v float64, n uint64) {
+	h.counts[sort.SearchFloat64s(buckets, v)].Add(1)
+	h.sum.Add(n)
+}
+
+// snapshot returns the cumulative bucket counts, ending with +Inf.
+func (h *metricsHistogram) snapshot() []uint64 {
+	counts := make([]uint64, len(h.counts))
+	var total uint64
+	for i := range h.counts {
+		total += h.counts[i].Load()
+		counts[i] = total
+	}
+	return counts
+}
+
+// serveMetrics writes the metrics exposition.
+func (app *App) serveMetrics(c *Context) error {
+	// OpenMetrics if the scraper prefers it (Prometheus does)
+	ranges := parseAccept(c.GetHeader("Accept"))
+	openMetricsQ, _ := matchAccept(ranges, "application/openmetrics-text")
+	textQ, _ := matchAccept(ranges, "text/plain")
+	w := &metricsWriter{openMetrics: openMetricsQ > textQ}
+
+	c.Vary("Accept")
+	w.writeHTTP(app.metrics)
+
+	app.serverMu.RLock()
+	srv := app.server
+	app.serverMu.RUnlock()
+	if srv != nil {
+		stats := srv.Stats()
+		w.counter("shockwave_server_connections_total", "Total connections accepted", float64(stats.TotalConnections.Load()))
+		w.gauge("shockwave_server_active_connections", "Current open connections", float64(stats.ActiveConnections.Load()))
+		w.counter("shockwave_server_requests_total", "Total requests handled", float64(stats.TotalRequests.Load()))
+		w.counter("shockwave_server_connection_errors_total", "Total connection errors", float64(stats.ConnectionErrors.Load()))
+		w.counter("shockwave_server_request_errors_total", "Total request errors", float64(stats.RequestErrors.Load()))
+		w.gauge("shockwave_server_start_time_seconds", "Server start time in seconds since the epoch", float64(stats.StartTime.UnixNano())/1e9)
+	}
+
+	w.writeBufferPool(shockwavebuf.GetBufferPoolMetrics())
+
+	w.counter("bolt_context_pool_allocated_total", "Total contexts allocated because the pool was empty", float64(app.contextPool.Stats().Allocated))
+
+	if w.openMetrics {
+		w.buf = append(w.buf, "# EOF\n"...)
+		return c.Blob(200, "application/openmetrics-text; version=1.0.0; charset=utf-8", w.buf)
+	}
+	return c.Blob(200, "text/plain; version=0.0.4; charset=utf-8", w.buf)
+}
+
+// metricsWriter writes metric families in the text exposition format.
+type metricsWriter struct {
+	buf         []byte
+	openMetrics bool
+}
+
+// metricLabel is a label of a sample.
+type metricLabel struct {
+	name, value string
+}
+
+// family writes the HELP and TYPE lines of a metric family. OpenMetrics
+// names counter families without the _total suffix of their samples.
+func (w *metricsWriter) family(name, typ, help string) {
+	if w.openMetrics && typ == "counter" {
+		name = strings.TrimSuffix(name, "_total")
+	}
+	w.buf = append(w.buf, "# HELP "...)
+	w.buf = append(w.buf, name...)
+	w.buf = append(w.buf, ' ')
+	w.buf = append(w.buf, help...)
+	w.buf = append(w.buf, "\n# TYPE "...)
+	w.buf = append(w.buf, name...)
+	w.buf = append(w.buf, ' ')
+	w.buf = append(w.buf, typ...)
+	w.buf = append(w.buf, '\n')
+}
+
+// sample writes a sample line.
+func (w *metricsWriter) sample(name string, labels []metricLabel, value float64) {
+	w.buf = append(w.buf, name...)
+	if len(labels) > 0 {
+		w.buf = append(w.buf, '{')
+		for i, l := range labels {
+			if i > 0 {
+				w.buf = append(w.buf, ',')
+			}
+			w.buf = append(w.buf, l.name...)
+			w.buf = append(w.buf, `="`...)
+			w.buf = appendLabelValue(w.buf, l.value)
+			w.buf = append(w.buf, '"')
+		}
+		w.buf = append(w.buf, '}')
+	}
+	w.buf = append(w.buf, ' ')
+	w.buf = appendMetricValue(w.buf, value)
+	w.buf = append(w.buf, '\n')
+}
+
+// counter writes a counter family with a single sample.
+func (w *metricsWriter) counter(name, help string, value float64) {
+	w.family(name, "counter", help)
+	w.sample(name, nil, value)
+}
+
+// gauge writes a gauge family with a single sample.
+func (w *metricsWriter) gauge(name, help string, value float64) {
+	w.family(name, "gauge", help)
+	w.sample(name, nil, value)
+}
+
+// histogram writes the samples of a histogram series; unit converts the
+// sum to the base unit.
+func (w *metricsWriter) histogram(name string, labels []metricLabel, buckets []float64, h *metricsHistogram, unit float64) {
+	counts := h.snapshot()
+	bucketLabels := append(slices.Clip(labels), metricLabel{name: "le"})
+	for i, count := range counts {
+		if i < len(buckets) {
+			bucketLabels[len(labels)].value = strconv.FormatFloat(buckets[i], 'g', -1, 64)
+		} else {
+			bucketLabels[len(labels)].value = "+Inf"
+		}
+		w.sample(name+"_bucket", bucketLabels, float64(count))
+	}
+	w.sample(name+"_sum", labels, float64(h.sum.Load())*unit)
+	w.sample(name+"_count", labels, float64(counts[len(counts)-1]))
+}
+
+// writeHTTP writes the request metrics, series sorted by route and method.
+func (w *metricsWriter) writeHTTP(m *httpMetrics) {
+	var series []*metricsSeries
+	for _, s := range *m.series.Load() {
+		series = append(series, s...)
+	}
+	slices.SortFunc(series, func(a, b *metricsSeries) int {
+		if c := strings.Compare(a.route, b.route); c != 0 {
+			return c
+		}
+		return strings.Compare(a.method, b.method)
+	})
+
+	// each calls fn for every status class with requests
+	each := func(fn func(labels []metricLabel, class *metricsClass)) {
+		for _, s := range series {
+			for i := range s.classes {
+				class := &s.classes[i]
+				if class.duration.snapshot()[len(m.durationBuckets)] == 0 {
+					continue
+				}
+				fn([]metricLabel{
+					{name: "method", value: s.method},
+					{name: "route", value: s.route},
+					{name: "status", value: strconv.Itoa(i+1) + "xx"},
+				}, class)
+			}
+		}
+	}
+
+	w.family("bolt_http_requests_total", "counter", "Total HTTP requests")
+	each(func(labels []metricLabel, class *metricsClass) {
+		counts := class.duration.snapshot()
+		w.sample("bolt_http_requests_total", labels, float64(counts[len(counts)-1]))
+	})
+
+	w.gauge("bolt_http_requests_in_flight", "HTTP requests being handled", float64(m.inFlight.Load()))
+
+	w.family("bolt_http_request_duration_seconds", "histogram", "HTTP request duration in seconds")
+	each(func(labels []metricLabel, class *metricsClass) {
+		w.histogram("bolt_http_request_duration_seconds", labels, m.durationBuckets, &class.duration, 1e-9)
+	})
+
+	w.family("bolt_http_response_size_bytes", "histogram", "HTTP response body size in bytes")
+	each(func(labels []metricLabel, class *metricsClass) {
+		w.histogram("bolt_http_response_size_bytes", labels, m.sizeBuckets, &class.size, 1)
+	})
+}
+
+// writeBufferPool writes the Shockwave buffer pool metrics.
+func (w *metricsWriter) writeBufferPool(metrics shockwavebuf.BufferPoolMetrics) {
+	pools := []struct {
+		size    string
+		metrics *shockwavebuf.SizedPoolMetrics
+	}{
+		{"2kb", &metrics.Pool2KB},
+		{"4kb", &metrics.Pool4KB},
+		{"8kb", &metrics.Pool8KB},
+		{"16kb", &metrics.Pool16KB},
+		{"32kb", &metrics.Pool32KB},
+		{"64kb", &metrics.Pool64KB},
+	}
+	sized := func(name, typ, help string, value func(p *shockwavebuf.SizedPoolMetrics) float64) {
+		w.family(name, typ, help)
+		for _, pool := range pools {
+			w.sample(name, []metricLabel{{name: "size", value: pool.size}}, value(pool.metrics))
+		}
+	}
+
+	sized("shockwave_buffer_pool_gets_total", "counter", "Total number of buffer Get operations",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Gets) })
+	sized("shockwave_buffer_pool_puts_total", "counter", "Total number of buffer Put operations",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Puts) })
+	sized("shockwave_buffer_pool_hits_total", "counter", "Total number of buffer pool hits (reuse)",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Hits) })
+	sized("shockwave_buffer_pool_misses_total", "counter", "Total number of buffer pool misses (new allocation)",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Misses) })
+	sized("shockwave_buffer_pool_discards_total", "counter", "Total number of buffers discarded (wrong size)",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Discards) })
+	sized("shockwave_buffer_pool_hit_rate", "gauge", "Current buffer pool hit rate (0-100%)",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return p.HitRate })
+	sized("shockwave_buffer_pool_bytes_allocated_total", "counter", "Total bytes allocated",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Allocated) })
+	sized("shockwave_buffer_pool_bytes_reused_total", "counter", "Total bytes reused from pool",
+		func(p *shockwavebuf.SizedPoolMetrics) float64 { return float64(p.Reused) })
+
+	w.gauge("shockwave_buffer_pool_global_hit_rate", "Global buffer pool hit rate across all sizes (0-100%)", metrics.GlobalHitRate)
+	w.gauge("shockwave_buffer_pool_memory_allocated_bytes", "Total memory allocated across all pools", float64(metrics.MemoryAllocated))
+	w.gauge("shockwave_buffer_pool_memory_reused_bytes", "Total memory reused across all pools", float64(metrics.MemoryReused))
+	w.gauge("shockwave_buffer_pool_reuse_efficiency", "Memory reuse efficiency (0-100%)", metrics.ReuseEfficiency)
+}
+
+// appendLabelValue appends a label value, escaping \, " and newlines.
+func appendLabelValue(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			b = append(b, `\\`...)
+		case '"':
+			b = append(b, `\"`...)
+		case '\n':
+			b = append(b, `\n`...)
+		default:
+			b = append(b, s[i])
+		}
+	}
+	return b
+}
+
+// appendMetricValue appends a sample value: integers without exponent or
+// fraction, other values in the shortest representation.
+func appendMetricValue(b []byte, v float64) []byte {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.AppendInt(b, int64(v), 10)
+	}
+	return strconv.AppendFloat(b, v, 'g', -1, 64)
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrapeMetrics returns the metrics exposition of app.
+func scrapeMetrics(t *testing.T, app *App, accept string) (string, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	return w.Header().Get("Content-Type"), w.Body.String()
+}
+
+// TestMetrics tests request metrics labels, histograms and pool statistics.
+func TestMetrics(t *testing.T) {
+	app := New()
+
+	// Registered before Metrics: its rejections must still be recorded
+	app.Use(func(next Handler) Handler {
+		return func(c *Context) error {
+			if c.GetHeader("Authorization") == "" && c.Path() == "/admin" {
+				return ErrUnauthorized
+			}
+			return next(c)
+		}
+	})
+	app.Get("/users/:id", func(c *Context) error {
+		return c.Blob(200, "text/plain", []byte("user "+c.Param("id")))
+	})
+	app.Get("/admin", func(c *Context) error { return c.NoContent() })
+	app.Post("/fail", func(c *Context) error { return errors.New("database down") })
+	app.Metrics("/metrics")
+
+	for _, path := range []string{"/users/1", "/users/22", "/admin", "/unknown"} {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/fail", nil))
+
+	contentType, body := scrapeMetrics(t, app, "")
+	if contentType != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("unexpected Content-Type %q", contentType)
+	}
+
+	users := `method="GET",route="/users/:id",status="2xx"`
+	want := []string{
+		"# HELP bolt_http_requests_total Total HTTP requests\n# TYPE bolt_http_requests_total counter\n",
+		"bolt_http_requests_total{" + users + "} 2\n",
+		`bolt_http_requests_total{method="GET",route="/admin",status="4xx"} 1` + "\n",
+		`bolt_http_requests_total{method="POST",route="/fail",status="5xx"} 1` + "\n",
+		"bolt_http_requests_in_flight 1\n",
+		"# TYPE bolt_http_request_duration_seconds histogram\n",
+		"bolt_http_request_duration_seconds_bucket{" + users + `,le="0.005"} `,
+		"bolt_http_request_duration_seconds_bucket{" + users + `,le="+Inf"} 2` + "\n",
+		"bolt_http_request_duration_seconds_count{" + users + "} 2\n",
+		"bolt_http_response_size_bytes_bucket{" + users + `,le="100"} 2` + "\n",
+		"bolt_http_response_size_bytes_count{" + users + "} 2\n",
+		`shockwave_buffer_pool_gets_total{size="2kb"} `,
+		"# TYPE shockwave_buffer_pool_reuse_efficiency gauge\n",
+		"bolt_context_pool_allocated_total ",
+	}
+	for _, w := range want {
+		if !strings.Contains(body, w) {
+			t.Errorf("expected %q in:\n%s", w, body)
+		}
+	}
+
+	// Raw paths, unmatched requests and idle status classes have no series
+	for _, unwanted := range []string{"/users/1", "/unknown", `route="/users/:id",status="5xx"`, "shockwave_server_", "# EOF"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("unexpected %q in:\n%s", unwanted, body)
+		}
+	}
+
+	// The scrape itself is recorded
+	_, body = scrapeMetrics(t, app, "")
+	if !strings.Contains(body, `bolt_http_requests_total{method="GET",route="/metrics",status="2xx"} 1`) {
+		t.Errorf("expected the previous scrape to be recorded:\n%s", body)
+	}
+}
+
+// TestMetricsOpenMetrics tests exposition format negotiation.
+func TestMetricsOpenMetrics(t *testing.T) {
+	app := New()
+	app.Get("/", func(c *Context) error { return c.NoContent() })
+	app.Metrics("/metrics")
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	// Accept header sent by Prometheus
+	accept := "application/openmetrics-text;version=1.0.0;q=0.5,text/plain;version=0.0.4;q=0.3,*/*;q=0.1"
+	contentType, body := scrapeMetrics(t, app, accept)
+	if contentType != "application/openmetrics-text; version=1.0.0; charset=utf-8" {
+		t.Errorf("unexpected Content-Type %q", contentType)
+	}
+	for _, w := range []string{
+		"# TYPE bolt_http_requests counter\n",
+		`bolt_http_requests_total{method="GET",route="/",status="2xx"} 1` + "\n",
+		"# TYPE bolt_context_pool_allocated counter\n",
+		"# TYPE bolt_http_requests_in_flight gauge\n",
+	} {
+		if !strings.Contains(body, w) {
+			t.Errorf("expected %q in:\n%s", w, body)
+		}
+	}
+	if !strings.HasSuffix(body, "\n# EOF\n") {
+		t.Error("expected # EOF at the end")
+	}
+
+	if contentType, _ := scrapeMetrics(t, app, "text/plain, application/openmetrics-text;q=0.5"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("expected the text format, got %q", contentType)
+	}
+}
+
+// TestMetricsWithConfig tests custom buckets, skipped paths and misuse.
+func TestMetricsWithConfig(t *testing.T) {
+	app := New()
+	app.Get("/health", func(c *Context) error { return c.NoContent() })
+	app.Get("/", func(c *Context) error { return c.NoContent() })
+	app.MetricsWithConfig("/metrics", MetricsConfig{
+		DurationBuckets: []float64{1, 60},
+		SkipPaths:       []string{"/health", "/metrics"},
+	})
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	_, body := scrapeMetrics(t, app, "")
+	for _, w := range []string{
+		`bolt_http_request_duration_seconds_bucket{method="GET",route="/",status="2xx",le="1"} 1`,
+		`bolt_http_request_duration_seconds_bucket{method="GET",route="/",status="2xx",le="60"} 1`,
+		`bolt_http_response_size_bytes_bucket{method="GET",route="/",status="2xx",le="1e+07"} 1`,
+		"bolt_http_requests_in_flight 0\n",
+	} {
+		if !strings.Contains(body, w) {
+			t.Errorf("expected %q in:\n%s", w, body)
+		}
+	}
+	if strings.Contains(body, `le="0.005"`) || strings.Contains(body, "/health") {
+		t.Errorf("unexpected default buckets or skipped path:\n%s", body)
+	}
+
+	mustPanic := func(name string, fn func()) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected panic", name)
+			}
+		}()
+		fn()
+	}
+	mustPanic("enabled twice", func() { app.Metrics("/metrics2") })
+	mustPanic("unsorted buckets", func() {
+		New().MetricsWithConfig("/metrics", MetricsConfig{SizeBuckets: []float64{10, 1}})
+	})
+}
+
+// TestMetricsServer tests response sizes and server statistics over
+// Shockwave (net/http only reports Content-Length, see BytesWritten).
+func TestMetricsServer(t *testing.T) {
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+
+	ts.app.Get("/hello", func(c *Context) error {
+		return c.JSON(200, map[string]string{"message": "Hello, World!"})
+	})
+	ts.app.Metrics("/metrics")
+
+	ts.Get("/hello").AssertStatus(t, 200)
+	resp := ts.Get("/metrics").AssertStatus(t, 200)
+
+	body := string(resp.body)
+	for _, w := range []string{
+		`bolt_http_response_size_bytes_sum{method="GET",route="/hello",status="2xx"} 28`,
+		"# TYPE shockwave_server_requests_total counter\nshockwave_server_requests_total 2\n",
+		"shockwave_server_active_connections ",
+		"shockwave_server_start_time_seconds ",
+	} {
+		if !strings.Contains(body, w) {
+			t.Errorf("expected %q in:\n%s", w, body)
+		}
+	}
+}
+
+// BenchmarkMetrics benchmarks request recording.
+func BenchmarkMetrics(b *testing.B) {
+	m := newHTTPMetrics(DefaultMetricsConfig())
+	handler := m.middleware(func(c *Context) error {
+		return nil
+	})
+
+	ctx := &Context{}
+	ctx.SetMethod("GET")
+	ctx.route = "/users/:id"
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = handler(ctx)
+	}
+}
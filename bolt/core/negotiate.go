@@ -0,0 +1,179 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/bolt/pool/buffers"
+)
+
+// Negotiate sends v in the format the client prefers, chosen from the
+// registered encoders (see App.RegisterEncoder) by the Accept header.
+//
+// Selection follows RFC 9110 §12.5.1: each encoder gets the quality of the
+// most specific Accept range matching it ("application/json" over
+// "application/*" over "*/*"); the highest quality wins, ties go to the
+// encoder registered first. Encoders that reject the value with
+// ErrUnsupportedValue are skipped. A missing Accept header accepts anything,
+// so the first registered encoder (JSON by default) is used.
+//
+// Returns ErrNotAcceptable (406) if no acceptable encoder can send v.
+// Responses carry "Vary: Accept" for caches.
+//
+// Example:
+//
+//	app.Get("/users/:id", func(c *bolt.Context) error {
+//	    user, err := users.Get(c.Context(), c.Param("id"))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // Accept: application/xml                 → XML
+//	    // Accept: application/msgpack, */*;q=0.1  → MessagePack
+//	    // Accept: text/html                       → 406
+//	    return c.Negotiate(200, user)
+//	})
+func (c *Context) Negotiate(status int, v any) error {
+	c.Vary("Accept")
+
+	ranges := parseAccept(c.GetHeader("Accept"))
+	candidates := negotiateEncoders(c.codecs().encoders, ranges)
+
+	buf := buffers.AcquireMediumJSONBuffer()
+	defer buffers.ReleaseJSONBuffer(buf)
+
+	for _, enc := range candidates {
+		buf.Reset()
+		err := enc.enc.Encode(buf, v)
+		if errors.Is(err, ErrUnsupportedValue) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return c.Blob(status, enc.contentType, buf.Bytes())
+	}
+	return ErrNotAcceptable
+}
+
+// acceptRange is a media range of an Accept header.
+type acceptRange struct {
+	typ, subtype string // Lowercase; "*" for wildcards
+	q            int    // Quality in thousandths (0-1000)
+}
+
+// parseAccept parses an Accept header. An empty header accepts anything.
+// Malformed ranges are ignored.
+func parseAccept(header string) []acceptRange {
+	if strings.TrimSpace(header) == "" {
+		return []acceptRange{{typ: "*", subtype: "*", q: 1000}}
+	}
+
+	ranges := make([]acceptRange, 0, strings.Count(header, ",")+1)
+	for part := range strings.SplitSeq(header, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaRange)), "/")
+		if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+
+		r := acceptRange{typ: typ, subtype: subtype, q: 1000}
+		for param := range strings.SplitSeq(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				r.q = ParseQuality(strings.TrimSpace(value))
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// ParseQuality parses a qvalue ("0.8") into thousandths (RFC 9110 §12.4.2).
+// Invalid values count as 0 (not acceptable).
+//
+// Example:
+//
+//	bolt.ParseQuality("0.8") // 800
+func ParseQuality(s string) int {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || f > 1 {
+		return 0
+	}
+	return int(f*1000 + 0.5)
+}
+
+// matchAccept returns the quality and specificity (0-2) of the most specific
+// range matching mediaType, or -1 if none does.
+func matchAccept(ranges []acceptRange, mediaType string) (q, specificity int) {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity = -1, -1
+	for _, r := range ranges {
+		var s int
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q, specificity
+}
+
+// negotiateEncoders returns the acceptable encoders, preferred first.
+func negotiateEncoders(encoders []registeredEncoder, ranges []acceptRange) []registeredEncoder {
+	type candidate struct {
+		enc            registeredEncoder
+		q, specificity int
+	}
+	candidates := make([]candidate, 0, len(encoders))
+	for _, enc := range encoders {
+		q, specificity := matchAccept(ranges, enc.mediaType)
+		if q > 0 {
+			candidates = append(candidates, candidate{enc, q, specificity})
+		}
+	}
+
+	// Stable: equal quality and specificity keep registration order
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.q != b.q {
+			return b.q - a.q
+		}
+		return b.specificity - a.specificity
+	})
+
+	result := make([]registeredEncoder, len(candidates))
+	for i := range candidates {
+		result[i] = candidates[i].enc
+	}
+	return result
+}
+
+// Vary adds a request header field to the Vary response header, keeping
+// fields already listed. Use it when the response depends on a request
+// header, so caches store one variant per value.
+//
+// Example:
+//
+//	c.Vary("Accept-Language")
+func (c *Context) Vary(field string) {
+	current := c.GetResponseHeader("Vary")
+	if current == "" {
+		c.SetHeader("Vary", field)
+		return
+	}
+	for existing := range strings.SplitSeq(current, ",") {
+		if strings.EqualFold(strings.TrimSpace(existing), field) {
+			return
+		}
+	}
+	c.SetHeader("Vary", current+", "+field)
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type negotiateUser struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+// TestNegotiate tests encoder selection from the Accept header.
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		value       any
+		status      int
+		contentType string
+	}{
+		{"no accept", "", negotiateUser{1, "ada"}, 200, "application/json"},
+		{"wildcard", "*/*", negotiateUser{1, "ada"}, 200, "application/json"},
+		{"exact", "application/xml", negotiateUser{1, "ada"}, 200, "application/xml; charset=utf-8"},
+		{"quality", "application/json;q=0.5, application/msgpack", negotiateUser{1, "ada"}, 200, "application/msgpack"},
+		{"specificity", "application/*;q=0.2, application/cbor;q=0.9, */*;q=0.1", negotiateUser{1, "ada"}, 200, "application/cbor"},
+		{"excluded", "application/json;q=0, */*", negotiateUser{1, "ada"}, 200, "application/xml; charset=utf-8"},
+		{"case insensitive", "TEXT/Plain", "hello", 200, "text/plain; charset=utf-8"},
+		{"unsupported value skipped", "text/plain, application/json;q=0.5", negotiateUser{1, "ada"}, 200, "application/json"},
+		{"protobuf", "application/x-protobuf, application/json;q=0.1", wrapperspb.String("ada"), 200, "application/x-protobuf"},
+		{"protobuf rejected", "application/x-protobuf", negotiateUser{1, "ada"}, 406, ""},
+		{"not acceptable", "text/html", negotiateUser{1, "ada"}, 406, ""},
+		{"malformed", "nonsense, */json", negotiateUser{1, "ada"}, 406, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.Get("/value", func(c *Context) error {
+				return c.Negotiate(200, tt.value)
+			})
+
+			req := httptest.NewRequest("GET", "/value", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == 200 && w.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, w.Header().Get("Content-Type"))
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+// TestNegotiateRoundTrip tests that Negotiate output binds back with Bind.
+func TestNegotiateRoundTrip(t *testing.T) {
+	for _, mediaType := range []string{"application/json", "application/xml", "application/msgpack", "application/x-msgpack", "application/cbor"} {
+		t.Run(mediaType, func(t *testing.T) {
+			app := New()
+			app.Get("/user", func(c *Context) error {
+				return c.Negotiate(200, negotiateUser{7, "grace"})
+			})
+			app.Post("/user", func(c *Context) error {
+				var u negotiateUser
+				if err := c.Bind(&u); err != nil {
+					return err
+				}
+				return c.JSON(200, u)
+			})
+
+			req := httptest.NewRequest("GET", "/user", nil)
+			req.Header.Set("Accept", mediaType)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			req = httptest.NewRequest("POST", "/user", w.Body)
+			req.Header.Set("Content-Type", w.Header().Get("Content-Type"))
+			w = httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Code != 200 || w.Body.String() != "{\"id\":7,\"name\":\"grace\"}\n" {
+				t.Errorf("unexpected round trip: %d %s", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		app := New()
+		app.Post("/echo", func(c *Context) error {
+			var msg wrapperspb.StringValue
+			if err := c.Bind(&msg); err != nil {
+				return err
+			}
+			return c.Negotiate(200, &msg)
+		})
+
+		body, _ := proto.Marshal(wrapperspb.String("grace"))
+		req := httptest.NewRequest("POST", "/echo", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("Accept", "application/x-protobuf")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		var msg wrapperspb.StringValue
+		if err := proto.Unmarshal(w.Body.Bytes(), &msg); err != nil || msg.Value != "grace" {
+			t.Errorf("unexpected protobuf echo %q (%v)", msg.Value, err)
+		}
+	})
+}
+
+// TestRegisterCodecs tests custom encoders and decoders.
+func TestRegisterCodecs(t *testing.T) {
+	app := New()
+
+	// New format, preferred after the built-ins
+	app.RegisterEncoder("text/csv; charset=utf-8", EncoderFunc(func(w io.Writer, v any) error {
+		u, ok := v.(negotiateUser)
+		if !ok {
+			return ErrUnsupportedValue
+		}
+		_, err := io.WriteString(w, "id,name\n"+u.Name+"\n")
+		return err
+	}))
+	app.RegisterDecoder("text/csv", DecoderFunc(func(r io.Reader, v any) error {
+		data, _ := io.ReadAll(r)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		v.(*negotiateUser).Name = lines[len(lines)-1]
+		return nil
+	}))
+
+	// Replace the JSON codec with encoding/json (indented, unknown fields allowed)
+	app.RegisterEncoder("application/json", EncoderFunc(func(w io.Writer, v any) error {
+		enc := stdjson.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}))
+	app.RegisterDecoder("application/json", DecoderFunc(func(r io.Reader, v any) error {
+		return stdjson.NewDecoder(r).Decode(v)
+	}))
+
+	app.Get("/user", func(c *Context) error {
+		return c.Negotiate(200, negotiateUser{1, "ada"})
+	})
+	app.Post("/user", func(c *Context) error {
+		var u negotiateUser
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.JSON(200, u)
+	})
+
+	do := func(method, accept, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/user", strings.NewReader(body))
+		req.Header.Set("Accept", accept)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("GET", "text/csv", "", ""); w.Body.String() != "id,name\nada\n" || w.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected CSV response %q (%s)", w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	// The replaced JSON encoder keeps its position ahead of XML
+	if w := do("GET", "", "", ""); w.Header().Get("Content-Type") != "application/json" || !strings.Contains(w.Body.String(), "\n  \"id\": 1") {
+		t.Errorf("expected the custom JSON encoder, got %q", w.Body.String())
+	}
+
+	// c.JSON and Bind use the replaced codec (unknown fields allowed)
+	if w := do("POST", "", "application/json", `{"name":"grace","extra":true}`); w.Code != 200 || !strings.Contains(w.Body.String(), `"name": "grace"`) {
+		t.Errorf("expected the custom JSON codec, got %d %q", w.Code, w.Body.String())
+	}
+	if w := do("POST", "", "text/csv; charset=utf-8", "id,name\ngrace\n"); w.Code != 200 || !strings.Contains(w.Body.String(), `"name": "grace"`) {
+		t.Errorf("expected the CSV decoder, got %d %q", w.Code, w.Body.String())
+	}
+
+	// Other apps keep the built-in codecs
+	other := New()
+	other.Post("/user", func(c *Context) error {
+		var u negotiateUser
+		return c.Bind(&u)
+	})
+	req := httptest.NewRequest("POST", "/user", strings.NewReader(`{"name":"grace","extra":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	other.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Errorf("expected 400 for unknown fields with the built-in decoder, got %d", w.Code)
+	}
+}
+
+// TestVary tests that Vary fields accumulate without duplicates.
+func TestVary(t *testing.T) {
+	c := &Context{}
+	c.Vary("Accept-Encoding")
+	c.Vary("Accept")
+	c.Vary("accept")
+	if got := c.GetResponseHeader("Vary"); got != "Accept-Encoding, Accept" {
+		t.Errorf("expected \"Accept-Encoding, Accept\", got %q", got)
+	}
+}
+
+// TestParseQuality tests qvalue parsing.
+func TestParseQuality(t *testing.T) {
+	for s, want := range map[string]int{
+		"1": 1000, "0.8": 800, "0.001": 1, "0": 0,
+		"1.5": 0, "-0.1": 0, "": 0, "high": 0,
+	} {
+		if got := ParseQuality(s); got != want {
+			t.Errorf("ParseQuality(%q): expected %d, got %d", s, want, got)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package core
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	json "github.com/goccy/go-json"
+)
+
+// OpenAPI 3.1 document generation from registered routes.
+//
+// Everything is derived from what the App already knows:
+//   - Method and path of every route (":id" and "*path" become path parameters)
+//   - Request types attached with ChainLink.Request or inferred by PostT/PutT/PatchT:
+//     `path`/`query`/`header` tagged fields become parameters, the others the body
+//   - Response types attached with ChainLink.Response or inferred from Data[T]
+//   - `validate` tags (required, min, max, len, oneof, email, regex) as schema constraints
+//   - Summary, description, tags and deprecation attached through ChainLink
+//
+// The document is rebuilt on every call, so it never drifts from the code.
+
+// openAPIVersion is the OpenAPI Specification version emitted.
+const openAPIVersion = "3.1.0"
+
+// OpenAPIInfo describes the API (OpenAPI "info" object).
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIDocument is an OpenAPI 3.1 document.
+type OpenAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents         `json:"components,omitempty"`
+}
+
+// OpenAPIPathItem maps lowercase HTTP methods to operations.
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation describes a single route.
+type OpenAPIOperation struct {
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	OperationID string                     `json:"operationId,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter describes a path, query or header parameter.
+type OpenAPIParameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// OpenAPIRequestBody describes a request body.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a response.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType associates a schema with a media type.
+type OpenAPIMediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// OpenAPIComponents holds reusable schemas referenced with $ref.
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// OpenAPI builds the OpenAPI 3.1 document for every registered route.
+//
+// Routes marked with ChainLink.Hidden are skipped.
+//
+// Example:
+//
+//	doc := app.OpenAPI(bolt.OpenAPIInfo{Title: "Users API", Version: "1.2.0"})
+//	data, _ := json.MarshalIndent(doc, "", "  ")
+//	os.WriteFile("openapi.json", data, 0o644)
+func (app *App) OpenAPI(info OpenAPIInfo) *OpenAPIDocument {
+	gen := newSchemaGenerator()
+	doc := &OpenAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]OpenAPIPathItem),
+	}
+
+	for _, route := range app.routes {
+		if route.Doc.Hidden {
+			continue
+		}
+
+		path, pathParams := openAPIPath(route.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(OpenAPIPathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(string(route.Method))] = gen.operation(route, pathParams)
+	}
+
+	if len(gen.schemas) > 0 {
+		doc.Components = &OpenAPIComponents{Schemas: gen.schemas}
+	}
+	return doc
+}
+
+// JSON returns the document as indented JSON.
+func (doc *OpenAPIDocument) JSON() ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// YAML returns the document as YAML.
+func (doc *OpenAPIDocument) YAML() ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+// OpenAPIConfig configures the routes registered by ServeOpenAPI.
+type OpenAPIConfig struct {
+	// Info describes the API.
+	Info OpenAPIInfo
+
+	// JSONPath serves the document as JSON.
+	// Default: "/openapi.json"
+	JSONPath string
+
+	// YAMLPath serves the document as YAML.
+	// Default: "/openapi.yaml"
+	YAMLPath string
+
+	// DocsPath serves a self-contained HTML explorer for the document.
+	// Default: "/docs"
+	DocsPath string
+
+	// DisableDocs disables the HTML explorer.
+	DisableDocs bool
+}
+
+// DefaultOpenAPIConfig returns the default OpenAPI configuration.
+func DefaultOpenAPIConfig() OpenAPIConfig {
+	return OpenAPIConfig{
+		Info:     OpenAPIInfo{Title: "API", Version: "1.0.0"},
+		JSONPath: "/openapi.json",
+		YAMLPath: "/openapi.yaml",
+		DocsPath: "/docs",
+	}
+}
+
+// ServeOpenAPI registers routes serving the OpenAPI document and explorer.
+//
+// The document is generated on each request, so routes registered after
+// ServeOpenAPI are included. The documentation routes themselves are hidden.
+//
+// Example:
+//
+//	app.ServeOpenAPI(bolt.OpenAPIConfig{
+//	    Info: bolt.OpenAPIInfo{Title: "Users API", Version: "1.2.0"},
+//	})
+//	// GET /openapi.json, GET /openapi.yaml, GET /docs
+func (app *App) ServeOpenAPI(config OpenAPIConfig) {
+	defaults := DefaultOpenAPIConfig()
+	if config.Info.Title == "" {
+		config.Info.Title = defaults.Info.Title
+	}
+	if config.Info.Version == "" {
+		config.Info.Version = defaults.Info.Version
+	}
+	if config.JSONPath == "" {
+		config.JSONPath = defaults.JSONPath
+	}
+	if config.YAMLPath == "" {
+		config.YAMLPath = defaults.YAMLPath
+	}
+	if config.DocsPath == "" {
+		config.DocsPath = defaults.DocsPath
+	}
+
+	app.Get(config.JSONPath, func(c *Context) error {
+		data, err := app.OpenAPI(config.Info).JSON()
+		if err != nil {
+			return err
+		}
+		return c.Blob(200, "application/json", data)
+	}).Hidden()
+
+	app.Get(config.YAMLPath, func(c *Context) error {
+		data, err := app.OpenAPI(config.Info).YAML()
+		if err != nil {
+			return err
+		}
+		return c.Blob(200, "application/yaml", data)
+	}).Hidden()
+
+	if !config.DisableDocs {
+		page := []byte(strings.Replace(openAPIExplorerHTML, "{{SPEC_URL}}", strconv.Quote(config.JSONPath), 1))
+		app.Get(config.DocsPath, func(c *Context) error {
+			return c.Blob(200, "text/html; charset=utf-8", page)
+		}).Hidden()
+	}
+}
+
+// openAPIPath converts a Bolt route path to an OpenAPI path template.
+//
+// Example: "/v:version<uuid>/files/*path" → "/v{version}/files/{path}", [version path]
+func openAPIPath(path string) (string, []routeSegment) {
+	segments := strings.Split(path, "/")
+	var params []routeSegment
+	for i, s := range segments {
+		seg := parseRouteSegment(s)
+		if seg.kind == 0 {
+			continue
+		}
+		if seg.name == "" {
+			seg.name = "wildcard"
+		}
+		params = append(params, seg)
+		segments[i] = seg.prefix + "{" + seg.name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operation builds the OpenAPI operation of a route.
+func (g *schemaGenerator) operation(route *RouteInfo, pathParams []routeSegment) *OpenAPIOperation {
+	doc := &route.Doc
+	op := &OpenAPIOperation{
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		OperationID: doc.OperationID,
+		Tags:        doc.Tags,
+		Deprecated:  doc.Deprecated,
+		Responses:   make(map[string]OpenAPIResponse),
+	}
+
+	// Parameters declared by the request type
+	var declared, others []OpenAPIParameter
+	if st := structType(doc.Request); st != nil {
+		for _, f := range structInfoOf(st).fields {
+			for _, in := range [...]string{tagPath, tagQuery, tagHeader} {
+				name := f.tags[in]
+				if name == "" || name == "-" {
+					continue
+				}
+				param := OpenAPIParameter{
+					Name:     name,
+					In:       in,
+					Required: in == tagPath || hasRule(f.rules, "required"),
+					Schema:   g.fieldSchema(st.FieldByIndex(f.index).Type, f.rules),
+				}
+				if in == tagPath {
+					declared = append(declared, param)
+				} else {
+					others = append(others, param)
+				}
+			}
+		}
+	}
+
+	// Path parameters in route order (typed if declared, from the constraint otherwise)
+	for _, seg := range pathParams {
+		param := OpenAPIParameter{Name: seg.name, In: tagPath, Required: true, Schema: constraintSchema(seg.constraint)}
+		for _, p := range declared {
+			if p.Name == seg.name {
+				param = p
+			}
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+	op.Parameters = append(op.Parameters, others...)
+
+	// Request body (not for GET/HEAD)
+	if doc.Request != nil && route.Method != MethodGet && route.Method != MethodHead {
+		if mediaType, schema := g.bodySchema(doc.Request); schema != nil {
+			op.RequestBody = &OpenAPIRequestBody{
+				Required: true,
+				Content:  map[string]OpenAPIMediaType{mediaType: {Schema: schema}},
+			}
+		}
+	}
+
+	// Responses
+	for _, r := range doc.Responses {
+		description := r.Description
+		if description == "" {
+			description = http.StatusText(r.Status)
+		}
+		resp := OpenAPIResponse{Description: description}
+		if r.Type != nil {
+			resp.Content = map[string]OpenAPIMediaType{mimeJSON: {Schema: g.schemaOf(r.Type)}}
+		}
+		op.Responses[strconv.Itoa(r.Status)] = resp
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = OpenAPIResponse{Description: http.StatusText(200)}
+	}
+	return op
+}
+
+// bodySchema returns the media type and schema of the request body.
+//
+// For struct types, only fields not bound from path, query or headers are
+// part of the body. Returns a nil schema if there are no body fields.
+func (g *schemaGenerator) bodySchema(t reflect.Type) (string, *Schema) {
+	st := structType(t)
+	if st == nil {
+		return mimeJSON, g.schemaOf(t)
+	}
+
+	var bodyFields []fieldInfo
+	multipartBody := false
+	for _, f := range structInfoOf(st).fields {
+		if f.tags[tagPath] != "" || f.tags[tagQuery] != "" || f.tags[tagHeader] != "" {
+			continue
+		}
+		if ft := st.FieldByIndex(f.index).Type; ft == fileHeaderType || ft == fileHeaderSliceType {
+			multipartBody = true
+		}
+		bodyFields = append(bodyFields, f)
+	}
+	if len(bodyFields) == 0 {
+		return "", nil
+	}
+
+	if multipartBody {
+		return mimeMultipartForm, g.objectSchema(st, bodyFields, tagForm)
+	}
+	if len(bodyFields) == len(structInfoOf(st).fields) {
+		// Whole struct is the body: reuse the component schema
+		return mimeJSON, g.schemaOf(t)
+	}
+	return mimeJSON, g.objectSchema(st, bodyFields, tagJSON)
+}
+
+// constraintSchema returns the schema of a path parameter constraint.
+func constraintSchema(constraint string) *Schema {
+	switch constraint {
+	case "":
+		return &Schema{Type: "string"}
+	case "int":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "uint":
+		zero := 0.0
+		return &Schema{Type: "integer", Format: "int64", Minimum: &zero}
+	case "float":
+		return &Schema{Type: "number", Format: "double"}
+	case "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	case "alpha":
+		return &Schema{Type: "string", Pattern: "^[A-Za-z]+$"}
+	case "alnum":
+		return &Schema{Type: "string", Pattern: "^[A-Za-z0-9]+$"}
+	}
+	return &Schema{Type: "string", Pattern: "^(?:" + constraint + ")$"}
+}
+
+// structType returns the struct type behind t (through pointers), or nil.
+func structType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Implements(dataEnvelopeType) || isScalarStruct(t) {
+		return nil
+	}
+	return t
+}
+
+// hasRule reports whether rules contain the named rule.
+func hasRule(rules []validationRule, name string) bool {
+	for _, r := range rules {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIExplorerHTML is a dependency-free HTML explorer for the document.
+const openAPIExplorerHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>API Reference</title>
+<style>
+body{font-family:system-ui,-apple-system,sans-serif;margin:0 auto;max-width:960px;padding:1rem 2rem;color:#1f2328}
+h1{margin-bottom:0}.version{color:#656d76}
+details{border:1px solid #d0d7de;border-radius:6px;margin:.5rem 0}
+summary{cursor:pointer;padding:.5rem .75rem;font-family:ui-monospace,monospace}
+.method{display:inline-block;min-width:4.5rem;font-weight:bold;text-transform:uppercase}
+.get{color:#0969da}.post{color:#1a7f37}.put{color:#9a6700}.patch{color:#8250df}.delete{color:#cf222e}
+.deprecated summary{text-decoration:line-through;opacity:.6}
+.body{padding:0 .75rem .75rem}
+pre{background:#f6f8fa;padding:.75rem;border-radius:6px;overflow:auto;font-size:.85rem}
+table{border-collapse:collapse}td,th{border:1px solid #d0d7de;padding:.25rem .5rem;text-align:left}
+</style>
+</head>
+<body>
+<div id="app">Loading…</div>
+<script>
+const specURL = {{SPEC_URL}};
+const el = (tag, attrs, ...children) => {
+  const e = document.createElement(tag);
+  Object.assign(e, attrs || {});
+  children.forEach(c => e.append(c));
+  return e;
+};
+const json = v => el("pre", {textContent: JSON.stringify(v, null, 2)});
+fetch(specURL).then(r => r.json()).then(spec => {
+  const app = document.getElementById("app");
+  app.textContent = "";
+  app.append(el("h1", {textContent: spec.info.title}),
+    el("p", {className: "version", textContent: "Version " + spec.info.version + " · "},
+      el("a", {href: specURL, textContent: "OpenAPI document"})));
+  if (spec.info.description) app.append(el("p", {textContent: spec.info.description}));
+  for (const [path, item] of Object.entries(spec.paths)) {
+    for (const [method, op] of Object.entries(item)) {
+      const body = el("div", {className: "body"});
+      if (op.description) body.append(el("p", {textContent: op.description}));
+      if (op.tags) body.append(el("p", {textContent: "Tags: " + op.tags.join(", ")}));
+      if (op.parameters) {
+        const table = el("table", {}, el("tr", {}, el("th", {textContent: "Name"}), el("th", {textContent: "In"}),
+          el("th", {textContent: "Required"}), el("th", {textContent: "Schema"})));
+        op.parameters.forEach(p => table.append(el("tr", {}, el("td", {textContent: p.name}),
+          el("td", {textContent: p.in}), el("td", {textContent: p.required ? "yes" : "no"}),
+          el("td", {textContent: JSON.stringify(p.schema)}))));
+        body.append(el("h4", {textContent: "Parameters"}), table);
+      }
+      if (op.requestBody) body.append(el("h4", {textContent: "Request body"}), json(op.requestBody.content));
+      body.append(el("h4", {textContent: "Responses"}), json(op.responses));
+      const summary = el("summary", {}, el("span", {className: "method " + method, textContent: method}), " " + path,
+        op.summary ? " — " + op.summary : "");
+      app.append(el("details", {className: op.deprecated ? "deprecated" : ""}, summary, body));
+    }
+  }
+  if (spec.components) app.append(el("h2", {textContent: "Schemas"}), json(spec.components.schemas));
+}).catch(err => { document.getElementById("app").textContent = "Failed to load " + specURL + ": " + err; });
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Schema is a JSON Schema (OpenAPI 3.1 dialect) describing a Go type.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+}
+
+var (
+	// dataEnvelopeType is implemented by every Data[T].
+	dataEnvelopeType = reflect.TypeOf((*dataEnvelope)(nil)).Elem()
+
+	// validationErrorBodyType documents the 400 body of invalid typed requests.
+	validationErrorBodyType = reflect.TypeOf(ErrorResponse{})
+
+	// schemaPackagePath matches package paths in generic type names
+	// ("Page[github.com/acme/api.User]" → "Page[User]").
+	schemaPackagePath = regexp.MustCompile(`[\w./-]*[/.]`)
+)
+
+// schemaGenerator converts Go types to schemas, collecting named structs
+// as reusable components.
+type schemaGenerator struct {
+	schemas map[string]*Schema      // Component name → schema
+	names   map[reflect.Type]string // Struct type → component name
+}
+
+// newSchemaGenerator creates an empty schema generator.
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema of t.
+//
+// Named structs are emitted once under components/schemas and referenced
+// with $ref (which also handles self-referencing types).
+func (g *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer && t != fileHeaderType {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(dataEnvelopeType):
+		return g.envelopeSchema(reflect.Zero(t).Interface().(dataEnvelope).envelopeValueType())
+	case t.Kind() == reflect.Struct && isScalarStruct(t):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is marshaled as a base64 string
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.componentRef(t)
+	}
+
+	// Interfaces and anything else: any value
+	return &Schema{}
+}
+
+// envelopeSchema documents the {"data", "meta", "error"} body written for Data[T].
+func (g *schemaGenerator) envelopeSchema(value reflect.Type) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data":  g.schemaOf(value),
+			"meta":  {Type: "object"},
+			"error": {Type: "string"},
+		},
+	}
+}
+
+// componentRef registers struct type t as a component and returns a $ref to it.
+// Anonymous structs are inlined.
+func (g *schemaGenerator) componentRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.objectSchema(t, structInfoOf(t).fields, tagJSON)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		// Register before generating fields: t may refer to itself
+		g.schemas[name] = nil
+		g.schemas[name] = g.objectSchema(t, structInfoOf(t).fields, tagJSON)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName returns a unique, URL-safe component name for t.
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := schemaPackagePath.ReplaceAllString(t.Name(), "")
+	name = strings.NewReplacer("[", "_", "]", "", ",", "_", "*", "", " ", "").Replace(name)
+
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := g.schemas[unique]; !taken {
+			return unique
+		}
+		unique = name + strconv.Itoa(i)
+	}
+}
+
+// objectSchema builds an object schema from fields of struct t, naming
+// properties after the given tag (falling back to `json`, then the Go name).
+func (g *schemaGenerator) objectSchema(t reflect.Type, fields []fieldInfo, tag string) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range fields {
+		name := f.tags[tag]
+		if name == "" {
+			name = f.tags[tagJSON]
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.name
+		}
+
+		schema.Properties[name] = g.fieldSchema(t.FieldByIndex(f.index).Type, f.rules)
+		if hasRule(f.rules, "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// fieldSchema returns the schema of a field, constrained by its `validate` rules.
+func (g *schemaGenerator) fieldSchema(t reflect.Type, rules []validationRule) *Schema {
+	schema := g.schemaOf(t)
+	if schema.Ref != "" || len(rules) == 0 {
+		return schema
+	}
+
+	for _, r := range rules {
+		switch r.name {
+		case "min", "max", "len":
+			n, num := int(r.num), r.num
+			switch schema.Type {
+			case "string":
+				if r.name != "max" {
+					schema.MinLength = &n
+				}
+				if r.name != "min" {
+					schema.MaxLength = &n
+				}
+			case "array":
+				if r.name != "max" {
+					schema.MinItems = &n
+				}
+				if r.name != "min" {
+					schema.MaxItems = &n
+				}
+			case "integer", "number":
+				if r.name == "min" {
+					schema.Minimum = &num
+				} else if r.name == "max" {
+					schema.Maximum = &num
+				}
+			}
+		case "oneof":
+			for _, opt := range r.options {
+				if schema.Type == "integer" || schema.Type == "number" {
+					if n, err := strconv.ParseFloat(opt, 64); err == nil {
+						schema.Enum = append(schema.Enum, n)
+						continue
+					}
+				}
+				schema.Enum = append(schema.Enum, opt)
+			}
+		case "email":
+			schema.Format = "email"
+		case "regex":
+			schema.Pattern = r.param
+		}
+	}
+	return schema
+}
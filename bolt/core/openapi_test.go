@@ -0,0 +1,231 @@
+package core
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type openAPIAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type openAPIUser struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name" validate:"required,min=2,max=64"`
+	Role      string          `json:"role" validate:"oneof=admin member"`
+	Email     string          `json:"email,omitempty" validate:"omitempty,email"`
+	CreatedAt time.Time       `json:"created_at"`
+	Address   *openAPIAddress `json:"address,omitempty"`
+	Friends   []openAPIUser   `json:"friends,omitempty"`
+	Internal  string          `json:"-"`
+}
+
+type openAPIUpdateUser struct {
+	ID      int    `path:"id"`
+	DryRun  bool   `query:"dry_run"`
+	TraceID string `header:"X-Trace-ID"`
+	Name    string `json:"name" validate:"required"`
+}
+
+type openAPIUpload struct {
+	Title string                `form:"title" validate:"required"`
+	File  *multipart.FileHeader `form:"file"`
+}
+
+// newOpenAPITestApp registers a documented API.
+func newOpenAPITestApp() *App {
+	app := New()
+	GetT(app, "/users/:id", func(c *Context) Data[openAPIUser] {
+		return OK(openAPIUser{})
+	}).Summary("Get a user").Tags("users").OperationID("getUser")
+	PostT(app, "/users", func(c *Context, req openAPIUser) Data[openAPIUser] {
+		return Created(req)
+	}).Tags("users")
+	PatchT(app, "/users/:id", func(c *Context, req openAPIUpdateUser) Data[openAPIUser] {
+		return OK(openAPIUser{})
+	})
+	app.Post("/uploads", func(c *Context) error { return nil }).
+		Request(openAPIUpload{}).
+		Response(204, nil)
+	app.Get("/files/*path", func(c *Context) error { return nil }).Deprecated()
+	app.Get("/internal", func(c *Context) error { return nil }).Hidden()
+	return app
+}
+
+// TestOpenAPIDocument tests paths, operations, parameters and metadata.
+func TestOpenAPIDocument(t *testing.T) {
+	doc := newOpenAPITestApp().OpenAPI(OpenAPIInfo{Title: "Test", Version: "1.0"})
+
+	if doc.OpenAPI != "3.1.0" || doc.Info.Title != "Test" {
+		t.Errorf("unexpected header: %s %+v", doc.OpenAPI, doc.Info)
+	}
+	if _, ok := doc.Paths["/internal"]; ok {
+		t.Error("hidden route should not be documented")
+	}
+	if op := doc.Paths["/files/{path}"]["get"]; op == nil || !op.Deprecated {
+		t.Error("expected deprecated /files/{path}")
+	}
+
+	get := doc.Paths["/users/{id}"]["get"]
+	if get == nil {
+		t.Fatal("missing GET /users/{id}")
+	}
+	if get.Summary != "Get a user" || get.OperationID != "getUser" || len(get.Tags) != 1 {
+		t.Errorf("unexpected metadata: %+v", get)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Name != "id" || !get.Parameters[0].Required {
+		t.Errorf("unexpected parameters: %+v", get.Parameters)
+	}
+	if get.RequestBody != nil {
+		t.Error("GET should have no request body")
+	}
+
+	patch := doc.Paths["/users/{id}"]["patch"]
+	if patch == nil {
+		t.Fatal("missing PATCH /users/{id}")
+	}
+	params := make(map[string]OpenAPIParameter)
+	for _, p := range patch.Parameters {
+		params[p.In+":"+p.Name] = p
+	}
+	if p := params["path:id"]; p.Schema == nil || p.Schema.Type != "integer" {
+		t.Errorf("expected integer path id, got %+v", p)
+	}
+	if _, ok := params["query:dry_run"]; !ok {
+		t.Error("expected dry_run query parameter")
+	}
+	if _, ok := params["header:X-Trace-ID"]; !ok {
+		t.Error("expected X-Trace-ID header parameter")
+	}
+	body := patch.RequestBody.Content["application/json"].Schema
+	if len(body.Properties) != 1 || body.Properties["name"] == nil || body.Required[0] != "name" {
+		t.Errorf("expected body with only name, got %+v", body)
+	}
+	if _, ok := patch.Responses["400"]; !ok {
+		t.Error("expected documented 400 response")
+	}
+
+	upload := doc.Paths["/uploads"]["post"]
+	media, ok := upload.RequestBody.Content["multipart/form-data"]
+	if !ok {
+		t.Fatalf("expected multipart body, got %+v", upload.RequestBody.Content)
+	}
+	if f := media.Schema.Properties["file"]; f == nil || f.Format != "binary" {
+		t.Errorf("expected binary file property, got %+v", f)
+	}
+	if r, ok := upload.Responses["204"]; !ok || r.Content != nil {
+		t.Errorf("expected empty 204 response, got %+v", upload.Responses)
+	}
+}
+
+// TestOpenAPISchemas tests schema inference from Data[T] and validate tags.
+func TestOpenAPISchemas(t *testing.T) {
+	doc := newOpenAPITestApp().OpenAPI(OpenAPIInfo{Title: "Test", Version: "1.0"})
+
+	resp := doc.Paths["/users"]["post"].Responses["201"].Content["application/json"].Schema
+	if resp.Properties["data"] == nil || resp.Properties["data"].Ref != "#/components/schemas/openAPIUser" {
+		t.Errorf("expected Data envelope referencing openAPIUser, got %+v", resp)
+	}
+
+	user := doc.Components.Schemas["openAPIUser"]
+	if user == nil {
+		t.Fatal("missing openAPIUser component")
+	}
+	if _, ok := user.Properties["Internal"]; ok {
+		t.Error(`json:"-" field should be skipped`)
+	}
+	name := user.Properties["name"]
+	if name.MinLength == nil || *name.MinLength != 2 || name.MaxLength == nil || *name.MaxLength != 64 {
+		t.Errorf("unexpected name constraints: %+v", name)
+	}
+	if role := user.Properties["role"]; len(role.Enum) != 2 {
+		t.Errorf("expected enum, got %+v", role)
+	}
+	if user.Properties["email"].Format != "email" {
+		t.Error("expected email format")
+	}
+	if user.Properties["created_at"].Format != "date-time" {
+		t.Error("expected date-time format")
+	}
+	if user.Properties["address"].Ref != "#/components/schemas/openAPIAddress" {
+		t.Errorf("unexpected address schema: %+v", user.Properties["address"])
+	}
+	if friends := user.Properties["friends"]; friends.Type != "array" || friends.Items.Ref != "#/components/schemas/openAPIUser" {
+		t.Errorf("unexpected self-referencing friends schema: %+v", friends)
+	}
+	if len(user.Required) != 1 || user.Required[0] != "name" {
+		t.Errorf("unexpected required list: %v", user.Required)
+	}
+	if doc.Components.Schemas["ErrorResponse"] == nil {
+		t.Error("expected ErrorResponse component")
+	}
+}
+
+// TestServeOpenAPI tests the JSON, YAML and HTML explorer routes.
+func TestServeOpenAPI(t *testing.T) {
+	app := newOpenAPITestApp()
+	app.ServeOpenAPI(OpenAPIConfig{Info: OpenAPIInfo{Title: "Served", Version: "2.0"}})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	if w.Code != 200 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("unexpected response: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	var doc OpenAPIDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Info.Title != "Served" {
+		t.Errorf("unexpected info: %+v", doc.Info)
+	}
+	if _, ok := doc.Paths["/openapi.json"]; ok {
+		t.Error("documentation routes should be hidden")
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.yaml", nil))
+	yaml := w.Body.String()
+	if w.Code != 200 || w.Header().Get("Content-Type") != "application/yaml" {
+		t.Fatalf("unexpected response: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{`openapi: "3.1.0"`, "  title: Served\n", "  /users/{id}:\n", `"201":`, `$ref: "#/components/schemas/openAPIUser"`} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("YAML missing %q:\n%s", want, yaml)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/docs", nil))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `const specURL = "/openapi.json";`) {
+		t.Errorf("unexpected explorer page: %d", w.Code)
+	}
+}
+
+// TestJSONToYAML tests YAML emission and quoting.
+func TestJSONToYAML(t *testing.T) {
+	in := `{"a":1,"b":"true","c":[{"x":"y","z":[]},"plain",["n"]],"d":{},"e":"","f":"a: b","g":null,"h":"line\nbreak"}`
+	want := `a: 1
+b: "true"
+c:
+  - x: "y"
+    z: []
+  - plain
+  - - "n"
+d: {}
+e: ""
+f: "a: b"
+g: null
+h: "line\nbreak"
+`
+	got, err := jsonToYAML([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("unexpected YAML:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Minimal JSON → YAML conversion for serving OpenAPI documents.
+//
+// The document is marshaled to JSON first (so `json` tags stay the single
+// source of truth), then re-emitted as block-style YAML preserving key order.
+// Strings are quoted whenever the plain form could be misread (numbers,
+// booleans, "200" response codes, indicators, ": " sequences).
+
+// yamlNode is a decoded JSON value: scalar, mapping or sequence.
+type yamlNode struct {
+	scalar string      // Rendered scalar (when not a mapping or sequence)
+	keys   []string    // Mapping keys in document order
+	values []*yamlNode // Mapping values or sequence items
+	isMap  bool
+	isSeq  bool
+}
+
+// jsonToYAML converts a JSON document to YAML.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("bolt: unexpected data after JSON document")
+	}
+
+	var buf bytes.Buffer
+	if root.isMap || root.isSeq {
+		writeYAMLNode(&buf, root, 0)
+	} else {
+		buf.WriteString(root.scalar)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeYAMLNode decodes the next JSON value from dec.
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		node := &yamlNode{isMap: v == '{', isSeq: v == '['}
+		for dec.More() {
+			if node.isMap {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, yamlString(key.(string)))
+			}
+			child, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, child)
+		}
+		// Closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yamlNode{scalar: yamlString(v)}, nil
+	case json.Number:
+		return &yamlNode{scalar: v.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(v)}, nil
+	default:
+		return &yamlNode{scalar: "null"}, nil
+	}
+}
+
+// writeYAMLNode writes a mapping or sequence at the given indentation.
+func writeYAMLNode(buf *bytes.Buffer, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	if node.isMap {
+		for i, key := range node.keys {
+			buf.WriteString(pad)
+			buf.WriteString(key)
+			buf.WriteByte(':')
+			writeYAMLValue(buf, node.values[i], indent+2)
+		}
+		return
+	}
+
+	for _, item := range node.values {
+		if (item.isMap || item.isSeq) && len(item.values) > 0 {
+			// Render the item one level deeper, then put "- " in its first indent
+			var nested bytes.Buffer
+			writeYAMLNode(&nested, item, indent+2)
+			buf.WriteString(pad)
+			buf.WriteString("- ")
+			buf.Write(nested.Bytes()[indent+2:])
+			continue
+		}
+		buf.WriteString(pad)
+		buf.WriteByte('-')
+		writeYAMLValue(buf, item, indent+2)
+	}
+}
+
+// writeYAMLValue writes the value after a "key:" or "-" indicator.
+func writeYAMLValue(buf *bytes.Buffer, node *yamlNode, indent int) {
+	switch {
+	case node.isMap && len(node.values) == 0:
+		buf.WriteString(" {}\n")
+	case node.isSeq && len(node.values) == 0:
+		buf.WriteString(" []\n")
+	case node.isMap || node.isSeq:
+		buf.WriteByte('\n')
+		writeYAMLNode(buf, node, indent)
+	default:
+		buf.WriteByte(' ')
+		buf.WriteString(node.scalar)
+		buf.WriteByte('\n')
+	}
+}
+
+// yamlString renders s as a plain scalar if unambiguous, double-quoted otherwise.
+func yamlString(s string) string {
+	if yamlPlainSafe(s) {
+		return s
+	}
+	// JSON escapes are valid in YAML double-quoted scalars
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// yamlPlainSafe reports whether s reads back as the same string when unquoted.
+func yamlPlainSafe(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`.+0123456789") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// RemoteAddr returns the network address ("IP:port") of the peer: the
+// client, or the closest proxy when the app runs behind one.
+//
+// Use RealIP for the client address reported by trusted proxies.
+func (c *Context) RemoteAddr() string {
+	if c.httpReq != nil {
+		return c.httpReq.RemoteAddr
+	}
+	if c.shockwaveReq != nil {
+		return c.shockwaveReq.RemoteAddr
+	}
+	return ""
+}
+
+// RealIP returns the IP address of the client.
+//
+// Forwarding headers are only honored when the peer is one of
+// Config.TrustedProxies; otherwise anyone could claim any address. The
+// Forwarded header (RFC 7239) is used first, then X-Forwarded-For, then
+// X-Real-IP. Forwarding lists are walked from the right, skipping trusted
+// proxies: the first untrusted address is the client.
+//
+// Returns "" when the peer address is unknown (e.g. in unit tests).
+//
+// Example:
+//
+//	app := bolt.NewWithConfig(bolt.Config{
+//	    TrustedProxies: []string{"10.0.0.0/8", "fd00::/8"},
+//	})
+//
+//	app.Get("/whoami", func(c *bolt.Context) error {
+//	    // Peer 10.0.0.2 sent X-Forwarded-For: 203.0.113.7, 10.0.0.1
+//	    return c.Text(200, c.RealIP()) // "203.0.113.7"
+//	})
+func (c *Context) RealIP() string {
+	if client, trusted := c.forwarded(); trusted {
+		return client.ip.String()
+	}
+	return stripPort(c.RemoteAddr())
+}
+
+// Scheme returns the request scheme as seen by the client: "http" or
+// "https".
+//
+// Behind trusted proxies it honors the proto parameter of the Forwarded
+// header or X-Forwarded-Proto; otherwise it reports whether the request was
+// received over TLS.
+func (c *Context) Scheme() string {
+	if client, trusted := c.forwarded(); trusted && client.proto != "" {
+		return client.proto
+	}
+	if (c.httpReq != nil && c.httpReq.TLS != nil) || (c.shockwaveReq != nil && c.shockwaveReq.IsTLS()) {
+		return "https"
+	}
+	return "http"
+}
+
+// Host returns the host (and port, if any) requested by the client.
+//
+// Behind trusted proxies it honors the host parameter of the Forwarded
+// header or X-Forwarded-Host; otherwise it returns the Host header.
+func (c *Context) Host() string {
+	if client, trusted := c.forwarded(); trusted && client.host != "" {
+		return client.host
+	}
+	if c.httpReq != nil {
+		return c.httpReq.Host
+	}
+	return c.GetHeader("Host")
+}
+
+// forwardedClient describes the client side of a request, as reported by
+// trusted proxies.
+type forwardedClient struct {
+	ip    netip.Addr // Invalid if unknown
+	proto string     // "http", "https" or "" if not reported
+	host  string     // "" if not reported
+}
+
+// forwarded resolves the client of the request. trusted reports whether the
+// peer is a trusted proxy, i.e. whether forwarding headers were used.
+func (c *Context) forwarded() (client forwardedClient, trusted bool) {
+	if c.app == nil || len(c.app.trustedProxies) == 0 {
+		return client, false // No proxies to trust (and no parsing to do)
+	}
+	client.ip = parseNode(c.RemoteAddr())
+	if !client.ip.IsValid() || !c.trustedProxy(client.ip) {
+		return client, false
+	}
+
+	// RFC 7239: each proxy appends an element for the hop it received from
+	if header := c.headerValues("Forwarded"); header != "" {
+		elements := strings.Split(header, ",")
+		for i := len(elements) - 1; i >= 0; i-- {
+			element := parseForwardedElement(elements[i])
+			// Reported by a trusted proxy: proto and host are reliable
+			if element.proto != "" {
+				client.proto = element.proto
+			}
+			if element.host != "" {
+				client.host = element.host
+			}
+			if !element.ip.IsValid() {
+				break // "unknown" or obfuscated: the proxy is the best we know
+			}
+			client.ip = element.ip
+			if !c.trustedProxy(client.ip) {
+				break
+			}
+		}
+		return client, true
+	}
+
+	// De facto headers
+	client.proto = forwardedProto(firstValue(c.GetHeader("X-Forwarded-Proto")))
+	client.host = firstValue(c.GetHeader("X-Forwarded-Host"))
+	if header := c.headerValues("X-Forwarded-For"); header != "" {
+		hops := strings.Split(header, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := parseNode(hops[i])
+			if !ip.IsValid() {
+				break
+			}
+			client.ip = ip
+			if !c.trustedProxy(ip) {
+				break
+			}
+		}
+		return client, true
+	}
+	if ip := parseNode(c.GetHeader("X-Real-IP")); ip.IsValid() {
+		client.ip = ip
+	}
+	return client, true
+}
+
+// trustedProxy reports whether ip belongs to Config.TrustedProxies.
+func (c *Context) trustedProxy(ip netip.Addr) bool {
+	if c.app == nil {
+		return false
+	}
+	for _, prefix := range c.app.trustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerValues returns all values of a request header joined with ", ".
+// Proxies may add a separate header line instead of extending the first one.
+func (c *Context) headerValues(name string) string {
+	if c.httpReq != nil {
+		return strings.Join(c.httpReq.Header.Values(name), ", ")
+	}
+	if c.shockwaveReq != nil {
+		var values []string
+		c.shockwaveReq.Header.VisitAll(func(key, value []byte) bool {
+			if strings.EqualFold(string(key), name) {
+				values = append(values, string(value))
+			}
+			return true
+		})
+		return strings.Join(values, ", ")
+	}
+	return c.GetHeader(name)
+}
+
+// parseForwardedElement parses one element of a Forwarded header, e.g.
+// `for="[2001:db8::17]:4711";proto=https;host=example.com`.
+func parseForwardedElement(element string) (client forwardedClient) {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(key) {
+		case "for":
+			client.ip = parseNode(value)
+		case "proto":
+			client.proto = forwardedProto(value)
+		case "host":
+			client.host = value
+		}
+	}
+	return client
+}
+
+// parseNode parses a node of a forwarding header: "192.0.2.1",
+// "192.0.2.1:4711", "2001:db8::1" or "[2001:db8::1]:4711". Other values
+// ("unknown", obfuscated identifiers) are invalid.
+func parseNode(node string) netip.Addr {
+	node = strings.TrimSpace(node)
+	if addrPort, err := netip.ParseAddrPort(node); err == nil {
+		return addrPort.Addr().Unmap()
+	}
+	node = strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+	if ip, err := netip.ParseAddr(node); err == nil {
+		return ip.Unmap().WithZone("")
+	}
+	return netip.Addr{}
+}
+
+// forwardedProto normalizes a forwarded scheme, ignoring unknown values.
+func forwardedProto(proto string) string {
+	switch strings.ToLower(proto) {
+	case "http":
+		return "http"
+	case "https":
+		return "https"
+	}
+	return ""
+}
+
+// firstValue returns the first value of a comma-separated header.
+func firstValue(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(first)
+}
+
+// stripPort returns the host of "host:port", or addr if it has no port.
+func stripPort(addr string) string {
+	if addr == "" {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// parseTrustedProxies parses Config.TrustedProxies: CIDR prefixes or single
+// addresses.
+func parseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		ip, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		ip = ip.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRealIP tests client address, scheme and host resolution.
+func TestRealIP(t *testing.T) {
+	app := NewWithConfig(Config{TrustedProxies: []string{"10.0.0.0/8", "2001:db8:ffff::1"}})
+
+	var ip, scheme, host, remote string
+	app.Get("/", func(c *Context) error {
+		ip, scheme, host, remote = c.RealIP(), c.Scheme(), c.Host(), c.RemoteAddr()
+		return c.NoContent()
+	})
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers [][2]string
+		tls     bool
+		ip      string
+		scheme  string
+		host    string
+	}{
+		{"direct", "203.0.113.7:1234", nil, false, "203.0.113.7", "http", "example.com"},
+		{"direct tls", "203.0.113.7:1234", nil, true, "203.0.113.7", "https", "example.com"},
+		{"untrusted peer", "203.0.113.7:1234", [][2]string{
+			{"X-Forwarded-For", "192.0.2.1"}, {"X-Forwarded-Proto", "https"}, {"X-Forwarded-Host", "evil.example"},
+			{"Forwarded", "for=192.0.2.1"}, {"X-Real-IP", "192.0.2.1"},
+		}, false, "203.0.113.7", "http", "example.com"},
+		{"x-forwarded", "10.0.0.1:1234", [][2]string{
+			{"X-Forwarded-For", "192.0.2.1, 203.0.113.7, 10.0.0.2"}, {"X-Forwarded-Proto", "https, http"}, {"X-Forwarded-Host", "api.example.com"},
+		}, false, "203.0.113.7", "https", "api.example.com"},
+		{"x-forwarded-for all trusted", "10.0.0.1:1234", [][2]string{{"X-Forwarded-For", "10.0.0.5, 10.0.0.2"}}, false, "10.0.0.5", "http", "example.com"},
+		{"x-forwarded-for lines", "10.0.0.1:1234", [][2]string{{"X-Forwarded-For", "192.0.2.1"}, {"X-Forwarded-For", "203.0.113.9"}}, false, "203.0.113.9", "http", "example.com"},
+		{"x-forwarded-for garbage", "10.0.0.1:1234", [][2]string{{"X-Forwarded-For", "203.0.113.7, nonsense"}}, false, "10.0.0.1", "http", "example.com"},
+		{"x-real-ip", "10.0.0.1:1234", [][2]string{{"X-Real-IP", "203.0.113.7"}}, false, "203.0.113.7", "http", "example.com"},
+		{"forwarded", "10.0.0.1:1234", [][2]string{
+			{"Forwarded", `for=192.0.2.60;proto=https;host="shop.example", for=10.0.0.3;proto=http`},
+			{"X-Forwarded-For", "198.51.100.1"},
+		}, false, "192.0.2.60", "https", "shop.example"},
+		{"forwarded ipv6", "[2001:db8:ffff::1]:443", [][2]string{{"Forwarded", `For="[2001:db8:cafe::17]:4711"`}}, false, "2001:db8:cafe::17", "http", "example.com"},
+		{"forwarded unknown", "10.0.0.1:1234", [][2]string{{"Forwarded", "for=unknown;proto=https"}}, false, "10.0.0.1", "https", "example.com"},
+		{"forwarded invalid proto", "10.0.0.1:1234", [][2]string{{"Forwarded", "for=192.0.2.60;proto=gopher"}}, true, "192.0.2.60", "https", "example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			req.RemoteAddr = tt.remote
+			for _, header := range tt.headers {
+				req.Header.Add(header[0], header[1])
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			app.ServeHTTP(httptest.NewRecorder(), req)
+
+			if ip != tt.ip || scheme != tt.scheme || host != tt.host || remote != tt.remote {
+				t.Errorf("got %s %s://%s from %s, want %s %s://%s from %s", ip, scheme, host, remote, tt.ip, tt.scheme, tt.host, tt.remote)
+			}
+		})
+	}
+
+	// Unit-test contexts have no peer
+	if c := (&Context{}); c.RealIP() != "" || c.RemoteAddr() != "" || c.Scheme() != "http" {
+		t.Errorf("unexpected address %q %q %q", c.RealIP(), c.RemoteAddr(), c.Scheme())
+	}
+}
+
+// TestTrustedProxiesInvalid tests that invalid trusted proxies panic.
+func TestTrustedProxiesInvalid(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "proxy.internal", ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %q", proxy)
+				}
+			}()
+			NewWithConfig(Config{TrustedProxies: []string{proxy}})
+		}()
+	}
+}
@@ -59,9 +59,7 @@ func (c *Context) JSONOK() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(200)
-		_, err := c.shockwaveRes.Write(jsonOKBytes)
-		return err
+		return c.writeShockwave(200, jsonOKBytes)
 	}
 
 	// Fallback to http.ResponseWriter (testing)
@@ -91,9 +89,7 @@ func (c *Context) JSONCreated() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(201)
-		_, err := c.shockwaveRes.Write(jsonCreatedBytes)
-		return err
+		return c.writeShockwave(201, jsonCreatedBytes)
 	}
 
 	c.httpRes.WriteHeader(201)
@@ -122,9 +118,7 @@ func (c *Context) JSONDeleted() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(200)
-		_, err := c.shockwaveRes.Write(jsonDeletedBytes)
-		return err
+		return c.writeShockwave(200, jsonDeletedBytes)
 	}
 
 	c.httpRes.WriteHeader(200)
@@ -153,9 +147,7 @@ func (c *Context) JSONUpdated() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(200)
-		_, err := c.shockwaveRes.Write(jsonUpdatedBytes)
-		return err
+		return c.writeShockwave(200, jsonUpdatedBytes)
 	}
 
 	c.httpRes.WriteHeader(200)
@@ -184,9 +176,7 @@ func (c *Context) JSONAccepted() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(202)
-		_, err := c.shockwaveRes.Write(jsonAcceptedBytes)
-		return err
+		return c.writeShockwave(202, jsonAcceptedBytes)
 	}
 
 	c.httpRes.WriteHeader(202)
@@ -245,9 +235,7 @@ func (c *Context) JSONBadRequest() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(400)
-		_, err := c.shockwaveRes.Write(json400Bytes)
-		return err
+		return c.writeShockwave(400, json400Bytes)
 	}
 
 	c.httpRes.WriteHeader(400)
@@ -278,9 +266,7 @@ func (c *Context) JSONUnauthorized() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(401)
-		_, err := c.shockwaveRes.Write(json401Bytes)
-		return err
+		return c.writeShockwave(401, json401Bytes)
 	}
 
 	c.httpRes.WriteHeader(401)
@@ -311,9 +297,7 @@ func (c *Context) JSONForbidden() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(403)
-		_, err := c.shockwaveRes.Write(json403Bytes)
-		return err
+		return c.writeShockwave(403, json403Bytes)
 	}
 
 	c.httpRes.WriteHeader(403)
@@ -345,9 +329,7 @@ func (c *Context) JSONNotFound() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(404)
-		_, err := c.shockwaveRes.Write(json404Bytes)
-		return err
+		return c.writeShockwave(404, json404Bytes)
 	}
 
 	c.httpRes.WriteHeader(404)
@@ -369,9 +351,7 @@ func (c *Context) JSONMethodNotAllowed() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(405)
-		_, err := c.shockwaveRes.Write(json405Bytes)
-		return err
+		return c.writeShockwave(405, json405Bytes)
 	}
 
 	c.httpRes.WriteHeader(405)
@@ -393,9 +373,7 @@ func (c *Context) JSONTooManyRequests() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(429)
-		_, err := c.shockwaveRes.Write(json429Bytes)
-		return err
+		return c.writeShockwave(429, json429Bytes)
 	}
 
 	c.httpRes.WriteHeader(429)
@@ -428,9 +406,7 @@ func (c *Context) JSONInternalError() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(500)
-		_, err := c.shockwaveRes.Write(json500Bytes)
-		return err
+		return c.writeShockwave(500, json500Bytes)
 	}
 
 	c.httpRes.WriteHeader(500)
@@ -452,9 +428,7 @@ func (c *Context) JSONServiceUnavailable() error {
 	c.written = true
 
 	if c.shockwaveRes != nil {
-		c.shockwaveRes.WriteHeader(503)
-		_, err := c.shockwaveRes.Write(json503Bytes)
-		return err
+		return c.writeShockwave(503, json503Bytes)
 	}
 
 	c.httpRes.WriteHeader(503)
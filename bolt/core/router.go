@@ -20,13 +20,17 @@ type Router struct {
 	// Dynamic routes (with parameters)
 	trees map[HTTPMethod]*node
 
+	// Methods with at least one route (Allow header order, replaced on write)
+	methods []HTTPMethod
+
 	mu sync.RWMutex
 }
 
 // node represents a node in the radix tree.
 //
 // Uses byte slices instead of strings for zero-allocation tree traversal.
-// Optimized with Gin/Echo patterns: indices for O(1) lookup, priority for hot path reordering.
+// Optimized with Gin/Echo patterns: indices for O(1) child lookup.
+// The tree is read-only during lookups, which share the router's read lock.
 //
 // ✅ CPU OPTIMIZATION: Field ordering optimized for cache locality (first 64 bytes = one cache line)
 // Hot path fields (accessed during every lookup) are placed first to minimize cache misses.
@@ -50,9 +54,13 @@ type node struct {
 	// ===== SECOND CACHE LINE - MEDIUM PRIORITY =====
 	paramNameBytes []byte // Parameter name (24 bytes) - used when isParam=true
 	indices        string // Child first-bytes index (16 bytes) - O(1) lookup
-	priority       uint32 // Access frequency counter (4 bytes)
+	priority       uint32 // Set on insert; not updated by lookups (4 bytes)
 	// padding: 4 bytes
 
+	// Parameter matching rules (nil for plain :param nodes - checked only when set)
+	prefix     []byte           // Static text before the parameter in its segment ("v" in "v:version")
+	constraint *paramConstraint // Value constraint (":id<int>")
+
 	// ===== THIRD CACHE LINE - COLD (registration only) =====
 	// These fields are ONLY used during route registration, not during lookup
 	path      string // Legacy path string (16 bytes)
@@ -72,8 +80,11 @@ func NewRouter() *Router {
 // Path formats:
 //   - Static: "/users" (exact match)
 //   - Parameter: "/users/:id" (single parameter)
+//   - Constrained parameter: "/users/:id<int>", "/v:version<uuid>" (see router_constraints.go)
 //   - Wildcard: "/files/*path" (catch-all)
 //
+// Precedence among siblings: static > constrained parameter > parameter > wildcard.
+//
 // Performance:
 //   - Static routes use hash map (O(1) lookup)
 //   - Dynamic routes use radix tree (O(log n) lookup)
@@ -81,6 +92,8 @@ func (r *Router) Add(method HTTPMethod, path string, handler Handler) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.methods = insertMethod(r.methods, method)
+
 	// Check if path is static (no parameters or wildcards)
 	if !strings.Contains(path, ":") && !strings.Contains(path, "*") {
 		// Static route: use hash map
@@ -111,24 +124,14 @@ func (r *Router) Add(method HTTPMethod, path string, handler Handler) {
 // Use LookupBytes() directly for absolute zero allocations.
 func (r *Router) Lookup(method HTTPMethod, path string) (Handler, map[string]string) {
 	// Use zero-allocation LookupBytes internally
-	handler, params, paramCount := r.LookupBytes(method, []byte(path))
+	handler, params := r.LookupBytes(method, []byte(path))
 
 	if handler == nil {
 		return nil, nil
 	}
 
 	// Convert to map only if there are parameters (backward compatibility)
-	if paramCount == 0 {
-		return handler, nil
-	}
-
-	// Create map and convert byte slices to strings
-	paramsMap := make(map[string]string, paramCount)
-	for i := 0; i < paramCount; i++ {
-		paramsMap[string(params[i].Key)] = string(params[i].Value)
-	}
-
-	return handler, paramsMap
+	return handler, params.toMap()
 }
 
 // ParamPair holds a parameter key-value pair as byte slices (zero-copy).
@@ -137,56 +140,148 @@ type ParamPair struct {
 	Value []byte
 }
 
+// maxInlineParams is the number of route parameters stored without allocation.
+const maxInlineParams = 8
+
+// Params holds the route parameters extracted by LookupBytes, in path order.
+//
+// The first 8 parameters are stored inline (zero allocation); routes with
+// more parameters spill the rest into a heap-allocated slice, so every
+// parameter is always returned.
+type Params struct {
+	inline   [maxInlineParams]ParamPair
+	overflow []ParamPair // Parameters beyond maxInlineParams (rare)
+	n        int
+}
+
+// Len returns the number of parameters.
+func (p *Params) Len() int {
+	return p.n
+}
+
+// At returns the i-th parameter (0 <= i < Len()).
+func (p *Params) At(i int) ParamPair {
+	if i < maxInlineParams {
+		return p.inline[i]
+	}
+	return p.overflow[i-maxInlineParams]
+}
+
+// Get returns the value of the named parameter.
+//
+// Performance: 0 allocs/op
+func (p *Params) Get(key string) ([]byte, bool) {
+	keyBytes := stringToBytes(key)
+	for i := 0; i < p.n; i++ {
+		if pair := p.At(i); bytesEqual(pair.Key, keyBytes) {
+			return pair.Value, true
+		}
+	}
+	return nil, false
+}
+
+// push appends a parameter.
+//
+//go:inline
+func (p *Params) push(key, value []byte) {
+	if p.n < maxInlineParams {
+		p.inline[p.n] = ParamPair{Key: key, Value: value}
+	} else {
+		// Drop pairs discarded by backtracking before appending
+		p.overflow = append(p.overflow[:p.n-maxInlineParams], ParamPair{Key: key, Value: value})
+	}
+	p.n++
+}
+
+// truncate discards parameters after the first n (backtracking).
+//
+//go:inline
+func (p *Params) truncate(n int) {
+	p.n = n
+}
+
+// toMap converts the parameters to a map (nil if there are none).
+func (p *Params) toMap() map[string]string {
+	if p.n == 0 {
+		return nil
+	}
+	m := make(map[string]string, p.n)
+	for i := 0; i < p.n; i++ {
+		pair := p.At(i)
+		m[string(pair.Key)] = string(pair.Value)
+	}
+	return m
+}
+
+// staticKey returns the "METHOD:PATH" static map key, built in buf when it fits.
+//
+// SAFETY: The returned string may reference buf; use it for map lookups only.
+//
+//go:inline
+func staticKey(buf *[128]byte, method HTTPMethod, pathBytes []byte) string {
+	if len(method)+1+len(pathBytes) > len(buf) {
+		// Long path: allocate rather than truncate (a truncated key could match another route)
+		return string(method) + ":" + string(pathBytes)
+	}
+	n := copy(buf[:], method)
+	buf[n] = ':'
+	n++
+	n += copy(buf[n:], pathBytes)
+	return bytesToString(buf[:n])
+}
+
 // LookupBytes finds a handler for the given method and path (TRUE ZERO-ALLOCATION fast path).
 //
 // Returns the handler and extracted parameters as byte slices WITHOUT map allocation.
-// Parameters are stored in a fixed-size array (up to 8 params) to avoid heap allocations.
+// Up to 8 parameters are stored inline to avoid heap allocations; more spill to a slice.
 //
 // Performance:
 //   - Static routes: ~50ns, 0 allocs/op (unsafe zero-copy map key lookup)
-//   - Dynamic routes (tree search): ~180ns, 0 allocs/op (true zero-allocation)
+//   - Dynamic routes (tree search): ~180ns, 0 allocs/op for ≤8 params (true zero-allocation)
 //
 // Uses unsafe package for zero-copy string conversions during map lookup.
 //
 // SAFETY: The returned byte slices reference the input path buffer.
 // They remain valid as long as the path buffer is not modified or deallocated.
 // ✅ CPU OPTIMIZATION: No defer in hot path (saves ~50ns)
-func (r *Router) LookupBytes(method HTTPMethod, pathBytes []byte) (Handler, [8]ParamPair, int) {
+func (r *Router) LookupBytes(method HTTPMethod, pathBytes []byte) (Handler, Params) {
+	var params Params
+	handler := r.lookupBytes(method, pathBytes, &params)
+	return handler, params
+}
+
+// lookupBytes is LookupBytes writing parameters into params (no copy of the result).
+func (r *Router) lookupBytes(method HTTPMethod, pathBytes []byte, params *Params) Handler {
 	r.mu.RLock()
 
 	// Try static route first (O(1))
 	// Build map key with ZERO allocations using unsafe + stack buffer
 	// Stack buffer is large enough for most paths (128 bytes)
-	var keyBuf [128]byte
-	n := copy(keyBuf[:], method)
-	keyBuf[n] = ':'
-	n++
-	n += copy(keyBuf[n:], pathBytes)
-
-	// Use unsafe zero-copy conversion for map lookup (read-only operation)
 	// SAFETY: key string is only used for map lookup (read-only) within this function
-	key := bytesToString(keyBuf[:n])
+	var keyBuf [128]byte
+	key := staticKey(&keyBuf, method, pathBytes)
 
 	if handler, ok := r.static[key]; ok {
 		r.mu.RUnlock()
-		return handler, [8]ParamPair{}, 0
+		return handler
 	}
 
 	// Try dynamic route (O(log n))
 	root := r.trees[method]
 	if root == nil {
 		r.mu.RUnlock()
-		return nil, [8]ParamPair{}, 0
+		return nil
 	}
 
 	// Search tree with ZERO-ALLOCATION parameter extraction
 	// This is the critical fast path - all byte slice operations, no string conversions
-	var params [8]ParamPair
-	paramCount := 0
-	handler := r.searchNodeBytes(root, pathBytes, 0, &params, &paramCount)
+	handler := r.searchNodeBytes(root, pathBytes, 0, params)
 
 	r.mu.RUnlock()
-	return handler, params, paramCount
+	if handler == nil {
+		params.truncate(0)
+	}
+	return handler
 }
 
 // addToTree adds a route to the radix tree.
@@ -194,83 +289,84 @@ func (r *Router) addToTree(root *node, path string, handler Handler) {
 	segments := splitPath(path)
 	current := root
 
-	for i, segment := range segments {
-		isLast := i == len(segments)-1
-
-		// Check for parameter or wildcard
-		if len(segment) > 0 && segment[0] == ':' {
-			// Parameter node
-			paramName := segment[1:]
-			child := r.findOrCreateChild(current, segment, true, false, paramName)
-			current = child
+	for _, segment := range segments {
+		seg := parseRouteSegment(segment)
 
-			if isLast {
-				child.handler = handler
-			}
-		} else if len(segment) > 0 && segment[0] == '*' {
+		switch seg.kind {
+		case ':':
+			// Parameter node (optionally prefixed and/or constrained)
+			current = r.findOrCreateChild(current, segment, seg)
+		case '*':
 			// Wildcard node (must be last)
-			paramName := segment[1:]
-			child := r.findOrCreateChild(current, segment, false, true, paramName)
+			child := r.findOrCreateChild(current, segment, seg)
 			child.handler = handler
 			return
-		} else {
+		default:
 			// Static node
-			child := r.findOrCreateChild(current, segment, false, false, "")
-			current = child
-
-			if isLast {
-				child.handler = handler
-			}
+			current = r.findOrCreateChild(current, segment, seg)
 		}
 	}
+
+	current.handler = handler
 }
 
 // findOrCreateChild finds or creates a child node.
-// Now maintains indices string for O(1) lookup performance (Gin/Echo pattern).
-func (r *Router) findOrCreateChild(parent *node, path string, isParam, isWild bool, paramName string) *node {
-	// Determine label (first byte of path)
-	var label byte
-	if len(path) > 0 {
-		label = path[0]
-	}
-
-	// Look for existing child using indices for faster search
-	for i, char := range parent.indices {
-		if char == rune(label) {
-			child := parent.children[i]
-			if child.path == path {
-				return child
-			}
-		}
-	}
-
-	// Also check remaining children (in case indices is being built incrementally)
+// Children are kept in precedence order with indices for O(1) lookup (Gin/Echo pattern).
+func (r *Router) findOrCreateChild(parent *node, path string, seg routeSegment) *node {
+	// Look for existing child (same segment text, including constraint)
 	for _, child := range parent.children {
 		if child.path == path {
 			return child
 		}
 	}
 
+	// Determine label (first byte of path)
+	var label byte
+	if len(path) > 0 {
+		label = path[0]
+	}
+
 	// Create new child with both string and byte slice versions
 	child := &node{
 		path:           path,
-		pathBytes:      []byte(path),      // Convert once during registration
-		label:          label,              // Store first byte for quick comparison
-		priority:       1,                  // Initial priority
-		isParam:        isParam,
-		isWild:         isWild,
-		paramName:      paramName,
-		paramNameBytes: []byte(paramName), // Convert once during registration
-		indices:        "",                 // No children yet
+		pathBytes:      []byte(path),          // Convert once during registration
+		label:          label,                 // Store first byte for quick comparison
+		priority:       1,                     // Initial priority
+		isParam:        seg.kind == ':',
+		isWild:         seg.kind == '*',
+		paramName:      seg.name,
+		paramNameBytes: []byte(seg.name), // Convert once during registration
+	}
+	if child.isParam && seg.prefix != "" {
+		child.prefix = []byte(seg.prefix)
+	}
+	if seg.constraint != "" {
+		child.constraint = newParamConstraint(seg.constraint)
 	}
 
-	// Add to parent's children and update indices
-	parent.children = append(parent.children, child)
-	parent.indices += string(label) // Append first byte to indices
+	// Add to parent's children (precedence order) and update indices
+	insertChild(parent, child)
 
 	return child
 }
 
+// matchParam returns the parameter value of segment for a parameter node,
+// checking its static prefix and constraint.
+//
+//go:inline
+func (n *node) matchParam(segment []byte) ([]byte, bool) {
+	if n.prefix != nil {
+		if len(segment) <= len(n.prefix) || !bytesEqual(segment[:len(n.prefix)], n.prefix) {
+			return nil, false
+		}
+		segment = segment[len(n.prefix):]
+	}
+	if n.constraint != nil && !n.constraint.match(segment) {
+		return nil, false
+	}
+	return segment, true
+}
+
 // searchTree searches the radix tree for a matching route.
 func (r *Router) searchTree(root *node, path string) (Handler, map[string]string) {
 	segments := splitPath(path)
@@ -306,10 +402,14 @@ func (r *Router) searchNode(node *node, segments []string, index int, params map
 		}
 	}
 
-	// Try parameter nodes
+	// Try parameter nodes (constrained before plain)
 	for _, child := range node.children {
 		if child.isParam {
-			params[child.paramName] = segment
+			value, ok := child.matchParam(stringToBytes(segment))
+			if !ok {
+				continue
+			}
+			params[child.paramName] = string(value)
 			if handler := r.searchNode(child, segments, index+1, params); handler != nil {
 				return handler
 			}
@@ -338,7 +438,7 @@ func (r *Router) searchNode(node *node, segments []string, index int, params map
 // Performance: 0 allocs/op for ≤8 params (inline array storage)
 //
 //go:inline
-func (r *Router) searchNodeBytes(node *node, pathBytes []byte, start int, params *[8]ParamPair, paramCount *int) Handler {
+func (r *Router) searchNodeBytes(node *node, pathBytes []byte, start int, params *Params) Handler {
 	// ✅ FAST PATH: nil node check
 	if node == nil {
 		return nil
@@ -368,7 +468,7 @@ func (r *Router) searchNodeBytes(node *node, pathBytes []byte, start int, params
 
 	// ✅ FAST PATH: Empty segment - skip to next
 	if len(segment) == 0 {
-		return r.searchNodeBytes(node, pathBytes, segEnd, params, paramCount)
+		return r.searchNodeBytes(node, pathBytes, segEnd, params)
 	}
 
 	// ✅ FAST PATH: No children - early exit
@@ -382,9 +482,9 @@ func (r *Router) searchNodeBytes(node *node, pathBytes []byte, start int, params
 		label := segment[0] // First byte of segment
 
 		// Try exact match first (static nodes) using indices for O(1) lookup
-		for i, char := range node.indices {
+		for i := 0; i < len(node.indices); i++ {
 			// ✅ Quick label check (single byte comparison)
-			if byte(char) != label {
+			if node.indices[i] != label {
 				continue // Skip this child
 			}
 
@@ -402,21 +502,9 @@ func (r *Router) searchNodeBytes(node *node, pathBytes []byte, start int, params
 
 			// ✅ Full path comparison only if label matches
 			if bytesEqual(child.pathBytes, segment) {
-				// ✅ Priority-based reordering (Gin pattern): Increment access count
-				child.priority++
-
-				// ✅ Bubble up hot path if it has higher priority than first child
-				if i > 0 && child.priority > node.children[0].priority {
-					// Swap children
-					node.children[0], node.children[i] = node.children[i], node.children[0]
-
-					// Update indices string (swap characters)
-					indices := []byte(node.indices)
-					indices[0], indices[i] = indices[i], indices[0]
-					node.indices = string(indices)
-				}
-
-				if handler := r.searchNodeBytes(child, pathBytes, segEnd, params, paramCount); handler != nil {
+				// No access-count reordering here: lookups run concurrently
+				// under the read lock and must not modify the tree
+				if handler := r.searchNodeBytes(child, pathBytes, segEnd, params); handler != nil {
 					return handler
 				}
 			}
@@ -429,34 +517,28 @@ func (r *Router) searchNodeBytes(node *node, pathBytes []byte, start int, params
 		// ✅ FAST PATH: Wildcard (catch-all) - immediate return
 		if child.isWild {
 			// Wildcard captures remaining path
-			remaining := pathBytes[segStart:]
-			if *paramCount < 8 {
-				params[*paramCount] = ParamPair{
-					Key:   child.paramNameBytes, // Use pre-converted byte slice (zero-copy)
-					Value: remaining,             // Direct reference to path buffer
-				}
-				*paramCount++
-			}
+			// Key: pre-converted byte slice, value: direct reference to path buffer (zero-copy)
+			params.push(child.paramNameBytes, pathBytes[segStart:])
 			return child.handler
 		}
 
-		// Try parameter nodes
+		// Try parameter nodes (constrained before plain)
 		if child.isParam {
-			// Store parameter as byte slice reference (zero-copy)
-			if *paramCount < 8 {
-				params[*paramCount] = ParamPair{
-					Key:   child.paramNameBytes, // Use pre-converted byte slice (zero-copy)
-					Value: segment,               // Direct reference to path buffer
-				}
-				*paramCount++
+			value, ok := child.matchParam(segment)
+			if !ok {
+				continue
+			}
 
-				if handler := r.searchNodeBytes(child, pathBytes, segEnd, params, paramCount); handler != nil {
-					return handler
-				}
+			// Store parameter as byte slice reference (zero-copy)
+			saved := params.n
+			params.push(child.paramNameBytes, value)
 
-				// Backtrack
-				*paramCount--
+			if handler := r.searchNodeBytes(child, pathBytes, segEnd, params); handler != nil {
+				return handler
 			}
+
+			// Backtrack
+			params.truncate(saved)
 		}
 	}
 
@@ -496,6 +578,7 @@ func splitPath(path string) []string {
 // Static routes use fast-path inline lookup, dynamic routes fall back to LookupBytes().
 //
 // Performance: 0 allocs/op for static routes, 0 allocs/op for ≤8 param dynamic routes
+// (routes with more parameters allocate once for the overflow)
 func (r *Router) ServeHTTP(c *Context) error {
 	method := HTTPMethod(c.MethodBytes())
 	pathBytes := c.PathBytes()
@@ -503,11 +586,7 @@ func (r *Router) ServeHTTP(c *Context) error {
 	// ✅ PHASE 1.2: FAST PATH - Inline static route lookup (no function call overhead)
 	// Build map key with zero allocations using stack buffer
 	var keyBuf [128]byte
-	n := copy(keyBuf[:], method)
-	keyBuf[n] = ':'
-	n++
-	n += copy(keyBuf[n:], pathBytes)
-	key := bytesToString(keyBuf[:n])
+	key := staticKey(&keyBuf, method, pathBytes)
 
 	// Try static route lookup (O(1) hash map)
 	r.mu.RLock()
@@ -520,17 +599,41 @@ func (r *Router) ServeHTTP(c *Context) error {
 
 	// ✅ SLOW PATH: Dynamic route lookup (only if static lookup fails)
 	// This uses the full LookupBytes() for tree traversal
-	handler, params, paramCount := r.LookupBytes(method, pathBytes)
+	var params Params
+	handler := r.lookupBytes(method, pathBytes, &params)
+	if handler == nil && method == MethodHead {
+		// A resource that supports GET supports HEAD (RFC 9110 Section 9.3.2)
+		handler = r.lookupBytes(MethodGet, pathBytes, &params)
+	}
 
 	if handler == nil {
+		// Distinguish 405 from 404 (miss path only)
+		if allow := r.allowed(method, pathBytes); allow != "" {
+			return methodNotAllowed(c, method, allow)
+		}
 		return ErrNotFound
 	}
 
 	// Set parameters in context using zero-copy setParamBytes
 	// No allocations: byte slices reference the path buffer directly
-	for i := 0; i < paramCount; i++ {
-		c.setParamBytes(params[i].Key, params[i].Value)
-	}
+	c.setParams(&params)
 
 	return handler(c)
 }
+
+// allowed returns the Allow header value for a path that has no route under method.
+//
+// Returns "" if the path isn't registered under any other method (404).
+func (r *Router) allowed(method HTTPMethod, pathBytes []byte) string {
+	r.mu.RLock()
+	methods := r.methods
+	r.mu.RUnlock()
+
+	return allowHeader(methods, func(m HTTPMethod) bool {
+		if m == method {
+			return false
+		}
+		var params Params
+		return r.lookupBytes(m, pathBytes, &params) != nil
+	})
+}
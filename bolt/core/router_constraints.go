@@ -0,0 +1,223 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Parameter constraints restrict which values a route parameter matches.
+//
+// Syntax: ":name<constraint>", where constraint is a built-in type or a
+// regular expression matched against the whole value:
+//
+//	/users/:id<int>              int      optionally signed 64-bit integer
+//	/items/:n<uint>              uint     unsigned 64-bit integer
+//	/prices/:p<float>            float    floating point number
+//	/v:version<uuid>             uuid     RFC 4122 textual UUID (8-4-4-4-12 hex)
+//	/tags/:t<alpha>              alpha    ASCII letters
+//	/codes/:c<alnum>             alnum    ASCII letters and digits
+//	/files/:name<[a-z0-9-]+>     regex    anything else (may not contain "/")
+//
+// A value that doesn't satisfy the constraint doesn't match the route, so the
+// request falls through to other routes (or 404). Parameters may follow a
+// static prefix within a segment ("/v:version<uuid>" matches "/v1f0c...").
+
+// routeSegment is a parsed route path segment.
+type routeSegment struct {
+	prefix     string // Static text before the parameter ("v" in "v:version")
+	kind       byte   // 0 (static), ':' (parameter) or '*' (wildcard)
+	name       string // Parameter name
+	constraint string // Constraint expression ("int" in ":id<int>")
+}
+
+// parseRouteSegment parses one "/"-separated segment of a route path.
+//
+// Panics on malformed constraints (route paths are programming errors).
+func parseRouteSegment(seg string) routeSegment {
+	if strings.HasPrefix(seg, "*") {
+		return routeSegment{kind: '*', name: seg[1:]}
+	}
+
+	idx := strings.IndexByte(seg, ':')
+	if idx < 0 {
+		return routeSegment{prefix: seg}
+	}
+
+	s := routeSegment{prefix: seg[:idx], kind: ':', name: seg[idx+1:]}
+	if j := strings.IndexByte(s.name, '<'); j >= 0 {
+		if !strings.HasSuffix(s.name, ">") || j == len(s.name)-2 {
+			panic(fmt.Sprintf("bolt: invalid parameter constraint in route segment %q", seg))
+		}
+		s.name, s.constraint = s.name[:j], s.name[j+1:len(s.name)-1]
+	}
+	return s
+}
+
+// paramConstraint matches parameter values against a constraint.
+type paramConstraint struct {
+	expr  string            // Source expression ("int", "[a-z]+")
+	match func([]byte) bool // Reports whether the value satisfies the constraint
+}
+
+// constraintCache maps expression → *paramConstraint (shared by routers and App.URL).
+var constraintCache sync.Map
+
+// newParamConstraint returns the compiled constraint for expr.
+//
+// Panics if a regular expression constraint is invalid.
+func newParamConstraint(expr string) *paramConstraint {
+	if c, ok := constraintCache.Load(expr); ok {
+		return c.(*paramConstraint)
+	}
+
+	c := &paramConstraint{expr: expr}
+	switch expr {
+	case "int":
+		c.match = isIntParam
+	case "uint":
+		c.match = isUintParam
+	case "float":
+		c.match = isFloatParam
+	case "uuid":
+		c.match = isUUIDParam
+	case "alpha":
+		c.match = func(b []byte) bool { return isASCIIParam(b, false) }
+	case "alnum":
+		c.match = func(b []byte) bool { return isASCIIParam(b, true) }
+	default:
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			panic(fmt.Sprintf("bolt: invalid parameter constraint <%s>: %v", expr, err))
+		}
+		c.match = re.Match
+	}
+
+	cached, _ := constraintCache.LoadOrStore(expr, c)
+	return cached.(*paramConstraint)
+}
+
+// isIntParam reports whether b is a (optionally signed) 64-bit integer.
+func isIntParam(b []byte) bool {
+	digits := b
+	if len(digits) > 0 && digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if !isDigits(digits) {
+		return false
+	}
+	if len(digits) < 19 {
+		return true
+	}
+	// Long values: check range (zero-copy, allocates only on failure)
+	_, err := strconv.ParseInt(bytesToString(b), 10, 64)
+	return err == nil
+}
+
+// isUintParam reports whether b is an unsigned 64-bit integer.
+func isUintParam(b []byte) bool {
+	if !isDigits(b) {
+		return false
+	}
+	if len(b) < 20 {
+		return true
+	}
+	_, err := strconv.ParseUint(bytesToString(b), 10, 64)
+	return err == nil
+}
+
+// isFloatParam reports whether b is a floating point number.
+func isFloatParam(b []byte) bool {
+	if isIntParam(b) {
+		return true
+	}
+	_, err := strconv.ParseFloat(bytesToString(b), 64)
+	return err == nil
+}
+
+// isUUIDParam reports whether b is a textual UUID (8-4-4-4-12 hex digits).
+func isUUIDParam(b []byte) bool {
+	if len(b) != 36 {
+		return false
+	}
+	for i, ch := range b {
+		switch i {
+		case 8, 13, 18, 23:
+			if ch != '-' {
+				return false
+			}
+		default:
+			if !('0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isASCIIParam reports whether b is non-empty ASCII letters (and digits if allowed).
+func isASCIIParam(b []byte, digits bool) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, ch := range b {
+		if !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || digits && '0' <= ch && ch <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether b is non-empty and all ASCII digits.
+func isDigits(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, ch := range b {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeRank orders siblings by matching precedence (lower is tried first):
+// static (0) > constrained or prefixed parameter (1) > parameter (2) > wildcard (3).
+func nodeRank(n *node) int {
+	switch {
+	case n.isWild:
+		return 3
+	case n.isParam && n.constraint == nil && len(n.prefix) == 0:
+		return 2
+	case n.isParam:
+		return 1
+	}
+	return 0
+}
+
+// insertChild adds child to parent, keeping children ordered by nodeRank
+// (stable within a rank) and indices in sync.
+//
+// Static siblings are ordered longest first: in RouterLockFree they are path
+// prefixes, so "/users/me/" must be tried before "/users/" + parameter.
+// Parameters differing only by name ("/users/:id" and "/users/:userId") keep
+// registration order: the first registered matches first.
+func insertChild(parent, child *node) {
+	rank := nodeRank(child)
+	pos := len(parent.children)
+	for i, sibling := range parent.children {
+		siblingRank := nodeRank(sibling)
+		if siblingRank > rank || rank == 0 && siblingRank == 0 && len(sibling.path) < len(child.path) {
+			pos = i
+			break
+		}
+	}
+
+	parent.children = append(parent.children, nil)
+	copy(parent.children[pos+1:], parent.children[pos:])
+	parent.children[pos] = child
+	// One byte per child (string(byte) would UTF-8 encode labels >= 0x80)
+	parent.indices = parent.indices[:pos] + string([]byte{child.label}) + parent.indices[pos:]
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// newConstraintTestApp registers constrained routes on the selected router.
+func newConstraintTestApp(lockFree bool) *App {
+	config := DefaultConfig()
+	config.UseLockFreeRouter = lockFree
+	app := NewWithConfig(config)
+
+	route := func(name string, params ...string) Handler {
+		return func(c *Context) error {
+			out := name
+			for _, p := range params {
+				out += " " + p + "=" + c.Param(p)
+			}
+			return c.Blob(200, "text/plain", []byte(out))
+		}
+	}
+
+	app.Get("/users/:name", route("name", "name"))
+	app.Get("/users/:id<int>", route("id", "id"))
+	app.Get("/users/:id<int>/posts", route("posts", "id"))
+	app.Get("/files/:file<[a-z0-9-]+>", route("file", "file"))
+	app.Get("/api/v:version<uuid>/status", route("version", "version"))
+	app.Get("/codes/:code<alpha>", route("alpha", "code"))
+	app.Get("/codes/*rest", route("rest", "rest"))
+	return app
+}
+
+// TestRouterConstraints tests constrained parameters in both routers.
+func TestRouterConstraints(t *testing.T) {
+	tests := []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/users/42", 200, "id id=42"},
+		{"/users/-7", 200, "id id=-7"},
+		{"/users/abc", 200, "name name=abc"},                                   // Falls through to the plain parameter
+		{"/users/99999999999999999999", 200, "name name=99999999999999999999"}, // Out of int64 range
+		{"/users/42/posts", 200, "posts id=42"},
+		{"/users/abc/posts", 404, ""},
+		{"/files/report-2024", 200, "file file=report-2024"},
+		{"/files/Report", 404, ""},
+		{"/api/v123e4567-e89b-12d3-a456-426614174000/status", 200, "version version=123e4567-e89b-12d3-a456-426614174000"},
+		{"/api/v2/status", 404, ""},
+		{"/codes/abc", 200, "alpha code=abc"},
+		{"/codes/abc1", 200, "rest rest=abc1"},
+	}
+
+	for _, lockFree := range []bool{false, true} {
+		app := newConstraintTestApp(lockFree)
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != tt.code {
+				t.Errorf("lockFree=%v %s: expected %d, got %d", lockFree, tt.path, tt.code, w.Code)
+				continue
+			}
+			if tt.code == 200 && w.Body.String() != tt.want {
+				t.Errorf("lockFree=%v %s: expected %q, got %q", lockFree, tt.path, tt.want, w.Body.String())
+			}
+		}
+	}
+}
+
+// TestRouterConstraintsOrder tests that precedence doesn't depend on registration order.
+func TestRouterConstraintsOrder(t *testing.T) {
+	for _, router := range []IRouter{NewRouter(), NewRouterLockFree()} {
+		plain := func(c *Context) error { return errors.New("plain") }
+		constrained := func(c *Context) error { return errors.New("constrained") }
+
+		// Constrained route registered last still wins for matching values
+		router.Add(MethodGet, "/items/:slug", plain)
+		router.Add(MethodGet, "/items/:id<uint>", constrained)
+
+		handler, params := router.Lookup(MethodGet, "/items/12")
+		if handler == nil || handler(nil).Error() != "constrained" || params["id"] != "12" {
+			t.Errorf("%T: expected constrained route for /items/12, got params %v", router, params)
+		}
+		handler, params = router.Lookup(MethodGet, "/items/twelve")
+		if handler == nil || handler(nil).Error() != "plain" || params["slug"] != "twelve" {
+			t.Errorf("%T: expected plain route for /items/twelve, got params %v", router, params)
+		}
+	}
+}
+
+// TestRouterConstraintsZeroAlloc tests that unconstrained lookups stay allocation-free.
+func TestRouterConstraintsZeroAlloc(t *testing.T) {
+	r := NewRouter()
+	r.Add(MethodGet, "/users/:id<int>", testHandler)
+	r.Add(MethodGet, "/posts/:slug", testHandler)
+
+	for _, path := range []string{"/posts/hello", "/users/42"} {
+		pathBytes := []byte(path)
+		allocs := testing.AllocsPerRun(100, func() {
+			r.LookupBytes(MethodGet, pathBytes)
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected 0 allocs, got %v", path, allocs)
+		}
+	}
+}
+
+// TestInvalidConstraint tests that malformed constraints panic at registration.
+func TestInvalidConstraint(t *testing.T) {
+	for _, path := range []string{"/users/:id<int", "/users/:id<>", "/users/:id<[a-z>"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", path)
+				}
+			}()
+			NewRouter().Add(MethodGet, path, testHandler)
+		}()
+	}
+}
+
+// TestParamInt tests typed parameter accessors.
+func TestParamInt(t *testing.T) {
+	c := &Context{}
+	c.setParamBytes([]byte("id"), []byte("42"))
+	c.setParamBytes([]byte("name"), []byte("abc"))
+
+	if id, err := c.ParamInt("id"); err != nil || id != 42 {
+		t.Errorf("expected 42, got %d (%v)", id, err)
+	}
+	if _, err := c.ParamInt("name"); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for non-integer, got %v", err)
+	}
+	if _, err := c.ParamInt64("missing"); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for missing parameter, got %v", err)
+	}
+}
+
+// TestURLConstraints tests reverse URL building with constrained parameters.
+func TestURLConstraints(t *testing.T) {
+	app := New()
+	app.Get("/api/v:version<uuid>/users/:id<int>", testHandler).Name("user")
+
+	url, err := app.URL("user", "version", "123e4567-e89b-12d3-a456-426614174000", "id", "7")
+	if err != nil || url != "/api/v123e4567-e89b-12d3-a456-426614174000/users/7" {
+		t.Errorf("unexpected URL %q (%v)", url, err)
+	}
+	if _, err := app.URL("user", "version", "123e4567-e89b-12d3-a456-426614174000", "id", "x"); err == nil {
+		t.Error("expected error for value violating <int>")
+	}
+
+	doc := app.OpenAPI(OpenAPIInfo{Title: "Test", Version: "1"})
+	op := doc.Paths["/api/v{version}/users/{id}"]["get"]
+	if op == nil {
+		t.Fatalf("missing operation, paths: %v", doc.Paths)
+	}
+	if op.Parameters[0].Schema.Format != "uuid" || op.Parameters[1].Schema.Type != "integer" {
+		t.Errorf("unexpected parameter schemas: %+v %+v", op.Parameters[0].Schema, op.Parameters[1].Schema)
+	}
+}
@@ -12,8 +12,8 @@ type IRouter interface {
 	// Lookup finds a handler for the given method and path
 	Lookup(method HTTPMethod, path string) (Handler, map[string]string)
 
-	// LookupBytes finds a handler using byte slices (zero-allocation)
-	LookupBytes(method HTTPMethod, pathBytes []byte) (Handler, [8]ParamPair, int)
+	// LookupBytes finds a handler using byte slices (zero-allocation for ≤8 params)
+	LookupBytes(method HTTPMethod, pathBytes []byte) (Handler, Params)
 
 	// ServeHTTP handles an HTTP request using the router
 	ServeHTTP(c *Context) error
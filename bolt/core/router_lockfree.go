@@ -31,6 +31,7 @@ type RouterLockFree struct {
 	// Immutable route maps (loaded atomically, zero lock contention)
 	staticRoutes atomic.Value // map[string]Handler
 	dynamicTrees atomic.Value // map[HTTPMethod]*node
+	methods      atomic.Value // []HTTPMethod (registered methods, Allow header order)
 
 	// Write lock (only used during route registration, not lookup)
 	writeMu sync.Mutex
@@ -52,6 +53,7 @@ func NewRouterLockFree() *RouterLockFree {
 	// Initialize with empty maps
 	r.staticRoutes.Store(make(map[string]Handler))
 	r.dynamicTrees.Store(make(map[HTTPMethod]*node))
+	r.methods.Store([]HTTPMethod{})
 
 	return r
 }
@@ -71,6 +73,9 @@ func (r *RouterLockFree) Add(method HTTPMethod, path string, handler Handler) {
 	r.writeMu.Lock()
 	defer r.writeMu.Unlock()
 
+	// Track method for 405/OPTIONS handling (insertMethod never mutates the old slice)
+	r.methods.Store(insertMethod(r.methods.Load().([]HTTPMethod), method))
+
 	// Load current maps
 	oldStatic := r.staticRoutes.Load().(map[string]Handler)
 	oldTrees := r.dynamicTrees.Load().(map[HTTPMethod]*node)
@@ -160,46 +165,78 @@ func (r *RouterLockFree) Lookup(method HTTPMethod, path string) (Handler, map[st
 
 // LookupBytes is the zero-allocation version using byte slices.
 //
-// Performance: ~50-200ns, 0 allocs/op
-func (r *RouterLockFree) LookupBytes(method HTTPMethod, pathBytes []byte) (Handler, [8]ParamPair, int) {
+// Performance: ~50-200ns, 0 allocs/op for ≤8 params
+func (r *RouterLockFree) LookupBytes(method HTTPMethod, pathBytes []byte) (Handler, Params) {
+	var params Params
+	handler := r.lookupBytes(method, pathBytes, &params)
+	return handler, params
+}
+
+// lookupBytes is LookupBytes writing parameters into params (no copy of the result).
+func (r *RouterLockFree) lookupBytes(method HTTPMethod, pathBytes []byte, params *Params) Handler {
 	// Load static routes (atomic load, no lock!)
 	staticRoutes := r.staticRoutes.Load().(map[string]Handler)
 
 	// Fast path: static route lookup
 	// Use unsafe zero-copy conversion for map lookup (read-only)
-	key := string(method) + ":" + bytesToString(pathBytes)
-	if handler, ok := staticRoutes[key]; ok {
-		return handler, [8]ParamPair{}, 0
+	var keyBuf [128]byte
+	if handler, ok := staticRoutes[staticKey(&keyBuf, method, pathBytes)]; ok {
+		return handler
 	}
 
 	// Slow path: dynamic route lookup
 	dynamicTrees := r.dynamicTrees.Load().(map[HTTPMethod]*node)
 	root := dynamicTrees[method]
 	if root == nil {
-		return nil, [8]ParamPair{}, 0
+		return nil
 	}
 
 	// Search tree with byte slices
-	return r.searchTreeBytes(root, pathBytes)
+	handler := r.searchNodeBytes(root, pathBytes, 0, params)
+	if handler == nil {
+		params.truncate(0)
+	}
+	return handler
 }
 
 // ServeHTTP implements the routing logic for HTTP requests.
 func (r *RouterLockFree) ServeHTTP(c *Context) error {
 	// Use zero-allocation LookupBytes
-	handler, params, paramCount := r.LookupBytes(HTTPMethod(c.MethodBytes()), c.PathBytes())
+	method := HTTPMethod(c.MethodBytes())
+	var params Params
+	handler := r.lookupBytes(method, c.PathBytes(), &params)
+	if handler == nil && method == MethodHead {
+		// A resource that supports GET supports HEAD (RFC 9110 Section 9.3.2)
+		handler = r.lookupBytes(MethodGet, c.PathBytes(), &params)
+	}
 
 	if handler == nil {
+		// Distinguish 405 from 404 (miss path only)
+		if allow := r.allowed(method, c.PathBytes()); allow != "" {
+			return methodNotAllowed(c, method, allow)
+		}
 		return ErrNotFound
 	}
 
 	// Set parameters in context using zero-copy setParamBytes
-	for i := 0; i < paramCount; i++ {
-		c.setParamBytes(params[i].Key, params[i].Value)
-	}
+	c.setParams(&params)
 
 	return handler(c)
 }
 
+// allowed returns the Allow header value for a path that has no route under method.
+//
+// Returns "" if the path isn't registered under any other method (404).
+func (r *RouterLockFree) allowed(method HTTPMethod, pathBytes []byte) string {
+	return allowHeader(r.methods.Load().([]HTTPMethod), func(m HTTPMethod) bool {
+		if m == method {
+			return false
+		}
+		var params Params
+		return r.lookupBytes(m, pathBytes, &params) != nil
+	})
+}
+
 // cloneTree creates a deep copy of a radix tree node.
 //
 // This is used during copy-on-write to ensure immutability.
@@ -219,6 +256,7 @@ func (r *RouterLockFree) cloneTree(n *node) *node {
 		paramNameBytes: n.paramNameBytes,
 		path:           n.path,
 		paramName:      n.paramName,
+		constraint:     n.constraint, // Immutable, safe to share
 	}
 
 	// Clone children recursively
@@ -243,25 +281,39 @@ func (r *RouterLockFree) addToTree(root *node, path string, handler Handler) {
 	for i < len(path) {
 		// Check for parameter
 		if path[i] == ':' {
-			// Find end of parameter name
+			// Parameter (and constraint) runs to the end of the segment
 			end := i + 1
 			for end < len(path) && path[end] != '/' {
 				end++
 			}
 
+			// Reuse existing parameter node (route re-registration)
+			var paramNode *node
+			for _, child := range current.children {
+				if child.isParam && child.path == path[i:end] {
+					paramNode = child
+					break
+				}
+			}
+
 			// Create parameter node
-			paramName := path[i+1 : end]
-			paramNode := &node{
-				pathBytes:      pathBytes[i:end],
-				path:           path[i:end],
-				isParam:        true,
-				paramNameBytes: stringToBytes(paramName),
-				paramName:      paramName,
-				label:          ':',
+			if paramNode == nil {
+				seg := parseRouteSegment(path[i:end])
+				paramNode = &node{
+					pathBytes:      pathBytes[i:end],
+					path:           path[i:end],
+					isParam:        true,
+					paramNameBytes: stringToBytes(seg.name),
+					paramName:      seg.name,
+					label:          ':',
+				}
+				if seg.constraint != "" {
+					paramNode.constraint = newParamConstraint(seg.constraint)
+				}
+
+				insertChild(current, paramNode)
 			}
 
-			current.children = append(current.children, paramNode)
-			current.indices += ":"
 			current = paramNode
 			i = end
 			continue
@@ -269,6 +321,14 @@ func (r *RouterLockFree) addToTree(root *node, path string, handler Handler) {
 
 		// Check for wildcard
 		if path[i] == '*' {
+			// Reuse existing wildcard node (route re-registration)
+			for _, child := range current.children {
+				if child.isWild && child.path == path[i:] {
+					child.handler = handler
+					return
+				}
+			}
+
 			// Wildcard captures rest of path
 			paramName := path[i+1:]
 			wildcardNode := &node{
@@ -281,8 +341,7 @@ func (r *RouterLockFree) addToTree(root *node, path string, handler Handler) {
 				label:          '*',
 			}
 
-			current.children = append(current.children, wildcardNode)
-			current.indices += "*"
+			insertChild(current, wildcardNode)
 			return
 		}
 
@@ -315,8 +374,7 @@ func (r *RouterLockFree) addToTree(root *node, path string, handler Handler) {
 				label:     segment[0],
 			}
 
-			current.children = append(current.children, newNode)
-			current.indices += string(segment[0])
+			insertChild(current, newNode)
 			current = newNode
 		}
 
@@ -329,98 +387,62 @@ func (r *RouterLockFree) addToTree(root *node, path string, handler Handler) {
 
 // searchTree searches for a handler in the tree (map-based params).
 func (r *RouterLockFree) searchTree(root *node, path string) (Handler, map[string]string) {
-	handler, params, paramCount := r.searchTreeBytes(root, stringToBytes(path))
+	var params Params
+	handler := r.searchNodeBytes(root, stringToBytes(path), 0, &params)
 
 	if handler == nil {
 		return nil, nil
 	}
 
-	if paramCount == 0 {
-		return handler, nil
-	}
-
 	// Convert params to map
-	paramMap := make(map[string]string, paramCount)
-	for i := 0; i < paramCount; i++ {
-		paramMap[bytesToString(params[i].Key)] = bytesToString(params[i].Value)
-	}
-
-	return handler, paramMap
+	return handler, params.toMap()
 }
 
-// searchTreeBytes searches for a handler using byte slices (zero-copy).
-func (r *RouterLockFree) searchTreeBytes(root *node, pathBytes []byte) (Handler, [8]ParamPair, int) {
-	var params [8]ParamPair // Inline storage for up to 8 params
-	paramCount := 0
-
-	current := root
-	i := 0
+// searchNodeBytes matches pathBytes[i:] below n, backtracking when a branch fails
+// (e.g. a constrained parameter rejects the value and a sibling route matches).
+//
+// Children are ordered by precedence: static > constrained param > param > wildcard.
+func (r *RouterLockFree) searchNodeBytes(n *node, pathBytes []byte, i int, params *Params) Handler {
+	// Consumed the entire path
+	if i >= len(pathBytes) {
+		return n.handler
+	}
 
-	for i < len(pathBytes) {
-		// Check children
-		var matched *node
-		for _, child := range current.children {
-			if child.isWild {
-				// Wildcard matches rest of path
-				if paramCount < len(params) {
-					params[paramCount] = ParamPair{
-						Key:   child.paramNameBytes,
-						Value: pathBytes[i:],
-					}
-					paramCount++
-				}
-				return child.handler, params, paramCount
+	for _, child := range n.children {
+		switch {
+		case child.isWild:
+			// Wildcard matches rest of path
+			params.push(child.paramNameBytes, pathBytes[i:])
+			return child.handler
+
+		case child.isParam:
+			// Find end of this path segment
+			end := i
+			for end < len(pathBytes) && pathBytes[end] != '/' {
+				end++
 			}
 
-			if child.isParam {
-				// Find end of this path segment
-				end := i
-				for end < len(pathBytes) && pathBytes[end] != '/' {
-					end++
-				}
-
-				if paramCount < len(params) {
-					params[paramCount] = ParamPair{
-						Key:   child.paramNameBytes,
-						Value: pathBytes[i:end],
-					}
-					paramCount++
-				}
-
-				matched = child
-				i = end
-				break
+			value := pathBytes[i:end]
+			if child.constraint != nil && !child.constraint.match(value) {
+				continue
 			}
 
-			// Static match
-			if len(child.pathBytes) <= len(pathBytes)-i {
-				if bytesEqual(child.pathBytes, pathBytes[i:i+len(child.pathBytes)]) {
-					matched = child
-					i += len(child.pathBytes)
-					break
-				}
+			saved := params.n
+			params.push(child.paramNameBytes, value)
+			if handler := r.searchNodeBytes(child, pathBytes, end, params); handler != nil {
+				return handler
 			}
-		}
+			params.truncate(saved) // Backtrack
 
-		if matched == nil {
-			return nil, [8]ParamPair{}, 0
-		}
-
-		current = matched
-
-		// If we've consumed the entire path and this node has a handler
-		if i >= len(pathBytes) {
-			if current.handler != nil {
-				return current.handler, params, paramCount
+		default:
+			// Static prefix match
+			if len(child.pathBytes) <= len(pathBytes)-i && bytesEqual(child.pathBytes, pathBytes[i:i+len(child.pathBytes)]) {
+				if handler := r.searchNodeBytes(child, pathBytes, i+len(child.pathBytes), params); handler != nil {
+					return handler
+				}
 			}
-			return nil, [8]ParamPair{}, 0
 		}
 	}
 
-	if current.handler != nil {
-		return current.handler, params, paramCount
-	}
-
-	return nil, [8]ParamPair{}, 0
+	return nil
 }
-
@@ -0,0 +1,9 @@
+package core
+
+import "testing"
+
+// TestRouterLockFreeHeadFallback tests that GET routes serve HEAD requests
+// and that HEAD is listed in Allow.
+func TestRouterLockFreeHeadFallback(t *testing.T) {
+	testRouterHeadFallback(t, NewRouterLockFree())
+}
@@ -0,0 +1,108 @@
+package core
+
+// Method tracking shared by Router and RouterLockFree.
+//
+// Both routers keep the set of methods that have at least one registered route.
+// When a lookup misses, the path is retried under every other registered method
+// to tell "404 Not Found" apart from "405 Method Not Allowed", and to answer
+// OPTIONS requests automatically.
+//
+// This only runs on the miss path, so matched requests pay nothing for it.
+//
+// HEAD requests without a HEAD route are served by the GET route of the path
+// (RFC 9110 Section 9.3.2); the response writers drop the body.
+
+// headerAllow is the Allow response header name (RFC 9110 Section 10.2.1).
+var headerAllow = []byte("Allow")
+
+// methodOrder defines the order in which methods are listed in the Allow header.
+// Methods not listed here (extension methods) sort after these in registration order.
+var methodOrder = [...]HTTPMethod{
+	MethodGet,
+	MethodHead,
+	MethodPost,
+	MethodPut,
+	MethodPatch,
+	MethodDelete,
+	MethodConnect,
+	MethodOptions,
+	MethodTrace,
+}
+
+// methodRank returns the sort position of a method for the Allow header.
+func methodRank(method HTTPMethod) int {
+	for i, m := range methodOrder {
+		if m == method {
+			return i
+		}
+	}
+	return len(methodOrder)
+}
+
+// insertMethod returns methods with method added (if missing), keeping Allow header order.
+//
+// The input slice is never modified, so the result can be published with copy-on-write.
+func insertMethod(methods []HTTPMethod, method HTTPMethod) []HTTPMethod {
+	for _, m := range methods {
+		if m == method {
+			return methods
+		}
+	}
+
+	rank := methodRank(method)
+	result := make([]HTTPMethod, 0, len(methods)+1)
+	inserted := false
+	for _, m := range methods {
+		if !inserted && rank < methodRank(m) {
+			result = append(result, method)
+			inserted = true
+		}
+		result = append(result, m)
+	}
+	if !inserted {
+		result = append(result, method)
+	}
+	return result
+}
+
+// allowHeader builds the Allow header value for a path.
+//
+// matches reports whether the path is routable under the given method.
+// OPTIONS is always listed once any method matches, because the router
+// answers OPTIONS automatically, and HEAD whenever GET matches (GET
+// routes serve HEAD requests). Returns "" if no method matches.
+func allowHeader(methods []HTTPMethod, matches func(HTTPMethod) bool) string {
+	var allowed []HTTPMethod
+	for _, m := range methods {
+		if matches(m) {
+			allowed = append(allowed, m)
+		}
+	}
+	if len(allowed) == 0 {
+		return ""
+	}
+
+	if allowed[0] == MethodGet { // Allow order: GET sorts first
+		allowed = insertMethod(allowed, MethodHead)
+	}
+	allowed = insertMethod(allowed, MethodOptions)
+
+	allow := string(allowed[0])
+	for _, m := range allowed[1:] {
+		allow += ", " + string(m)
+	}
+	return allow
+}
+
+// methodNotAllowed handles a lookup miss for a path that exists under other methods.
+//
+// OPTIONS requests are answered with 204 No Content and the Allow header;
+// all other methods get the Allow header and ErrMethodNotAllowed (405).
+func methodNotAllowed(c *Context, method HTTPMethod, allow string) error {
+	c.SetHeaderBytes(headerAllow, stringToBytes(allow))
+
+	if method == MethodOptions {
+		return c.NoContent()
+	}
+	return ErrMethodNotAllowed
+}
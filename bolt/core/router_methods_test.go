@@ -0,0 +1,190 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestRouters returns both router implementations for table tests.
+func newTestRouters() map[string]IRouter {
+	return map[string]IRouter{
+		"Router":         NewRouter(),
+		"RouterLockFree": NewRouterLockFree(),
+	}
+}
+
+// TestRouterMethodNotAllowed tests 405 detection and the Allow header.
+func TestRouterMethodNotAllowed(t *testing.T) {
+	for name, router := range newTestRouters() {
+		t.Run(name, func(t *testing.T) {
+			noop := func(c *Context) error { return nil }
+			router.Add(MethodPost, "/users", noop)
+			router.Add(MethodGet, "/users", noop)
+			router.Add(MethodDelete, "/users/:id", noop)
+			router.Add(MethodPut, "/users/:id", noop)
+
+			tests := []struct {
+				method string
+				path   string
+				err    error
+				allow  string
+			}{
+				{"PUT", "/users", ErrMethodNotAllowed, "GET, HEAD, POST, OPTIONS"},
+				{"GET", "/users/42", ErrMethodNotAllowed, "PUT, DELETE, OPTIONS"},
+				{"GET", "/missing", ErrNotFound, ""},
+				{"PATCH", "/users/42/posts", ErrNotFound, ""},
+			}
+
+			for _, tt := range tests {
+				c := &Context{}
+				c.SetMethod(tt.method)
+				c.SetPath(tt.path)
+
+				err := router.ServeHTTP(c)
+				if !errors.Is(err, tt.err) {
+					t.Errorf("%s %s: expected %v, got %v", tt.method, tt.path, tt.err, err)
+				}
+				if got := c.GetResponseHeader("Allow"); got != tt.allow {
+					t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.allow, got)
+				}
+			}
+		})
+	}
+}
+
+// TestRouterAutomaticOptions tests OPTIONS answered from registered methods.
+func TestRouterAutomaticOptions(t *testing.T) {
+	for name, router := range newTestRouters() {
+		t.Run(name, func(t *testing.T) {
+			noop := func(c *Context) error { return nil }
+			explicitCalled := false
+			router.Add(MethodGet, "/items", noop)
+			router.Add(MethodPatch, "/items", noop)
+			router.Add(MethodGet, "/custom", noop)
+			router.Add(MethodOptions, "/custom", func(c *Context) error {
+				explicitCalled = true
+				return nil
+			})
+
+			c := &Context{}
+			c.SetMethod("OPTIONS")
+			c.SetPath("/items")
+			if err := router.ServeHTTP(c); err != nil {
+				t.Fatalf("expected automatic OPTIONS response, got %v", err)
+			}
+			if c.StatusCode() != 204 {
+				t.Errorf("expected status 204, got %d", c.StatusCode())
+			}
+			if got := c.GetResponseHeader("Allow"); got != "GET, HEAD, PATCH, OPTIONS" {
+				t.Errorf("expected Allow %q, got %q", "GET, HEAD, PATCH, OPTIONS", got)
+			}
+
+			// Explicit OPTIONS route wins
+			c = &Context{}
+			c.SetMethod("OPTIONS")
+			c.SetPath("/custom")
+			if err := router.ServeHTTP(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !explicitCalled {
+				t.Error("expected explicit OPTIONS handler to be called")
+			}
+
+			// Unknown path stays 404
+			c = &Context{}
+			c.SetMethod("OPTIONS")
+			c.SetPath("/nowhere")
+			if err := router.ServeHTTP(c); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+// TestAppMethodNotAllowedHTTP tests 405 through App.ServeHTTP.
+func TestAppMethodNotAllowedHTTP(t *testing.T) {
+	app := New()
+	app.Get("/ping", func(c *Context) error {
+		return c.JSON(200, map[string]string{"pong": "ok"})
+	})
+
+	req := httptest.NewRequest("POST", "/ping", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 405 {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, HEAD, OPTIONS" {
+		t.Errorf("expected Allow %q, got %q", "GET, HEAD, OPTIONS", got)
+	}
+
+	req = httptest.NewRequest("OPTIONS", "/ping", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, HEAD, OPTIONS" {
+		t.Errorf("expected Allow %q, got %q", "GET, HEAD, OPTIONS", got)
+	}
+}
+
+// TestAppHeadFallback tests HEAD requests to GET-only routes: the GET
+// handler runs and the body is dropped, over net/http and Shockwave.
+func TestAppHeadFallback(t *testing.T) {
+	handler := func(c *Context) error {
+		c.SetHeader("X-Handler", "get")
+		return c.Text(200, "hello")
+	}
+
+	app := New()
+	app.Get("/ping", handler)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("HEAD", "/ping", nil))
+	if w.Code != 200 || w.Header().Get("X-Handler") != "get" {
+		t.Errorf("net/http: expected the GET handler, got %d %v", w.Code, w.Header())
+	}
+
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+	ts.app.Get("/ping", handler)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+	for i := 0; i < 2; i++ { // Keep-alive: no body may follow the HEAD response
+		resp, err := client.Head(ts.url + "/ping")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 200 || resp.Header.Get("X-Handler") != "get" || resp.ContentLength != 5 {
+			t.Errorf("shockwave: expected the GET handler's headers, got %d %v", resp.StatusCode, resp.Header)
+		}
+	}
+	if body := ts.Get("/ping").AssertStatus(t, 200).body; string(body) != "hello" {
+		t.Errorf("expected GET body after HEAD, got %q", body)
+	}
+}
+
+// TestInsertMethod tests Allow header ordering.
+func TestInsertMethod(t *testing.T) {
+	var methods []HTTPMethod
+	for _, m := range []HTTPMethod{MethodDelete, "PURGE", MethodGet, MethodOptions, MethodPost, MethodGet} {
+		methods = insertMethod(methods, m)
+	}
+
+	expected := []HTTPMethod{MethodGet, MethodPost, MethodDelete, MethodOptions, "PURGE"}
+	if len(methods) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, methods)
+	}
+	for i := range expected {
+		if methods[i] != expected[i] {
+			t.Errorf("position %d: expected %s, got %s", i, expected[i], methods[i])
+		}
+	}
+}
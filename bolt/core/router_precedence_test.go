@@ -0,0 +1,190 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// routerImplementations returns a fresh instance of each router.
+func routerImplementations() []IRouter {
+	return []IRouter{NewRouter(), NewRouterLockFree()}
+}
+
+// namedHandler returns a handler identifying itself through its error.
+func namedHandler(name string) Handler {
+	return func(c *Context) error { return errors.New(name) }
+}
+
+// lookupName returns the name of the handler matching path ("" if none).
+func lookupName(r IRouter, path string) (string, map[string]string) {
+	handler, params := r.Lookup(MethodGet, path)
+	if handler == nil {
+		return "", nil
+	}
+	return handler(nil).Error(), params
+}
+
+// TestRouterManyParams tests routes with more parameters than the inline storage.
+func TestRouterManyParams(t *testing.T) {
+	const count = 12
+
+	var route, path strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&route, "/:p%d", i)
+		fmt.Fprintf(&path, "/v%d", i)
+	}
+
+	for _, r := range routerImplementations() {
+		r.Add(MethodGet, route.String(), namedHandler("many"))
+
+		handler, params := r.LookupBytes(MethodGet, []byte(path.String()))
+		if handler == nil {
+			t.Fatalf("%T: expected match for %d params", r, count)
+		}
+		if params.Len() != count {
+			t.Fatalf("%T: expected %d params, got %d", r, count, params.Len())
+		}
+		for i := 0; i < count; i++ {
+			pair := params.At(i)
+			if string(pair.Key) != fmt.Sprintf("p%d", i) || string(pair.Value) != fmt.Sprintf("v%d", i) {
+				t.Errorf("%T: param %d = %s=%s", r, i, pair.Key, pair.Value)
+			}
+		}
+		if v, ok := params.Get("p11"); !ok || string(v) != "v11" {
+			t.Errorf("%T: Get(p11) = %q, %v", r, v, ok)
+		}
+
+		// Context sees every parameter
+		c := &Context{}
+		c.setParams(&params)
+		if c.Param("p0") != "v0" || c.Param("p11") != "v11" {
+			t.Errorf("%T: context params p0=%q p11=%q", r, c.Param("p0"), c.Param("p11"))
+		}
+	}
+}
+
+// TestRouterManyParamsBacktracking tests overflow storage when a long branch fails.
+func TestRouterManyParamsBacktracking(t *testing.T) {
+	for _, r := range routerImplementations() {
+		r.Add(MethodGet, "/:a/:b/:c/:d/:e/:f/:g/:h/:i/:j/x", namedHandler("x"))
+		r.Add(MethodGet, "/:a/:b/:c/:d/:e/:f/:g/:h/:i/:j<int>/y", namedHandler("y"))
+
+		// The constrained branch is tried first and must not leak its parameters
+		name, params := lookupName(r, "/1/2/3/4/5/6/7/8/9/10/x")
+		if name != "x" || len(params) != 10 || params["j"] != "10" {
+			t.Errorf("%T: got %q %v", r, name, params)
+		}
+		name, params = lookupName(r, "/1/2/3/4/5/6/7/8/9/10/y")
+		if name != "y" || len(params) != 10 || params["j"] != "10" {
+			t.Errorf("%T: got %q %v", r, name, params)
+		}
+	}
+}
+
+// TestRouterParamsZeroAlloc tests that up to 8 parameters don't allocate.
+func TestRouterParamsZeroAlloc(t *testing.T) {
+	for _, r := range routerImplementations() {
+		r.Add(MethodGet, "/:a/:b/:c/:d/:e/:f/:g/*h", testHandler)
+		pathBytes := []byte("/1/2/3/4/5/6/7/8/9")
+
+		allocs := testing.AllocsPerRun(100, func() {
+			r.LookupBytes(MethodGet, pathBytes)
+		})
+		if allocs != 0 {
+			t.Errorf("%T: expected 0 allocs for 8 params, got %v", r, allocs)
+		}
+	}
+}
+
+// TestRouterPrecedence tests static > constrained param > param > wildcard,
+// independent of registration order.
+func TestRouterPrecedence(t *testing.T) {
+	routes := [][2]string{
+		{"/users/*rest", "wildcard"},
+		{"/users/:name", "param"},
+		{"/users/:id<int>", "constrained"},
+		{"/users/me", "static"},
+		{"/users/:id/:tab", "param-tab"},
+		{"/users/me/:tab", "static-tab"},
+		{"/users/:id<int>/:tab", "constrained-tab"},
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/me", "static"},
+		{"/users/42", "constrained"},
+		{"/users/ada", "param"},
+		{"/users/ada/x/y", "wildcard"},
+		{"/users/me/posts", "static-tab"},
+		{"/users/42/posts", "constrained-tab"},
+		{"/users/ada/posts", "param-tab"},
+	}
+
+	for _, reverse := range []bool{false, true} {
+		for _, r := range routerImplementations() {
+			for i := range routes {
+				route := routes[i]
+				if reverse {
+					route = routes[len(routes)-1-i]
+				}
+				r.Add(MethodGet, route[0], namedHandler(route[1]))
+			}
+
+			for _, tt := range tests {
+				if got, params := lookupName(r, tt.path); got != tt.want {
+					t.Errorf("%T reverse=%v %s: expected %s, got %s (%v)", r, reverse, tt.path, tt.want, got, params)
+				}
+			}
+		}
+	}
+}
+
+// TestRouterConflicts tests deterministic outcomes for overlapping routes.
+func TestRouterConflicts(t *testing.T) {
+	for _, r := range routerImplementations() {
+		// Same shape, different names: first registered wins
+		r.Add(MethodGet, "/items/:id", namedHandler("first"))
+		r.Add(MethodGet, "/items/:itemId", namedHandler("second"))
+		if got, params := lookupName(r, "/items/1"); got != "first" || params["id"] != "1" {
+			t.Errorf("%T: expected first route, got %s %v", r, got, params)
+		}
+
+		// ... but the other remains reachable where the first has no route
+		r.Add(MethodGet, "/items/:itemId/reviews", namedHandler("reviews"))
+		if got, params := lookupName(r, "/items/1/reviews"); got != "reviews" || params["itemId"] != "1" || len(params) != 1 {
+			t.Errorf("%T: expected reviews route, got %s %v", r, got, params)
+		}
+
+		// Re-registering the same pattern replaces the handler
+		r.Add(MethodGet, "/items/:id", namedHandler("replaced"))
+		if got, _ := lookupName(r, "/items/1"); got != "replaced" {
+			t.Errorf("%T: expected replaced handler, got %s", r, got)
+		}
+
+		// Identical constraints with different names behave like plain parameters
+		r.Add(MethodGet, "/orders/:id<int>", namedHandler("order"))
+		r.Add(MethodGet, "/orders/:orderId<int>", namedHandler("order2"))
+		if got, _ := lookupName(r, "/orders/5"); got != "order" {
+			t.Errorf("%T: expected first constrained route, got %s", r, got)
+		}
+	}
+}
+
+// TestRouterLongStaticPath tests that long paths can't collide through key truncation.
+func TestRouterLongStaticPath(t *testing.T) {
+	long := "/" + strings.Repeat("a", 130)
+	for _, r := range routerImplementations() {
+		r.Add(MethodGet, long[:120], namedHandler("short"))
+		r.Add(MethodGet, long, namedHandler("long"))
+
+		if got, _ := lookupName(r, long); got != "long" {
+			t.Errorf("%T: expected long route, got %q", r, got)
+		}
+		if got, _ := lookupName(r, long+"b"); got != "" {
+			t.Errorf("%T: expected no match, got %q", r, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+	"sync"
 	"testing"
 )
 
@@ -208,6 +210,48 @@ func TestSplitPath(t *testing.T) {
 	}
 }
 
+// TestRouterHeadFallback tests that GET routes serve HEAD requests and that
+// HEAD is listed in Allow.
+func TestRouterHeadFallback(t *testing.T) {
+	testRouterHeadFallback(t, NewRouter())
+}
+
+// testRouterHeadFallback checks HEAD on GET-only static and dynamic routes.
+func testRouterHeadFallback(t *testing.T, r IRouter) {
+	t.Helper()
+	var called string
+	r.Add(MethodGet, "/status", func(c *Context) error { called = "get"; return nil })
+	r.Add(MethodGet, "/users/:id", func(c *Context) error { called = "get " + c.Param("id"); return nil })
+	r.Add(MethodGet, "/files", testHandler)
+	r.Add(MethodHead, "/files", func(c *Context) error { called = "head"; return nil })
+	r.Add(MethodPost, "/forms", testHandler)
+
+	for path, want := range map[string]string{"/status": "get", "/users/7": "get 7", "/files": "head"} {
+		called = ""
+		c := &Context{}
+		c.SetMethod("HEAD")
+		c.SetPath(path)
+		if err := r.ServeHTTP(c); err != nil || called != want {
+			t.Errorf("HEAD %s: expected %q, got %q (%v)", path, want, called, err)
+		}
+	}
+
+	// POST-only paths stay 405 for HEAD
+	c := &Context{}
+	c.SetMethod("HEAD")
+	c.SetPath("/forms")
+	if err := r.ServeHTTP(c); !errors.Is(err, ErrMethodNotAllowed) || c.GetResponseHeader("Allow") != "POST, OPTIONS" {
+		t.Errorf("HEAD /forms: expected 405 with Allow POST, got %v %q", err, c.GetResponseHeader("Allow"))
+	}
+
+	c = &Context{}
+	c.SetMethod("DELETE")
+	c.SetPath("/users/7")
+	if err := r.ServeHTTP(c); !errors.Is(err, ErrMethodNotAllowed) || c.GetResponseHeader("Allow") != "GET, HEAD, OPTIONS" {
+		t.Errorf("DELETE /users/7: expected Allow \"GET, HEAD, OPTIONS\", got %v %q", err, c.GetResponseHeader("Allow"))
+	}
+}
+
 // TestRouterConcurrency tests concurrent access to router.
 func TestRouterConcurrency(t *testing.T) {
 	r := NewRouter()
@@ -241,6 +285,34 @@ func TestRouterConcurrency(t *testing.T) {
 	}
 }
 
+// TestRouterConcurrentSiblingLookups tests concurrent lookups across static
+// siblings of the radix tree, which must not modify it (run with -race).
+func TestRouterConcurrentSiblingLookups(t *testing.T) {
+	r := NewRouter()
+	sections := []string{"users", "posts", "items", "orders", "teams"}
+	for _, section := range sections {
+		r.Add(MethodGet, "/api/"+section+"/:id", testHandler)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				// Later siblings are looked up most: the old lookup moved them first
+				section := sections[len(sections)-1-(offset+j)%3]
+				handler, params := r.Lookup(MethodGet, "/api/"+section+"/7")
+				if handler == nil || params["id"] != "7" {
+					t.Errorf("lookup failed for /api/%s/7", section)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
 // TestHybridRoutingPerformance tests that static routes are faster.
 func TestHybridRoutingPerformance(t *testing.T) {
 	r := NewRouter()
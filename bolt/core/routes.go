@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Route introspection and reverse URL building.
+
+// Routes returns every registered route in registration order.
+//
+// The returned values are copies: modifying them does not affect routing.
+// Registering a method and path twice panics.
+//
+// Example:
+//
+//	for _, r := range app.Routes() {
+//	    fmt.Printf("%-7s %-30s %-15s %d middleware\n", r.Method, r.Path, r.Name, r.Middleware)
+//	}
+func (app *App) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(app.routes))
+	for i, route := range app.routes {
+		routes[i] = *route
+	}
+	return routes
+}
+
+// URL builds the path of the named route, substituting parameters.
+//
+// params are key/value pairs. Values of ":name" parameters are escaped as a
+// single path segment; "*name" wildcard values may contain slashes, and each
+// of their segments is escaped separately.
+//
+// Returns an error if no route has the name, a parameter is missing or
+// violates its constraint (":id<int>"), or a key does not match any
+// parameter of the route.
+//
+// Example:
+//
+//	app.Get("/users/:id/files/*path", getFile).Name("user.file")
+//
+//	app.URL("user.file", "id", "42", "path", "docs/a b.pdf")
+//	// "/users/42/files/docs/a%20b.pdf"
+func (app *App) URL(name string, params ...string) (string, error) {
+	route, ok := app.namedRoutes[name]
+	if !ok {
+		return "", fmt.Errorf("bolt: no route named %q", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("bolt: route %q: odd number of parameters (want key/value pairs)", name)
+	}
+
+	used := 0
+	segments := strings.Split(route.Path, "/")
+	for i, s := range segments {
+		seg := parseRouteSegment(s)
+		if seg.kind == 0 {
+			continue
+		}
+
+		value, found := lookupPair(params, seg.name)
+		if !found {
+			return "", fmt.Errorf("bolt: route %q: missing parameter %q", name, seg.name)
+		}
+		used++
+
+		if seg.kind == '*' {
+			parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+			continue
+		}
+
+		if seg.constraint != "" && !newParamConstraint(seg.constraint).match([]byte(value)) {
+			return "", fmt.Errorf("bolt: route %q: parameter %q value %q does not match <%s>", name, seg.name, value, seg.constraint)
+		}
+		segments[i] = seg.prefix + url.PathEscape(value)
+	}
+
+	if used != len(params)/2 {
+		for i := 0; i < len(params); i += 2 {
+			if !routeHasParam(route.Path, params[i]) {
+				return "", fmt.Errorf("bolt: route %q has no parameter %q", name, params[i])
+			}
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// addRouteInfo records a route.
+//
+// Registering the same method and path twice panics: the router would keep
+// only the last handler, silently dropping the first (and its name). Paths
+// differing only in parameter or wildcard names ("/u/:id", "/u/:name") are
+// the same route.
+func (app *App) addRouteInfo(route *RouteInfo) {
+	shape := routeShape(route.Path)
+	for _, existing := range app.routes {
+		if existing.Method != route.Method || routeShape(existing.Path) != shape {
+			continue
+		}
+		if existing.Path == route.Path {
+			panic(fmt.Sprintf("bolt: route %s %s already registered", route.Method, route.Path))
+		}
+		panic(fmt.Sprintf("bolt: route %s %s conflicts with %s %s", route.Method, route.Path, existing.Method, existing.Path))
+	}
+	app.routes = append(app.routes, route)
+}
+
+// routeShape returns path with parameter and wildcard names removed
+// ("/u/:id<int>/*rest" → "/u/:<int>/*"), so equivalent patterns compare equal.
+func routeShape(path string) string {
+	segs := strings.Split(path, "/")
+	for i, s := range segs {
+		switch seg := parseRouteSegment(s); seg.kind {
+		case ':':
+			segs[i] = seg.prefix + ":"
+			if seg.constraint != "" {
+				segs[i] += "<" + seg.constraint + ">"
+			}
+		case '*':
+			segs[i] = "*"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// nameRoute assigns a unique name to route.
+func (app *App) nameRoute(route *RouteInfo, name string) {
+	if existing, ok := app.namedRoutes[name]; ok && existing != route {
+		panic(fmt.Sprintf("bolt: route name %q already used by %s %s", name, existing.Method, existing.Path))
+	}
+	if app.namedRoutes == nil {
+		app.namedRoutes = make(map[string]*RouteInfo)
+	}
+	if route.Name != "" {
+		delete(app.namedRoutes, route.Name)
+	}
+	route.Name = name
+	app.namedRoutes[name] = route
+}
+
+// routeHasParam reports whether path declares a parameter named key.
+func routeHasParam(path, key string) bool {
+	for _, s := range strings.Split(path, "/") {
+		if seg := parseRouteSegment(s); seg.kind != 0 && seg.name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupPair returns the value following key in a key/value list.
+func lookupPair(pairs []string, key string) (string, bool) {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i] == key {
+			return pairs[i+1], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRoutes tests route introspection and middleware counts.
+func TestRoutes(t *testing.T) {
+	app := New()
+	noop := func(next Handler) Handler { return next }
+	handler := func(c *Context) error { return nil }
+
+	app.Use(noop)
+	app.Get("/users", handler).Name("user.list")
+	api := app.Group("/api", noop)
+	api.Post("/items", handler).Use(noop, noop)
+
+	routes := app.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Method != MethodGet || routes[0].Path != "/users" || routes[0].Name != "user.list" {
+		t.Errorf("unexpected route: %+v", routes[0])
+	}
+	if routes[0].Middleware != 1 {
+		t.Errorf("expected 1 middleware, got %d", routes[0].Middleware)
+	}
+	if routes[1].Path != "/api/items" || routes[1].Middleware != 4 {
+		t.Errorf("expected /api/items with 4 middleware, got %s with %d", routes[1].Path, routes[1].Middleware)
+	}
+
+	// Copies don't affect the app
+	routes[1].Path = "/changed"
+	if app.Routes()[1].Path != "/api/items" {
+		t.Error("Routes should return copies")
+	}
+}
+
+// TestRoutesDuplicate tests that registering a method and path twice panics.
+func TestRoutesDuplicate(t *testing.T) {
+	app := New()
+	handler := func(c *Context) error { return nil }
+	app.Get("/users", handler).Name("user.list")
+	app.Post("/users", handler)
+	app.Group("/api").Get("/users", handler)
+
+	for _, register := range []func(){
+		func() { app.Get("/users", handler) },
+		func() { app.Group("/api").Get("/users", handler) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "route GET /") {
+					t.Errorf("expected duplicate route panic, got %v", r)
+				}
+			}()
+			register()
+		}()
+	}
+
+	// The first registration is kept, with its name
+	if url, err := app.URL("user.list"); err != nil || url != "/users" {
+		t.Errorf("expected /users, got %q (%v)", url, err)
+	}
+	if len(app.Routes()) != 3 {
+		t.Errorf("expected 3 routes, got %d", len(app.Routes()))
+	}
+}
+
+// TestURL tests reverse URL building and escaping.
+func TestURL(t *testing.T) {
+	app := New()
+	handler := func(c *Context) error { return nil }
+	app.Get("/users/:id", handler).Name("user.show")
+	app.Get("/users/:id/files/*path", handler).Name("user.file")
+	app.Group("/admin").Get("/stats", handler).Name("admin.stats")
+
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{"user.show", []string{"id", "42"}, "/users/42"},
+		{"user.show", []string{"id", "a b/c?d"}, "/users/a%20b%2Fc%3Fd"},
+		{"user.file", []string{"id", "7", "path", "docs/a b.pdf"}, "/users/7/files/docs/a%20b.pdf"},
+		{"admin.stats", nil, "/admin/stats"},
+	}
+	for _, tt := range tests {
+		got, err := app.URL(tt.name, tt.params...)
+		if err != nil {
+			t.Errorf("URL(%q, %v): %v", tt.name, tt.params, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("URL(%q, %v) = %q, want %q", tt.name, tt.params, got, tt.want)
+		}
+	}
+
+	errorCases := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{"missing", nil, "no route named"},
+		{"user.show", nil, `missing parameter "id"`},
+		{"user.show", []string{"id"}, "odd number"},
+		{"user.show", []string{"id", "1", "extra", "2"}, `no parameter "extra"`},
+	}
+	for _, tt := range errorCases {
+		if _, err := app.URL(tt.name, tt.params...); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("URL(%q, %v): expected error containing %q, got %v", tt.name, tt.params, tt.want, err)
+		}
+	}
+}
+
+// TestRoutesDuplicatePattern tests that paths differing only in parameter
+// or wildcard names conflict.
+func TestRoutesDuplicatePattern(t *testing.T) {
+	app := New()
+	handler := func(c *Context) error { return nil }
+	app.Get("/u/:id", handler)
+	app.Get("/v:version<int>/items", handler)
+	app.Get("/files/*path", handler)
+
+	// Different methods, constraints or static prefixes are distinct routes
+	app.Post("/u/:name", handler)
+	app.Get("/v:version<uuid>/items", handler)
+	app.Get("/u/:id/posts", handler)
+
+	for _, path := range []string{"/u/:name", "/v:n<int>/items", "/files/*rest"} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "conflicts with GET /") {
+					t.Errorf("GET %s: expected conflicting route panic, got %v", path, r)
+				}
+			}()
+			app.Get(path, handler)
+		}()
+	}
+	if len(app.Routes()) != 6 {
+		t.Errorf("expected 6 routes, got %d", len(app.Routes()))
+	}
+}
+
+// TestNameDuplicate tests that reusing a route name panics.
+func TestNameDuplicate(t *testing.T) {
+	app := New()
+	handler := func(c *Context) error { return nil }
+	app.Get("/a", handler).Name("dup")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate route name")
+		}
+	}()
+	app.Get("/b", handler).Name("dup")
+}
+
+// TestContextRoute tests that middleware sees the matched route pattern.
+func TestContextRoute(t *testing.T) {
+	app := New()
+	var route string
+	app.Use(func(next Handler) Handler {
+		return func(c *Context) error {
+			route = c.Route()
+			return next(c)
+		}
+	})
+	handler := func(c *Context) error { return c.NoContent() }
+
+	app.Get("/users", handler)
+	app.Get("/users/:id", handler)
+	app.Group("/api").Get("/files/*path", handler)
+
+	for path, want := range map[string]string{
+		"/users":             "/users",
+		"/users/42":          "/users/:id",
+		"/api/files/a/b.txt": "/api/files/*path",
+	} {
+		route = ""
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		if route != want {
+			t.Errorf("%s: expected route %q, got %q", path, want, route)
+		}
+	}
+
+	// Reset with the context
+	c := app.contextPool.Acquire()
+	c.route = "/users"
+	app.contextPool.Release(c)
+	if c.Route() != "" {
+		t.Errorf("expected route to be reset, got %q", c.Route())
+	}
+}
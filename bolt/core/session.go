@@ -0,0 +1,65 @@
+package core
+
+// Session is the per-request session provided by session middleware
+// (see middleware/session). Handlers access it with Context.Session.
+//
+// Changes are saved when the handler returns. Methods that change the
+// session ID or clear it (Regenerate, Destroy) also update the session
+// cookie, so call them before writing the response.
+type Session interface {
+	// ID returns the session ID ("" for new or cookie-stored sessions).
+	ID() string
+
+	// Get returns a session value (nil if absent).
+	Get(key string) any
+
+	// Set stores a session value.
+	Set(key string, value any)
+
+	// Delete removes a session value.
+	Delete(key string)
+
+	// Flash stores a value read once by Flashes, typically on the next request.
+	Flash(key string, value any)
+
+	// Flashes returns and removes the flash values stored under key.
+	Flashes(key string) []any
+
+	// Regenerate issues a new session ID and keeps the data.
+	// Call it on privilege changes (login, logout, role change) to prevent
+	// session fixation.
+	Regenerate() error
+
+	// Destroy deletes the session and clears the session cookie.
+	Destroy() error
+}
+
+// SessionKey is the Context store key under which session middleware
+// stores the request's Session.
+const SessionKey = "bolt.session"
+
+// Session returns the request's session.
+//
+// Panics if no session middleware is installed.
+//
+// Example:
+//
+//	app.Post("/login", func(c *bolt.Context) error {
+//	    user, err := authenticate(c)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    sess := c.Session()
+//	    if err := sess.Regenerate(); err != nil {
+//	        return err
+//	    }
+//	    sess.Set("user_id", user.ID)
+//	    return c.JSON(200, user)
+//	})
+func (c *Context) Session() Session {
+	s, ok := c.Get(SessionKey).(Session)
+	if !ok {
+		panic("bolt: Context.Session called without session middleware")
+	}
+	return s
+}
@@ -0,0 +1,392 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// Server-Sent Events (text/event-stream, WHATWG HTML Section 9.2).
+//
+// The response is streamed with chunked transfer encoding and every event is
+// flushed to the client as soon as it is sent. The stream ends when the
+// callback returns or the client disconnects; heartbeat comments keep idle
+// connections open through proxies and detect dead clients.
+
+// SSEConfig defines the config for Server-Sent Events streams.
+type SSEConfig struct {
+	// Retry is sent to the client as the reconnection delay ("retry:" field).
+	// Zero leaves the browser default (~3s).
+	// Default: 0
+	Retry time.Duration
+
+	// Heartbeat is the interval of keep-alive comments sent while the
+	// stream is idle. Zero disables heartbeats.
+	// Default: 15s
+	Heartbeat time.Duration
+
+	// WriteTimeout bounds each write to the client (Shockwave server only).
+	// A client that stops reading ends the stream after this long.
+	// Default: 30s
+	WriteTimeout time.Duration
+}
+
+// DefaultSSEConfig returns the default Server-Sent Events configuration.
+func DefaultSSEConfig() SSEConfig {
+	return SSEConfig{
+		Heartbeat:    15 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+}
+
+// SSEStream sends events to a connected client.
+//
+// Methods are safe for concurrent use: events can be sent from several
+// goroutines while the heartbeat runs in the background.
+type SSEStream struct {
+	c      *Context
+	config SSEConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	lastID  string
+	written time.Time // Last write, used to skip unneeded heartbeats
+	failed  bool      // A write to the client failed
+
+	body io.WriteCloser // Body wrapper writer (nil if unwrapped, see SetBodyWrapper)
+}
+
+// SSE streams Server-Sent Events to the client using DefaultSSEConfig.
+//
+// fn runs for the lifetime of the stream; return from it to end the stream.
+// Use stream.Done() to stop when the client disconnects or the server
+// shuts down. Returns nil when the stream ended because the client went away.
+//
+// Example:
+//
+//	app.Get("/events", func(c *bolt.Context) error {
+//	    return c.SSE(func(stream *bolt.SSEStream) error {
+//	        updates := hub.Subscribe(stream.LastEventID())
+//	        defer hub.Unsubscribe(updates)
+//
+//	        for {
+//	            select {
+//	            case <-stream.Done():
+//	                return nil
+//	            case u := <-updates:
+//	                if err := stream.Send("update", u.ID, u); err != nil {
+//	                    return err
+//	                }
+//	            }
+//	        }
+//	    })
+//	})
+func (c *Context) SSE(fn func(stream *SSEStream) error) error {
+	return c.SSEWithConfig(DefaultSSEConfig(), fn)
+}
+
+// SSEWithConfig streams Server-Sent Events to the client with custom config.
+//
+// Example:
+//
+//	return c.SSEWithConfig(bolt.SSEConfig{
+//	    Retry:     5 * time.Second,
+//	    Heartbeat: 30 * time.Second,
+//	}, streamDashboard)
+func (c *Context) SSEWithConfig(config SSEConfig, fn func(stream *SSEStream) error) error {
+	ctx, cancel := context.WithCancel(c.Context())
+	defer cancel()
+
+	stream := &SSEStream{
+		c:      c,
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+		lastID: c.GetHeader("Last-Event-ID"),
+	}
+
+	c.SetHeader("Content-Type", "text/event-stream")
+	c.SetHeader("Cache-Control", "no-cache")
+	c.SetHeader("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	if c.bodyWrapper != nil {
+		stream.body = c.wrapStream(200, -1)
+	}
+	c.statusCode = 200
+	c.written = true
+
+	if conn := stream.conn(); conn != nil {
+		// The stream outlives the server's per-request read deadline;
+		// writes are bounded by WriteTimeout instead
+		conn.SetReadDeadline(time.Time{})
+	}
+
+	// Send headers (and the retry hint) right away so the client sees the stream open
+	stream.mu.Lock()
+	if config.Retry > 0 {
+		writeSSEField(&stream.buf, "retry", strconv.FormatInt(config.Retry.Milliseconds(), 10))
+		stream.buf.WriteByte('\n')
+	}
+	err := stream.flushLocked()
+	stream.mu.Unlock()
+	if err != nil {
+		return nil
+	}
+
+	var heartbeat sync.WaitGroup
+	if config.Heartbeat > 0 {
+		heartbeat.Add(1)
+		go func() {
+			defer heartbeat.Done()
+			stream.heartbeat()
+		}()
+	}
+
+	err = fn(stream)
+	cancel()
+	heartbeat.Wait()
+
+	stream.mu.Lock()
+	if stream.body != nil && !stream.failed {
+		_ = stream.body.Close()
+	}
+	if c.shockwaveRes != nil && !stream.failed {
+		_ = c.shockwaveRes.FinishChunked()
+	}
+	failed := stream.failed
+	stream.mu.Unlock()
+
+	if err != nil && (failed || errors.Is(err, context.Canceled) || c.Context().Err() != nil) {
+		// Client disconnected or server shutting down
+		return nil
+	}
+	return err
+}
+
+// Send sends an event to the client.
+//
+// event sets the event type (empty for the default "message" type) and id
+// the event ID the browser reports in Last-Event-ID when reconnecting.
+// data is written as is for string and []byte values and JSON-encoded
+// otherwise; multi-line data is split into several "data:" fields.
+//
+// Returns an error if the client disconnected.
+//
+// Example:
+//
+//	stream.Send("price", "42", map[string]any{"symbol": "ACME", "price": 12.5})
+//	// event: price
+//	// id: 42
+//	// data: {"symbol":"ACME","price":12.5}
+func (s *SSEStream) Send(event, id string, data any) error {
+	var payload []byte
+	switch v := data.(type) {
+	case nil:
+	case string:
+		payload = []byte(v)
+	case []byte:
+		payload = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		payload = encoded
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if event != "" {
+		writeSSEField(&s.buf, "event", event)
+	}
+	if id != "" {
+		id = sseFieldValue(id)
+		writeSSEField(&s.buf, "id", id)
+		s.lastID = id
+	}
+	for {
+		// Clients end lines at CRLF, LF or a lone CR: split on all three so
+		// data can't inject fields
+		end := bytes.IndexAny(payload, "\r\n")
+		if end < 0 {
+			end = len(payload)
+		}
+		s.buf.WriteString("data: ")
+		s.buf.Write(payload[:end])
+		s.buf.WriteByte('\n')
+		if end == len(payload) {
+			break
+		}
+		if payload[end] == '\r' && end+1 < len(payload) && payload[end+1] == '\n' {
+			end++
+		}
+		payload = payload[end+1:]
+	}
+	s.buf.WriteByte('\n')
+	return s.flushLocked()
+}
+
+// Retry tells the client to wait d before reconnecting.
+func (s *SSEStream) Retry(d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	writeSSEField(&s.buf, "retry", strconv.FormatInt(d.Milliseconds(), 10))
+	s.buf.WriteByte('\n')
+	return s.flushLocked()
+}
+
+// Comment sends a comment line, ignored by clients.
+func (s *SSEStream) Comment(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	writeSSEField(&s.buf, "", text)
+	s.buf.WriteByte('\n')
+	return s.flushLocked()
+}
+
+// LastEventID returns the ID of the last event sent, initially the
+// Last-Event-ID the client sent when reconnecting ("" for new clients).
+//
+// Use it to resume: replay events the client missed before streaming new ones.
+func (s *SSEStream) LastEventID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastID
+}
+
+// Context returns the stream context, canceled when the client disconnects,
+// the server shuts down or the stream ends.
+func (s *SSEStream) Context() context.Context {
+	return s.ctx
+}
+
+// Done returns a channel closed when the stream ends (see Context).
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// heartbeat sends keep-alive comments until the stream ends.
+func (s *SSEStream) heartbeat() {
+	ticker := time.NewTicker(s.config.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			if s.ctx.Err() == nil && now.Sub(s.written) >= s.config.Heartbeat/2 {
+				s.buf.WriteString(":\n\n")
+				s.flushLocked()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// flushLocked writes the buffered data to the client and flushes it.
+// A failed write ends the stream. s.mu must be held.
+func (s *SSEStream) flushLocked() error {
+	c := s.c
+	data := s.buf.Bytes()
+	s.buf.Reset()
+	s.written = time.Now()
+
+	var err error
+	switch {
+	case s.body != nil:
+		if conn := s.conn(); conn != nil && s.config.WriteTimeout > 0 {
+			conn.SetWriteDeadline(s.written.Add(s.config.WriteTimeout))
+		}
+		err = s.flushBody(data)
+	case c.httpRes != nil:
+		if len(data) > 0 {
+			_, err = c.httpRes.Write(data)
+		} else {
+			c.httpRes.WriteHeader(200)
+		}
+		if flusher, ok := c.httpRes.(http.Flusher); ok && err == nil {
+			flusher.Flush()
+		}
+	case c.shockwaveRes != nil:
+		if conn := s.conn(); conn != nil && s.config.WriteTimeout > 0 {
+			conn.SetWriteDeadline(s.written.Add(s.config.WriteTimeout))
+		}
+		if err = c.shockwaveRes.WriteChunk(data); err == nil {
+			err = c.shockwaveRes.Flush()
+		}
+	}
+
+	if err != nil {
+		s.failed = true
+		s.cancel()
+	}
+	return err
+}
+
+// flushBody writes data through the body wrapper and flushes it to the client.
+func (s *SSEStream) flushBody(data []byte) error {
+	if _, err := s.body.Write(data); err != nil {
+		return err
+	}
+	if flusher, ok := s.body.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return err
+		}
+	}
+	if s.c.httpRes != nil {
+		if flusher, ok := s.c.httpRes.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	}
+	if s.c.shockwaveRes != nil {
+		return s.c.shockwaveRes.Flush()
+	}
+	return nil
+}
+
+// conn returns the network connection of a Shockwave response (nil otherwise).
+func (s *SSEStream) conn() net.Conn {
+	if s.c.shockwaveRes == nil {
+		return nil
+	}
+	return s.c.shockwaveRes.Conn()
+}
+
+// writeSSEField writes "name: value\n". An empty name writes a comment.
+func writeSSEField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	buf.WriteString(": ")
+	buf.WriteString(sseFieldValue(value))
+	buf.WriteByte('\n')
+}
+
+// sseFieldValue drops line breaks that would let value inject extra fields.
+func sseFieldValue(value string) string {
+	if !strings.ContainsAny(value, "\r\n") {
+		return value
+	}
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,217 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSSE tests event formatting, line break handling, retry hints and
+// Last-Event-ID resume.
+func TestSSE(t *testing.T) {
+	app := New()
+	app.Get("/events", func(c *Context) error {
+		config := DefaultSSEConfig()
+		config.Retry = 2 * time.Second
+		return c.SSEWithConfig(config, func(stream *SSEStream) error {
+			stream.Send("", "", "resume after "+stream.LastEventID())
+			stream.Send("update", "8", "line1\nline2")
+			stream.Send("", "", "a\revent: evil\r\nretry: 1\rid: x\n\r")
+			stream.Send("user", "9\nid: forged", struct {
+				Name string `json:"name"`
+			}{"Ada"})
+			stream.Comment("bye")
+			if stream.LastEventID() != "9id: forged" {
+				t.Errorf("unexpected last event ID %q", stream.LastEventID())
+			}
+			return errors.New("failed after the stream started")
+		})
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/events", nil)
+	req.Header.Set("Last-Event-ID", "7")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("unexpected Cache-Control %q", cc)
+	}
+
+	// The error handler must not append a JSON body to the stream
+	want := "retry: 2000\n\n" +
+		"data: resume after 7\n\n" +
+		"event: update\nid: 8\ndata: line1\ndata: line2\n\n" +
+		"data: a\ndata: event: evil\ndata: retry: 1\ndata: id: x\ndata: \ndata: \n\n" +
+		"event: user\nid: 9id: forged\ndata: {\"name\":\"Ada\"}\n\n" +
+		": bye\n\n"
+	if string(body) != want {
+		t.Errorf("unexpected stream:\n%q\nwant:\n%q", body, want)
+	}
+}
+
+// TestSSEDisconnect tests that the stream ends when the client goes away.
+func TestSSEDisconnect(t *testing.T) {
+	result := make(chan error, 1)
+	app := New()
+	app.Get("/events", func(c *Context) error {
+		err := c.SSE(func(stream *SSEStream) error {
+			ticker := time.NewTicker(5 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stream.Done():
+					return stream.Context().Err()
+				case <-ticker.C:
+					if err := stream.Send("tick", "", "x"); err != nil {
+						return err
+					}
+				}
+			}
+		})
+		result <- err
+		return err
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+	if line != "event: tick\n" {
+		t.Errorf("unexpected first line %q", line)
+	}
+	cancel()
+	resp.Body.Close()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("expected nil after disconnect, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream didn't end after client disconnect")
+	}
+}
+
+// TestSSEHeartbeat tests keep-alive comments on idle streams.
+func TestSSEHeartbeat(t *testing.T) {
+	app := New()
+	app.Get("/events", func(c *Context) error {
+		return c.SSEWithConfig(SSEConfig{Heartbeat: 10 * time.Millisecond}, func(stream *SSEStream) error {
+			time.Sleep(60 * time.Millisecond)
+			return nil
+		})
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if !strings.HasPrefix(string(body), ":\n\n") || strings.Trim(string(body), ":\n") != "" {
+		t.Errorf("expected heartbeat comments only, got %q", body)
+	}
+}
+
+// TestSSEShockwave tests chunked streaming through the Shockwave server.
+func TestSSEShockwave(t *testing.T) {
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+
+	ts.app.Get("/events", func(c *Context) error {
+		return c.SSE(func(stream *SSEStream) error {
+			for _, id := range []string{"1", "2", "3"} {
+				if err := stream.Send("count", id, id); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	ended := make(chan struct{})
+	ts.app.Get("/ticks", func(c *Context) error {
+		defer close(ended)
+		return c.SSE(func(stream *SSEStream) error {
+			for {
+				if err := stream.Send("tick", "", "x"); err != nil {
+					return err
+				}
+				select {
+				case <-stream.Done():
+					return nil
+				case <-time.After(5 * time.Millisecond):
+				}
+			}
+		})
+	})
+	ts.app.Get("/after", func(c *Context) error {
+		return c.JSON(200, map[string]string{"ok": "true"})
+	})
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(ts.url + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	want := "event: count\nid: 1\ndata: 1\n\nevent: count\nid: 2\ndata: 2\n\nevent: count\nid: 3\ndata: 3\n\n"
+	if string(body) != want || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("unexpected stream %q (%s)", body, resp.Header.Get("Content-Type"))
+	}
+
+	// Client disconnect ends the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.url+"/ticks", nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bufio.NewReader(resp.Body).ReadString('\n')
+	cancel()
+	resp.Body.Close()
+	select {
+	case <-ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream didn't end after client disconnect")
+	}
+
+	// The connection is reusable after the stream ends
+	resp, err = client.Get(ts.url + "/after")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("expected 200 after stream, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,579 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Static file serving.
+//
+// Files are served from an fs.FS (a directory, embed.FS, ...) under a route
+// prefix. Each response carries Content-Type, Content-Length, Last-Modified,
+// ETag and Accept-Ranges headers, and the handler answers:
+//   - conditional requests (If-None-Match, If-Modified-Since) with 304
+//   - Range requests with 206, using multipart/byteranges for several ranges
+//   - unsatisfiable ranges with 416
+//
+// Files from a directory are transmitted with sendfile(2) on Linux when the
+// Shockwave response writer is attached to a connection (zero-copy).
+
+// StaticConfig defines the config for static file serving.
+type StaticConfig struct {
+	// Index is the file served for directory requests.
+	// Empty disables index files (directories return 404).
+	// Default: "index.html"
+	Index string
+
+	// MaxAge sets "Cache-Control: public, max-age=N".
+	// Zero sends no Cache-Control header.
+	// Default: 0
+	MaxAge time.Duration
+
+	// Precompressed serves "name.br" or "name.gz" siblings to clients
+	// accepting that encoding.
+	// Default: true
+	Precompressed bool
+}
+
+// DefaultStaticConfig returns the default static file configuration.
+func DefaultStaticConfig() StaticConfig {
+	return StaticConfig{
+		Index:         "index.html",
+		Precompressed: true,
+	}
+}
+
+// maxRanges limits the number of ranges honored in a single request.
+const maxRanges = 32
+
+// precompressedEncodings lists sibling suffixes in order of preference.
+var precompressedEncodings = [...]struct{ encoding, suffix string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// Static serves files from the directory root under prefix.
+//
+// Paths are resolved inside root: ".." segments and symlinks leading out of
+// root are rejected. Panics if root can't be opened.
+//
+// Example:
+//
+//	app.Static("/assets", "./public")
+//	// GET /assets/css/app.css → ./public/css/app.css
+func (app *App) Static(prefix, root string) {
+	app.StaticWithConfig(prefix, staticDirFS(root), DefaultStaticConfig())
+}
+
+// StaticFS serves files from fsys under prefix.
+//
+// Example:
+//
+//	//go:embed dist
+//	var dist embed.FS
+//
+//	sub, _ := fs.Sub(dist, "dist")
+//	app.StaticFS("/", sub)
+func (app *App) StaticFS(prefix string, fsys fs.FS) {
+	app.StaticWithConfig(prefix, fsys, DefaultStaticConfig())
+}
+
+// StaticWithConfig serves files from fsys under prefix with custom config.
+//
+// Example:
+//
+//	app.StaticWithConfig("/assets", os.DirFS("./public"), bolt.StaticConfig{
+//	    MaxAge:        24 * time.Hour,
+//	    Precompressed: true,
+//	})
+func (app *App) StaticWithConfig(prefix string, fsys fs.FS, config StaticConfig) {
+	registerStatic(app, nil, joinPaths("", prefix), fsys, config)
+}
+
+// Static serves files from the directory root under the group prefix.
+func (g *Group) Static(prefix, root string) {
+	g.StaticWithConfig(prefix, staticDirFS(root), DefaultStaticConfig())
+}
+
+// StaticFS serves files from fsys under the group prefix.
+func (g *Group) StaticFS(prefix string, fsys fs.FS) {
+	g.StaticWithConfig(prefix, fsys, DefaultStaticConfig())
+}
+
+// StaticWithConfig serves files from fsys under the group prefix with custom config.
+func (g *Group) StaticWithConfig(prefix string, fsys fs.FS, config StaticConfig) {
+	registerStatic(g.app, g, joinPaths(g.prefix, prefix), fsys, config)
+}
+
+// staticDirFS opens root as a file system confined to the directory.
+func staticDirFS(root string) fs.FS {
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		panic(fmt.Sprintf("bolt: static root: %v", err))
+	}
+	return r.FS()
+}
+
+// registerStatic registers GET and HEAD routes for prefix and everything below it.
+func registerStatic(app *App, group *Group, prefix string, fsys fs.FS, config StaticConfig) {
+	handler := staticHandler(fsys, config)
+
+	// The wildcard doesn't match an empty remainder: the directory itself
+	// is registered as a static route ("/assets" redirects to "/assets/")
+	dir := strings.TrimSuffix(prefix, "/") + "/"
+	patterns := []string{dir + "*filepath", dir}
+	if prefix != dir {
+		patterns = append(patterns, prefix)
+	}
+	for _, pattern := range patterns {
+		for _, method := range []HTTPMethod{MethodGet, MethodHead} {
+			app.addGroupRoute(group, method, pattern, handler).Hidden()
+		}
+	}
+}
+
+// staticHandler returns the handler serving files from fsys.
+func staticHandler(fsys fs.FS, config StaticConfig) Handler {
+	return func(c *Context) error {
+		name := path.Clean("/" + c.Param("filepath"))[1:]
+		if name == "" {
+			name = "."
+		}
+		if !fs.ValidPath(name) || strings.ContainsAny(name, "\\\x00") {
+			return ErrNotFound
+		}
+
+		f, info, err := openStatic(fsys, name)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			f.Close()
+
+			// Relative references in index files need the trailing slash
+			if p := c.Path(); !strings.HasSuffix(p, "/") {
+				c.SetHeader("Location", p+"/")
+				return c.sendStatus(http.StatusMovedPermanently)
+			}
+			if config.Index == "" {
+				return ErrNotFound
+			}
+			name = path.Join(name, config.Index)
+			if f, info, err = openStatic(fsys, name); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				f.Close()
+				return ErrNotFound
+			}
+		}
+		defer f.Close()
+
+		ctype := staticContentType(name, f)
+
+		if config.Precompressed {
+			if cf, cinfo, encoding, vary := openPrecompressed(fsys, name, c.GetHeader("Accept-Encoding")); vary {
+				c.Vary("Accept-Encoding")
+				if cf != nil {
+					defer cf.Close()
+					c.SetHeader("Content-Encoding", encoding)
+					f, info = cf, cinfo
+				}
+			}
+		}
+		if config.MaxAge > 0 {
+			c.SetHeader("Cache-Control", "public, max-age="+strconv.FormatInt(int64(config.MaxAge/time.Second), 10))
+		}
+		return serveContent(c, f, info, ctype)
+	}
+}
+
+// openStatic opens name, mapping file system errors to HTTP errors.
+func openStatic(fsys fs.FS, name string) (fs.File, fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, staticError(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, staticError(err)
+	}
+	return f, info, nil
+}
+
+// staticError maps a file system error to ErrNotFound or ErrForbidden.
+func staticError(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return ErrForbidden
+	}
+	return ErrNotFound
+}
+
+// openPrecompressed opens the preferred precompressed sibling of name
+// accepted by the client. vary reports whether any sibling exists, in which
+// case the response depends on Accept-Encoding.
+func openPrecompressed(fsys fs.FS, name, acceptEncoding string) (f fs.File, info fs.FileInfo, encoding string, vary bool) {
+	for _, enc := range precompressedEncodings {
+		sf, sinfo, err := openStatic(fsys, name+enc.suffix)
+		if err != nil {
+			continue
+		}
+		if sinfo.IsDir() {
+			sf.Close()
+			continue
+		}
+		vary = true
+		if f == nil && acceptsEncoding(acceptEncoding, enc.encoding) {
+			f, info, encoding = sf, sinfo, enc.encoding
+			continue
+		}
+		sf.Close()
+	}
+	return f, info, encoding, vary
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows encoding.
+func acceptsEncoding(header, encoding string) bool {
+	for header != "" {
+		var item string
+		item, header, _ = strings.Cut(header, ",")
+		token, params, _ := strings.Cut(item, ";")
+		token = strings.TrimSpace(token)
+		if !strings.EqualFold(token, encoding) && token != "*" {
+			continue
+		}
+		q := strings.TrimSpace(params)
+		if v, ok := strings.CutPrefix(q, "q="); ok {
+			if weight, err := strconv.ParseFloat(v, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// staticContentType returns the MIME type of name, sniffing the content when
+// the extension is unknown.
+func staticContentType(name string, f fs.File) string {
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		return ctype
+	}
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		return "application/octet-stream"
+	}
+	var buf [512]byte
+	n, _ := io.ReadFull(rs, buf[:])
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return "application/octet-stream"
+	}
+	return http.DetectContentType(buf[:n])
+}
+
+// serveContent writes f, honoring conditional and Range requests.
+//
+// The caller sets any Content-Encoding beforehand.
+func serveContent(c *Context, f fs.File, info fs.FileInfo, ctype string) error {
+	size := info.Size()
+	modTime := info.ModTime()
+	etag := staticETag(size, modTime)
+
+	c.SetHeader("Content-Type", ctype)
+	c.SetHeader("ETag", etag)
+	if !modTime.IsZero() {
+		c.SetHeader("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, modTime) {
+		return c.sendStatus(http.StatusNotModified)
+	}
+
+	rs, seekable := f.(io.ReadSeeker)
+	if !seekable {
+		return sendRanges(c, f, http.StatusOK, nil, size, ctype)
+	}
+	c.SetHeader("Accept-Ranges", "bytes")
+
+	rangeHeader := c.GetHeader("Range")
+	if rangeHeader == "" || !rangeApplies(c.GetHeader("If-Range"), etag, modTime) {
+		return sendRanges(c, rs, http.StatusOK, nil, size, ctype)
+	}
+
+	ranges, err := parseRange(rangeHeader, size)
+	if err != nil {
+		c.SetHeader("Content-Range", "bytes */"+strconv.FormatInt(size, 10))
+		return c.sendStatus(http.StatusRequestedRangeNotSatisfiable)
+	}
+	if ranges == nil {
+		// Malformed or too expensive: ignore the header
+		return sendRanges(c, rs, http.StatusOK, nil, size, ctype)
+	}
+	return sendRanges(c, rs, http.StatusPartialContent, ranges, size, ctype)
+}
+
+// staticETag returns a strong validator derived from size and modification time.
+func staticETag(size int64, modTime time.Time) string {
+	return `"` + strconv.FormatInt(modTime.UnixNano(), 36) + "-" + strconv.FormatInt(size, 36) + `"`
+}
+
+// notModified evaluates If-None-Match and If-Modified-Since (RFC 9110 Section 13.2.2).
+func notModified(c *Context, etag string, modTime time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		return etagListMatches(inm, etag)
+	}
+	ims := c.GetHeader("If-Modified-Since")
+	if ims == "" || modTime.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !modTime.Truncate(time.Second).After(t)
+}
+
+// etagListMatches reports whether an If-None-Match list matches etag (weak comparison).
+func etagListMatches(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	for list != "" {
+		var tag string
+		tag, list, _ = strings.Cut(list, ",")
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeApplies evaluates If-Range: the Range header is honored only if the
+// validator still matches (strong comparison for entity tags).
+func rangeApplies(ifRange, etag string, modTime time.Time) bool {
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) {
+		return ifRange == etag
+	}
+	t, err := http.ParseTime(ifRange)
+	return err == nil && !modTime.IsZero() && modTime.Truncate(time.Second).Equal(t)
+}
+
+// byteRange is a satisfiable range: length bytes starting at start.
+type byteRange struct {
+	start, length int64
+}
+
+// contentRange returns the Content-Range value of r.
+func (r byteRange) contentRange(size int64) string {
+	return "bytes " + strconv.FormatInt(r.start, 10) + "-" + strconv.FormatInt(r.start+r.length-1, 10) + "/" + strconv.FormatInt(size, 10)
+}
+
+// errRangeNotSatisfiable reports a Range header with no satisfiable range.
+var errRangeNotSatisfiable = errors.New("bolt: range not satisfiable")
+
+// parseRange parses a Range header (RFC 9110 Section 14.2).
+//
+// Returns nil ranges for headers that must be ignored (malformed, not in
+// bytes, or requesting more than maxRanges ranges or more than the file), and
+// errRangeNotSatisfiable when no range overlaps the file.
+func parseRange(header string, size int64) ([]byteRange, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, nil
+	}
+
+	var ranges []byteRange
+	var total int64
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		first, last, ok := strings.Cut(item, "-")
+		if !ok {
+			return nil, nil
+		}
+		first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+
+		var r byteRange
+		if first == "" {
+			// Suffix range: the last N bytes
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, nil
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			r.length = min(n, size)
+			r.start = size - r.length
+		} else {
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, nil
+			}
+			end := size - 1
+			if last != "" {
+				if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+					return nil, nil
+				}
+			}
+			if start >= size {
+				continue
+			}
+			r.start = start
+			r.length = min(end, size-1) - start + 1
+		}
+		ranges = append(ranges, r)
+		total += r.length
+	}
+
+	if len(ranges) == 0 {
+		return nil, errRangeNotSatisfiable
+	}
+	if len(ranges) > maxRanges || total > size {
+		return nil, nil
+	}
+	return ranges, nil
+}
+
+// sendRanges writes the response body: the whole file for nil ranges, a
+// single part, or a multipart/byteranges body.
+func sendRanges(c *Context, r io.Reader, status int, ranges []byteRange, size int64, ctype string) error {
+	var boundary string
+	var parts []string
+	length := size
+
+	switch len(ranges) {
+	case 0:
+	case 1:
+		c.SetHeader("Content-Range", ranges[0].contentRange(size))
+		length = ranges[0].length
+	default:
+		boundary = multipartBoundary()
+		c.SetHeader("Content-Type", "multipart/byteranges; boundary="+boundary)
+
+		parts = make([]string, len(ranges))
+		length = int64(len(boundary) + 8) // "\r\n--" boundary "--\r\n"
+		for i, br := range ranges {
+			parts[i] = "\r\n--" + boundary + "\r\nContent-Type: " + ctype + "\r\nContent-Range: " + br.contentRange(size) + "\r\n\r\n"
+			length += int64(len(parts[i])) + br.length
+		}
+	}
+	c.SetHeader("Content-Length", strconv.FormatInt(length, 10))
+
+	// Body wrapper (e.g. compression): whole files only, copied instead of sendfile
+	if c.bodyWrapper != nil && ranges == nil && c.Method() != "HEAD" {
+		if w := c.wrapStream(status, size); w != nil {
+			if seeker, ok := r.(io.Seeker); ok {
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+			}
+			if _, err := io.CopyN(w, r, size); err != nil {
+				return err
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			if c.shockwaveRes != nil {
+				return c.shockwaveRes.FinishChunked()
+			}
+			return nil
+		}
+	}
+
+	c.statusCode = status
+	c.written = true
+
+	var w io.Writer
+	switch {
+	case c.httpRes != nil:
+		c.httpRes.WriteHeader(status)
+		w = c.httpRes
+	case c.shockwaveRes != nil:
+		c.shockwaveRes.WriteHeader(status)
+		w = c.shockwaveRes
+	default:
+		// No response writer (unit tests)
+		return nil
+	}
+	if c.Method() == "HEAD" {
+		return nil
+	}
+
+	if ranges == nil {
+		return c.copyFile(w, r, 0, size)
+	}
+	if len(ranges) == 1 {
+		return c.copyFile(w, r, ranges[0].start, ranges[0].length)
+	}
+	for i, br := range ranges {
+		if _, err := io.WriteString(w, parts[i]); err != nil {
+			return err
+		}
+		if err := c.copyFile(w, r, br.start, br.length); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\r\n--"+boundary+"--\r\n")
+	return err
+}
+
+// copyFile writes length bytes of r starting at offset to w.
+//
+// ✅ OPTIMIZATION: *os.File bodies go through the Shockwave writer's
+// SendFile (sendfile(2) on Linux, no user-space copy).
+func (c *Context) copyFile(w io.Writer, r io.Reader, offset, length int64) error {
+	if f, ok := r.(*os.File); ok && c.shockwaveRes != nil && c.httpRes == nil {
+		_, err := c.shockwaveRes.SendFile(f, offset, length)
+		return err
+	}
+	if seeker, ok := r.(io.Seeker); ok {
+		if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	_, err := io.CopyN(w, r, length)
+	return err
+}
+
+// sendStatus writes a response with no body.
+func (c *Context) sendStatus(status int) error {
+	c.statusCode = status
+	c.written = true
+
+	if c.httpRes != nil {
+		c.httpRes.WriteHeader(status)
+		return nil
+	}
+	if c.shockwaveRes != nil {
+		if status != http.StatusNotModified {
+			c.SetHeader("Content-Length", "0")
+		}
+		c.shockwaveRes.WriteHeader(status)
+	}
+	return nil
+}
+
+// multipartBoundary returns a random multipart boundary.
+func multipartBoundary() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,345 @@
+package core
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// staticModTime is the modification time of files in test file systems.
+var staticModTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newStaticTestFS returns an in-memory file system for static tests.
+func newStaticTestFS() fstest.MapFS {
+	file := func(data string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(data), ModTime: staticModTime}
+	}
+	return fstest.MapFS{
+		"index.html":      file("<h1>home</h1>"),
+		"app.js":          file("console.log('app')"),
+		"app.js.br":       file("BROTLI"),
+		"app.js.gz":       file("GZIP"),
+		"data.txt":        file("0123456789abcdefghij"),
+		"docs/index.html": file("<h1>docs</h1>"),
+		"noext":           file("plain text content"),
+	}
+}
+
+// doStatic performs a request against app with optional headers.
+func doStatic(app *App, method, path string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+// TestStaticDir tests serving from a directory, including traversal protection.
+func TestStaticDir(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "public")
+	if err := os.MkdirAll(filepath.Join(root, "css"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"public/css/app.css": "body{}",
+		"public/index.html":  "<h1>index</h1>",
+		"secret.txt":         "secret",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	app := New()
+	app.Static("/assets", root)
+
+	w := doStatic(app, "GET", "/assets/css/app.css")
+	if w.Code != 200 || w.Body.String() != "body{}" {
+		t.Fatalf("expected css file, got %d %q", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != mime.TypeByExtension(".css") {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	if w.Header().Get("Content-Length") != "6" || w.Header().Get("Accept-Ranges") != "bytes" {
+		t.Errorf("unexpected headers %v", w.Header())
+	}
+
+	if w := doStatic(app, "GET", "/assets/"); w.Code != 200 || w.Body.String() != "<h1>index</h1>" {
+		t.Errorf("expected index, got %d %q", w.Code, w.Body.String())
+	}
+	if w := doStatic(app, "GET", "/assets"); w.Code != 301 || w.Header().Get("Location") != "/assets/" {
+		t.Errorf("expected redirect, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if w := doStatic(app, "GET", "/assets/css"); w.Code != 301 || w.Header().Get("Location") != "/assets/css/" {
+		t.Errorf("expected redirect, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if w := doStatic(app, "GET", "/assets/css/"); w.Code != 404 {
+		t.Errorf("directory without index: expected 404, got %d", w.Code)
+	}
+
+	// Nothing outside the root is reachable
+	for _, path := range []string{"/assets/../secret.txt", "/assets/css/../../secret.txt", "/assets/link.txt", "/assets/missing.css"} {
+		if w := doStatic(app, "GET", path); w.Code != 404 || strings.Contains(w.Body.String(), "secret") {
+			t.Errorf("%s: expected 404, got %d %q", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestStaticFS tests serving an fs.FS at the root, HEAD requests and content sniffing.
+func TestStaticFS(t *testing.T) {
+	app := New()
+	app.StaticFS("/", newStaticTestFS())
+
+	if w := doStatic(app, "GET", "/"); w.Code != 200 || w.Body.String() != "<h1>home</h1>" {
+		t.Errorf("expected index, got %d %q", w.Code, w.Body.String())
+	}
+	if w := doStatic(app, "GET", "/docs/"); w.Code != 200 || w.Body.String() != "<h1>docs</h1>" {
+		t.Errorf("expected docs index, got %d %q", w.Code, w.Body.String())
+	}
+	if w := doStatic(app, "GET", "/noext"); !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("expected sniffed text/plain, got %q", w.Header().Get("Content-Type"))
+	}
+
+	w := doStatic(app, "HEAD", "/data.txt")
+	if w.Code != 200 || w.Body.Len() != 0 || w.Header().Get("Content-Length") != "20" {
+		t.Errorf("HEAD: got %d, body %q, length %q", w.Code, w.Body.String(), w.Header().Get("Content-Length"))
+	}
+
+	// Static routes are excluded from the OpenAPI document
+	if doc := app.OpenAPI(OpenAPIInfo{Title: "Test", Version: "1"}); len(doc.Paths) != 0 {
+		t.Errorf("expected no documented paths, got %v", doc.Paths)
+	}
+}
+
+// TestStaticConditional tests ETag and Last-Modified validation.
+func TestStaticConditional(t *testing.T) {
+	app := New()
+	config := DefaultStaticConfig()
+	config.MaxAge = time.Hour
+	app.StaticWithConfig("/static", newStaticTestFS(), config)
+
+	w := doStatic(app, "GET", "/static/data.txt")
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") != staticModTime.Format(http.TimeFormat) {
+		t.Fatalf("missing validators: %v", w.Header())
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "public, max-age=3600" {
+		t.Errorf("unexpected Cache-Control %q", cc)
+	}
+
+	tests := []struct {
+		name    string
+		headers []string
+		code    int
+	}{
+		{"etag match", []string{"If-None-Match", etag}, 304},
+		{"weak etag match", []string{"If-None-Match", `"other", W/` + etag}, 304},
+		{"wildcard", []string{"If-None-Match", "*"}, 304},
+		{"etag mismatch", []string{"If-None-Match", `"other"`}, 200},
+		{"not modified since", []string{"If-Modified-Since", staticModTime.Format(http.TimeFormat)}, 304},
+		{"modified since", []string{"If-Modified-Since", staticModTime.Add(-time.Hour).Format(http.TimeFormat)}, 200},
+		{"etag takes precedence", []string{"If-None-Match", `"other"`, "If-Modified-Since", staticModTime.Format(http.TimeFormat)}, 200},
+	}
+	for _, tt := range tests {
+		w := doStatic(app, "GET", "/static/data.txt", tt.headers...)
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.code, w.Code)
+		}
+		if tt.code == 304 && w.Body.Len() != 0 {
+			t.Errorf("%s: 304 with body %q", tt.name, w.Body.String())
+		}
+	}
+}
+
+// TestStaticRange tests single, multiple and unsatisfiable ranges.
+func TestStaticRange(t *testing.T) {
+	app := New()
+	app.StaticFS("/", newStaticTestFS())
+	etag := doStatic(app, "GET", "/data.txt").Header().Get("ETag")
+
+	tests := []struct {
+		rangeHeader string
+		ifRange     string
+		code        int
+		body        string
+		content     string
+	}{
+		{"bytes=0-3", "", 206, "0123", "bytes 0-3/20"},
+		{"bytes=15-", "", 206, "fghij", "bytes 15-19/20"},
+		{"bytes=-3", "", 206, "hij", "bytes 17-19/20"},
+		{"bytes=18-100", "", 206, "ij", "bytes 18-19/20"},
+		{"bytes=0-3", etag, 206, "0123", "bytes 0-3/20"},
+		{"bytes=0-3", `"stale"`, 200, "0123456789abcdefghij", ""},
+		{"bytes=0-3", staticModTime.Format(http.TimeFormat), 206, "0123", "bytes 0-3/20"},
+		{"bytes=20-", "", 416, "", "bytes */20"},
+		{"bytes=5-2", "", 200, "0123456789abcdefghij", ""},
+		{"items=0-3", "", 200, "0123456789abcdefghij", ""},
+	}
+	for _, tt := range tests {
+		headers := []string{"Range", tt.rangeHeader}
+		if tt.ifRange != "" {
+			headers = append(headers, "If-Range", tt.ifRange)
+		}
+		w := doStatic(app, "GET", "/data.txt", headers...)
+		if w.Code != tt.code || w.Body.String() != tt.body || w.Header().Get("Content-Range") != tt.content {
+			t.Errorf("%s (If-Range %q): got %d %q %q", tt.rangeHeader, tt.ifRange, w.Code, w.Body.String(), w.Header().Get("Content-Range"))
+		}
+	}
+
+	// Multiple ranges use multipart/byteranges
+	w := doStatic(app, "GET", "/data.txt", "Range", "bytes=0-1, 10-12")
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if w.Code != 206 || err != nil || mediaType != "multipart/byteranges" {
+		t.Fatalf("expected multipart response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w.Header().Get("Content-Length") != "" && w.Header().Get("Content-Length") != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("Content-Length %s doesn't match body length %d", w.Header().Get("Content-Length"), w.Body.Len())
+	}
+
+	reader := multipart.NewReader(w.Body, params["boundary"])
+	want := [][2]string{{"01", "bytes 0-1/20"}, {"abc", "bytes 10-12/20"}}
+	for i := 0; ; i++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			if i != len(want) {
+				t.Errorf("expected %d parts, got %d", len(want), i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(part)
+		if i >= len(want) || string(data) != want[i][0] || part.Header.Get("Content-Range") != want[i][1] {
+			t.Errorf("part %d: %q %q", i, data, part.Header.Get("Content-Range"))
+		}
+		if part.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Errorf("part %d: unexpected Content-Type %q", i, part.Header.Get("Content-Type"))
+		}
+	}
+}
+
+// TestStaticPrecompressed tests .br/.gz sibling selection.
+func TestStaticPrecompressed(t *testing.T) {
+	app := New()
+	app.StaticFS("/", newStaticTestFS())
+
+	tests := []struct {
+		acceptEncoding string
+		body           string
+		encoding       string
+	}{
+		{"gzip, br", "BROTLI", "br"},
+		{"gzip", "GZIP", "gzip"},
+		{"br;q=0, gzip", "GZIP", "gzip"},
+		{"identity", "console.log('app')", ""},
+		{"", "console.log('app')", ""},
+	}
+	for _, tt := range tests {
+		w := doStatic(app, "GET", "/app.js", "Accept-Encoding", tt.acceptEncoding)
+		if w.Body.String() != tt.body || w.Header().Get("Content-Encoding") != tt.encoding {
+			t.Errorf("%q: got %q encoding %q", tt.acceptEncoding, w.Body.String(), w.Header().Get("Content-Encoding"))
+		}
+		if !strings.Contains(w.Header().Get("Content-Type"), "javascript") {
+			t.Errorf("%q: unexpected Content-Type %q", tt.acceptEncoding, w.Header().Get("Content-Type"))
+		}
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%q: missing Vary header", tt.acceptEncoding)
+		}
+	}
+
+	if w := doStatic(app, "GET", "/data.txt", "Accept-Encoding", "br"); w.Header().Get("Vary") != "" {
+		t.Errorf("unexpected Vary for file without siblings")
+	}
+
+	config := DefaultStaticConfig()
+	config.Precompressed = false
+	plain := New()
+	plain.StaticWithConfig("/", newStaticTestFS(), config)
+	if w := doStatic(plain, "GET", "/app.js", "Accept-Encoding", "br"); w.Body.String() != "console.log('app')" {
+		t.Errorf("precompressed disabled: got %q", w.Body.String())
+	}
+}
+
+// TestParseRange tests Range header parsing.
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []byteRange
+		err    bool
+	}{
+		{"bytes=0-0", []byteRange{{0, 1}}, false},
+		{"bytes=0-4,6-", []byteRange{{0, 5}, {6, 4}}, false},
+		{"bytes=-20", []byteRange{{0, 10}}, false},
+		{"bytes=10-", nil, true},
+		{"bytes=-0", nil, true},
+		{"bytes=abc", nil, false},
+		{"bytes=0-9,0-9", nil, false}, // Exceeds the file size in total
+		{"bytes=" + strings.Repeat("0-0,", maxRanges+1), nil, false},
+	}
+	for _, tt := range tests {
+		got, err := parseRange(tt.header, 10)
+		if (err != nil) != tt.err || len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, %v", tt.header, got, err)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: range %d = %v, want %v", tt.header, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// TestStaticShockwave tests file transmission through the Shockwave server (sendfile path).
+func TestStaticShockwave(t *testing.T) {
+	root := t.TempDir()
+	data := strings.Repeat("0123456789", 10000)
+	if err := os.WriteFile(filepath.Join(root, "large.txt"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+	ts.app.Static("/files", root)
+
+	if resp := ts.Get("/files/large.txt"); resp.statusCode != 200 || string(resp.body) != data {
+		t.Fatalf("expected file contents, got %d (%d bytes, err %v)", resp.statusCode, len(resp.body), resp.err)
+	}
+
+	// Keep-alive connection: ranges must not corrupt the next response
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+	for _, rangeHeader := range []string{"bytes=5-9", "bytes=0-0,99999-"} {
+		req, _ := http.NewRequest("GET", ts.url+"/files/large.txt", nil)
+		req.Header.Set("Range", rangeHeader)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 206 || strconv.Itoa(len(body)) != resp.Header.Get("Content-Length") {
+			t.Errorf("%s: got %d with %d bytes (Content-Length %s)", rangeHeader, resp.StatusCode, len(body), resp.Header.Get("Content-Length"))
+		}
+		if rangeHeader == "bytes=5-9" && string(body) != "56789" {
+			t.Errorf("%s: got %q", rangeHeader, body)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"context"
 	"errors"
+	"net/http"
+	"reflect"
 )
 
 // HTTPMethod represents an HTTP method.
@@ -61,7 +63,8 @@ type Middleware func(Handler) Handler
 
 // ErrorHandler handles errors returned by handlers.
 //
-// The default error handler sends a 500 Internal Server Error response.
+// The default error handler (DefaultErrorHandler) maps errors to statuses
+// with AsHTTPError; ProblemErrorHandler sends RFC 9457 problem details.
 // Custom error handlers can provide more sophisticated error handling.
 //
 // Example:
@@ -92,18 +95,58 @@ var (
 	// ErrMethodNotAllowed is returned when HTTP method is not supported.
 	ErrMethodNotAllowed = errors.New("method not allowed")
 
+	// ErrNotAcceptable is returned when no response format matches the Accept header.
+	ErrNotAcceptable = errors.New("not acceptable")
+
 	// ErrRequestTooLarge is returned when request body exceeds limits.
 	ErrRequestTooLarge = errors.New("request too large")
 
+	// ErrUnsupportedMediaType is returned when the request Content-Type cannot be handled.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
 	// ErrInternalServerError is returned for internal errors.
 	ErrInternalServerError = errors.New("internal server error")
 )
 
 // RouteInfo contains metadata about a registered route.
 type RouteInfo struct {
-	Method  HTTPMethod
-	Path    string
-	Handler Handler
+	Method     HTTPMethod
+	Path       string
+	Name       string  // Route name for reverse URL building (see ChainLink.Name)
+	Handler    Handler // Final handler with all middleware applied
+	Middleware int     // Number of middleware wrapping the handler (global, group and route)
+
+	Doc RouteDoc // Documentation metadata (see OpenAPI)
+
+	handler    Handler      // Original handler (before middleware)
+	middleware []Middleware // Route-specific middleware (outermost first)
+	group      *Group       // Owning group (nil for top-level routes)
+}
+
+// RouteDoc holds documentation metadata attached to a route through ChainLink.
+//
+// It drives OpenAPI generation and has no effect on request handling.
+type RouteDoc struct {
+	Summary     string
+	Description string
+	OperationID string
+	Tags        []string
+	Deprecated  bool
+	Hidden      bool // Excluded from the OpenAPI document
+
+	// Request is the request type: `path`, `query` and `header` tagged fields
+	// become parameters, the remaining fields the request body.
+	Request reflect.Type
+
+	// Responses lists documented responses in registration order.
+	Responses []ResponseDoc
+}
+
+// ResponseDoc documents one response of a route.
+type ResponseDoc struct {
+	Status      int
+	Description string
+	Type        reflect.Type // nil for responses without a body
 }
 
 // ChainLink allows fluent API for route configuration.
@@ -127,16 +170,123 @@ type ChainLink struct {
 //	    Use(AdminMiddleware())
 func (cl *ChainLink) Use(middleware ...Middleware) *ChainLink {
 	if cl.lastRoute != nil && cl.app != nil {
-		// Wrap the handler with middleware (in reverse order)
-		handler := cl.lastRoute.Handler
-		for i := len(middleware) - 1; i >= 0; i-- {
-			handler = middleware[i](handler)
-		}
-		cl.lastRoute.Handler = handler
+		// Each Use call wraps the previous chain, so new middleware goes first
+		routeMw := make([]Middleware, 0, len(middleware)+len(cl.lastRoute.middleware))
+		routeMw = append(routeMw, middleware...)
+		cl.lastRoute.middleware = append(routeMw, cl.lastRoute.middleware...)
 
 		// Re-register the route with the updated handler
 		// This overwrites the previous registration
-		cl.app.router.Add(cl.lastRoute.Method, cl.lastRoute.Path, handler)
+		cl.app.register(cl.lastRoute)
+	}
+	return cl
+}
+
+// Name names the route for reverse URL building with App.URL.
+//
+// Names must be unique; reusing a name for another route panics.
+//
+// Example:
+//
+//	app.Get("/users/:id", showUser).Name("user.show")
+//
+//	url, _ := app.URL("user.show", "id", "42") // "/users/42"
+func (cl *ChainLink) Name(name string) *ChainLink {
+	if cl.lastRoute != nil && cl.app != nil {
+		cl.app.nameRoute(cl.lastRoute, name)
+	}
+	return cl
+}
+
+// Summary sets the OpenAPI summary of the route.
+//
+// Example:
+//
+//	app.Get("/users/:id", getUser).
+//	    Summary("Get a user").
+//	    Tags("users")
+func (cl *ChainLink) Summary(summary string) *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Summary = summary
+	}
+	return cl
+}
+
+// Description sets the OpenAPI description of the route (Markdown allowed).
+func (cl *ChainLink) Description(description string) *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Description = description
+	}
+	return cl
+}
+
+// OperationID sets the OpenAPI operationId of the route.
+func (cl *ChainLink) OperationID(id string) *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.OperationID = id
+	}
+	return cl
+}
+
+// Tags adds OpenAPI tags to the route.
+func (cl *ChainLink) Tags(tags ...string) *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Tags = append(cl.lastRoute.Doc.Tags, tags...)
+	}
+	return cl
+}
+
+// Deprecated marks the route as deprecated in the OpenAPI document.
+func (cl *ChainLink) Deprecated() *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Deprecated = true
+	}
+	return cl
+}
+
+// Hidden excludes the route from the OpenAPI document.
+func (cl *ChainLink) Hidden() *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Hidden = true
+	}
+	return cl
+}
+
+// Request documents the request type of the route.
+//
+// Fields tagged `path`, `query` or `header` become parameters; the other
+// fields form the JSON (or multipart) request body. Typed routes registered
+// with PostT/PutT/PatchT document their request type automatically.
+//
+// Example:
+//
+//	app.Post("/users", createUser).
+//	    Request(CreateUserRequest{}).
+//	    Response(201, User{})
+func (cl *ChainLink) Request(v interface{}) *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Request = reflect.TypeOf(v)
+	}
+	return cl
+}
+
+// Response documents a response of the route.
+//
+// v is a sample value of the body type (nil for no body). Data[T] values
+// are documented with the {"data": ..., "meta": ...} envelope.
+//
+// Example:
+//
+//	app.Get("/users/:id", getUser).
+//	    Response(200, User{}).
+//	    Response(404, nil)
+func (cl *ChainLink) Response(status int, v interface{}) *ChainLink {
+	if cl.lastRoute != nil {
+		cl.lastRoute.Doc.Responses = append(cl.lastRoute.Doc.Responses, ResponseDoc{
+			Status:      status,
+			Description: http.StatusText(status),
+			Type:        reflect.TypeOf(v),
+		})
 	}
 	return cl
 }
@@ -150,12 +300,32 @@ type Config struct {
 	ErrorHandler ErrorHandler
 
 	// Context for graceful shutdown
+	// Parent of every request context (see Context.Context): cancelling it
+	// cancels in-flight requests, like App.Shutdown does
 	ShutdownContext context.Context
 
 	// Maximum request body size (default: 10MB)
 	// Uses int to match Shockwave's Config type
+	// Limits the body as received; decoded (e.g. decompressed) bodies are
+	// limited by Context.SetRequestBodyDecoder, defaulting to the same size
 	MaxRequestBodySize int
 
+	// Maximum memory used for multipart form parsing (default: 32MB)
+	// File parts beyond this threshold are spooled to temporary files
+	MaxMultipartMemory int64
+
+	// Secrets for signed and encrypted cookies (default: none)
+	// Each key must be at least 32 random bytes. The first key signs and
+	// encrypts new cookies; all keys are accepted when reading, so prepend
+	// a new key to rotate and drop old keys once their cookies expired
+	CookieKeys [][]byte
+
+	// Proxies allowed to report the client address, scheme and host
+	// (default: none). CIDR prefixes ("10.0.0.0/8") or single addresses.
+	// Forwarded, X-Forwarded-* and X-Real-IP headers are ignored unless the
+	// peer is trusted (see Context.RealIP)
+	TrustedProxies []string
+
 	// Enable request logging (default: false)
 	EnableLogging bool
 
@@ -176,44 +346,50 @@ func DefaultConfig() Config {
 		Addr:               ":8080",
 		ErrorHandler:       DefaultErrorHandler,
 		MaxRequestBodySize: 10 << 20, // 10MB
+		MaxMultipartMemory: 32 << 20, // 32MB
 		EnableLogging:      false,
 		DisableStats:       true,  // Zero-allocation mode by default
 		UseLockFreeRouter:  false, // ✅ RWMutex router (faster for most workloads)
 	}
 }
 
+// ErrorResponse is the JSON error body written by DefaultErrorHandler.
+type ErrorResponse struct {
+	Error  string               `json:"error"`
+	Code   string               `json:"code,omitempty"`   // HTTPError only
+	Detail string               `json:"detail,omitempty"` // HTTPError only
+	Fields []FieldErrorResponse `json:"fields,omitempty"` // Validation errors only
+}
+
+// FieldErrorResponse describes one invalid field in an ErrorResponse.
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 // DefaultErrorHandler is the default error handler.
 //
-// It sends a 500 Internal Server Error for all errors.
-// Override with custom error handler for better error handling.
+// It sends {"error": "<title>"} with the status AsHTTPError maps err to
+// (500 Internal Server Error for unknown errors), listing the failing fields
+// of validation errors. An *HTTPError also sends its code and detail.
+// Use ProblemErrorHandler for RFC 9457 problem details.
 func DefaultErrorHandler(c *Context, err error) {
-	// Map common errors to HTTP status codes
-	status := 500
-	message := "Internal Server Error"
-
-	switch {
-	case errors.Is(err, ErrNotFound):
-		status = 404
-		message = "Not Found"
-	case errors.Is(err, ErrBadRequest):
-		status = 400
-		message = "Bad Request"
-	case errors.Is(err, ErrUnauthorized):
-		status = 401
-		message = "Unauthorized"
-	case errors.Is(err, ErrForbidden):
-		status = 403
-		message = "Forbidden"
-	case errors.Is(err, ErrMethodNotAllowed):
-		status = 405
-		message = "Method Not Allowed"
-	case errors.Is(err, ErrRequestTooLarge):
-		status = 413
-		message = "Request Too Large"
+	// The response already started (e.g. a stream): an error body would corrupt it
+	if c.Written() {
+		return
+	}
+
+	e := AsHTTPError(err)
+	response := ErrorResponse{Error: e.title()}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		response.Code = httpErr.Code
+		response.Detail = httpErr.Detail
+	}
+	if fields, ok := e.Extensions["errors"].([]FieldErrorResponse); ok {
+		response.Fields = fields
 	}
 
-	// Send JSON error response
-	c.JSON(status, map[string]string{
-		"error": message,
-	})
+	setRetryAfter(c, e)
+	c.JSON(e.status(), response)
 }
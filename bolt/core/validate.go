@@ -0,0 +1,339 @@
+package core
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+// Declarative validation driven by the `validate` struct tag.
+//
+// Rules are comma-separated:
+//
+//	required        value must not be the zero value (nil, "", 0, empty slice)
+//	omitempty       skip the other rules when the value is the zero value
+//	min=N           numbers: value >= N; strings: >= N characters; slices/maps: >= N items
+//	max=N           numbers: value <= N; strings: <= N characters; slices/maps: <= N items
+//	len=N           strings: exactly N characters; slices/maps: exactly N items
+//	oneof=a b c     value must be one of the space-separated options
+//	email           string must be a valid email address
+//	regex=PATTERN   string must match PATTERN (must be the last rule: may contain commas)
+//
+// Malformed tags (bad numbers or patterns, unknown rules, email or regex on
+// a non-string field) panic when the type is first bound or validated.
+// Typed routes (PostT, PutT, PatchT) check their request type when they are
+// registered; call CheckTags at startup for other types.
+//
+// Rules apply to zero values too (min=1 rejects 0), except for nil pointers
+// and `omitempty` fields, which are absent rather than invalid.
+// Nested structs (and slices of structs) are validated recursively;
+// their errors are reported as "parent.child" and "items[2].name".
+
+// validationRule is one parsed rule of a `validate` tag.
+type validationRule struct {
+	name    string
+	param   string         // Raw parameter ("3" for min=3)
+	num     float64        // Numeric parameter (min, max, len)
+	re      *regexp.Regexp // Compiled pattern (regex)
+	options []string       // Allowed values (oneof)
+}
+
+// regexCache maps pattern → *regexp.Regexp (patterns are shared across types).
+var regexCache sync.Map
+
+// Validate checks v (a struct or pointer to struct) against its `validate` tags.
+//
+// Returns nil if every rule passes, or a *capacitor.ValidationError listing
+// every failing field. The error wraps ErrBadRequest, so returning it from a
+// handler produces a 400 response.
+//
+// Example:
+//
+//	type CreateUser struct {
+//	    Name  string   `json:"name" validate:"required,min=2,max=64"`
+//	    Email string   `json:"email" validate:"required,email"`
+//	    Role  string   `json:"role" validate:"omitempty,oneof=admin member guest"`
+//	    Tags  []string `json:"tags" validate:"max=10"`
+//	}
+//
+//	if err := bolt.Validate(&req); err != nil {
+//	    return err
+//	}
+func Validate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("bolt: cannot validate nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("bolt: Validate requires a struct, got %T", v)
+	}
+
+	verr := newValidationError(rv.Type())
+	structInfoOf(rv.Type()).validate(rv, "", verr)
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// CheckTags reports the first malformed `validate` tag of T, including its
+// nested structs, or nil if every tag is valid.
+//
+// Example:
+//
+//	func init() {
+//	    if err := bolt.CheckTags[CreateUser](); err != nil {
+//	        panic(err)
+//	    }
+//	}
+func CheckTags[T any]() error {
+	return checkTags(typeOf[T](), make(map[reflect.Type]bool))
+}
+
+// checkTags checks the tags of t and its nested structs; seen stops the
+// walk on recursive types.
+func checkTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	info := loadStructInfo(t)
+	if info.err != nil {
+		return info.err
+	}
+	for i := range info.fields {
+		if nested := info.fields[i].nested; nested != nil {
+			if err := checkTags(nested, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks every field of rv, prefixing error field names with prefix.
+func (info *structInfo) validate(rv reflect.Value, prefix string, verr *capacitor.ValidationError) {
+	for i := range info.fields {
+		f := &info.fields[i]
+		fv := rv.FieldByIndex(f.index)
+		name := prefix + f.display
+
+		if len(f.rules) > 0 {
+			validateField(fv, name, f.rules, verr)
+		}
+		if f.nested != nil {
+			validateNested(fv, name, structInfoOf(f.nested), verr)
+		}
+	}
+}
+
+// validateNested validates a struct, pointer to struct, or slice of structs.
+func validateNested(fv reflect.Value, name string, info *structInfo, verr *capacitor.ValidationError) {
+	switch fv.Kind() {
+	case reflect.Pointer:
+		if !fv.IsNil() {
+			validateNested(fv.Elem(), name, info, verr)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			validateNested(fv.Index(i), name+"["+strconv.Itoa(i)+"]", info, verr)
+		}
+	case reflect.Struct:
+		info.validate(fv, name+".", verr)
+	}
+}
+
+// validateField applies rules to one field value.
+func validateField(fv reflect.Value, name string, rules []validationRule, verr *capacitor.ValidationError) {
+	if fv.IsZero() || (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map) && fv.Len() == 0 {
+		switch {
+		case rules[0].name == "required":
+			verr.AddFieldError(name, "is required")
+			return
+		case rules[0].name == "omitempty", fv.Kind() == reflect.Pointer:
+			// Absent: nothing else to check
+			return
+		}
+	}
+
+	// Rules apply to the pointed-to value
+	for fv.Kind() == reflect.Pointer {
+		fv = fv.Elem()
+	}
+
+	for i := range rules {
+		if msg := rules[i].check(fv); msg != "" {
+			verr.AddFieldError(name, msg)
+			return // One message per field
+		}
+	}
+}
+
+// check returns an error message if fv violates the rule, or "" if it passes.
+func (r *validationRule) check(fv reflect.Value) string {
+	switch r.name {
+	case "required", "omitempty":
+		return "" // Handled by validateField
+
+	case "min":
+		if measure(fv) < r.num {
+			return "must be at least " + r.param + unit(fv)
+		}
+	case "max":
+		if measure(fv) > r.num {
+			return "must be at most " + r.param + unit(fv)
+		}
+	case "len":
+		if measure(fv) != r.num {
+			return "must be exactly " + r.param + unit(fv)
+		}
+
+	case "oneof":
+		s := scalarString(fv)
+		for _, opt := range r.options {
+			if s == opt {
+				return ""
+			}
+		}
+		return "must be one of [" + strings.Join(r.options, " ") + "]"
+
+	case "email":
+		addr, err := mail.ParseAddress(fv.String())
+		if err != nil || addr.Address != fv.String() {
+			return "must be a valid email address"
+		}
+
+	case "regex":
+		if !r.re.MatchString(fv.String()) {
+			return "must match pattern " + r.param
+		}
+	}
+	return ""
+}
+
+// measure returns the value compared by min/max/len:
+// the number itself, or the length of strings (in characters), slices and maps.
+func measure(fv reflect.Value) float64 {
+	switch fv.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(fv.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(fv.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fv.Float()
+	}
+	return 0
+}
+
+// unit returns the unit suffix for min/max/len messages.
+func unit(fv reflect.Value) string {
+	switch fv.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return " items"
+	}
+	return ""
+}
+
+// scalarString formats a scalar value for oneof comparison.
+func scalarString(fv reflect.Value) string {
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10)
+	}
+	return fmt.Sprint(fv.Interface())
+}
+
+// parseRules parses a `validate` tag.
+func parseRules(field reflect.StructField, tag string) ([]validationRule, error) {
+	if tag == "" || tag == "-" {
+		return nil, nil
+	}
+
+	var rules []validationRule
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regex=") {
+			// Pattern runs to the end of the tag (may contain commas)
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		rule := validationRule{name: name, param: param}
+
+		switch name {
+		case "required":
+			// Keep required first so validateField can check it cheaply
+			rules = append([]validationRule{rule}, rules...)
+			continue
+		case "omitempty":
+			// First too, unless required (which wins) is already there
+			if len(rules) > 0 && rules[0].name == "required" {
+				rules = append(rules[:1], append([]validationRule{rule}, rules[1:]...)...)
+			} else {
+				rules = append([]validationRule{rule}, rules...)
+			}
+			continue
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return nil, fmt.Errorf("bolt: field %s: invalid %s=%q", field.Name, name, param)
+			}
+			rule.num = n
+		case "oneof":
+			rule.options = strings.Fields(param)
+		case "email", "regex":
+			// Checked against the string itself, not a formatted value
+			if kind := indirectValueType(field.Type).Kind(); kind != reflect.String {
+				return nil, fmt.Errorf("bolt: field %s: %s requires a string field, got %s", field.Name, name, kind)
+			}
+			if name == "email" {
+				break
+			}
+			re, ok := regexCache.Load(param)
+			if !ok {
+				compiled, err := regexp.Compile(param)
+				if err != nil {
+					return nil, fmt.Errorf("bolt: field %s: invalid regex %q: %v", field.Name, param, err)
+				}
+				re, _ = regexCache.LoadOrStore(param, compiled)
+			}
+			rule.re = re.(*regexp.Regexp)
+		default:
+			return nil, fmt.Errorf("bolt: field %s: unknown validation rule %q", field.Name, name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// indirectValueType strips pointers from t: rules apply to the pointed-to value.
+func indirectValueType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
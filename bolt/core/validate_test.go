@@ -0,0 +1,210 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"omitempty,len=5,regex=^[0-9]+$"`
+}
+
+type validateUser struct {
+	Name     string            `json:"name" validate:"required,min=2,max=8"`
+	Email    string            `json:"email" validate:"omitempty,email"`
+	Role     string            `json:"role" validate:"omitempty,oneof=admin member"`
+	Age      int               `json:"age" validate:"omitempty,min=18,max=130"`
+	Tags     []string          `json:"tags" validate:"max=2"`
+	Score    *float64          `json:"score" validate:"required,max=1.5"`
+	Code     string            `json:"code" validate:"regex=^[a-z]{2,3},[0-9]+$"`
+	Address  validateAddress   `json:"address"`
+	Previous []validateAddress `json:"previous"`
+	internal string
+}
+
+// TestValidate tests each rule and the collected error shape.
+func TestValidate(t *testing.T) {
+	score := 2.0
+	u := validateUser{
+		Name:     "A",
+		Email:    "nope",
+		Role:     "root",
+		Age:      12,
+		Tags:     []string{"a", "b", "c"},
+		Score:    &score,
+		Code:     "abcd,1",
+		Address:  validateAddress{Zip: "12a45"},
+		Previous: []validateAddress{{City: "Oslo", Zip: "0150"}},
+	}
+
+	err := Validate(&u)
+	var verr *capacitor.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *capacitor.ValidationError, got %v", err)
+	}
+	if verr.Type != "validateUser" {
+		t.Errorf("expected type validateUser, got %s", verr.Type)
+	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Error("expected validation error to wrap ErrBadRequest")
+	}
+
+	expected := map[string]string{
+		"name":            "must be at least 2 characters",
+		"email":           "must be a valid email address",
+		"role":            "must be one of [admin member]",
+		"age":             "must be at least 18",
+		"tags":            "must be at most 2 items",
+		"score":           "must be at most 1.5",
+		"code":            "must match pattern ^[a-z]{2,3},[0-9]+$",
+		"address.city":    "is required",
+		"address.zip":     "must match pattern ^[0-9]+$",
+		"previous[0].zip": "must be exactly 5 characters",
+	}
+	got := make(map[string]string, len(verr.Errors))
+	for _, fe := range verr.Errors {
+		got[fe.Field] = fe.Message
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected %d errors, got %d: %v", len(expected), len(got), got)
+	}
+	for field, msg := range expected {
+		if got[field] != msg {
+			t.Errorf("field %s: expected %q, got %q", field, msg, got[field])
+		}
+	}
+}
+
+// TestValidatePasses tests a valid struct and optional empty fields.
+func TestValidatePasses(t *testing.T) {
+	score := 1.0
+	u := validateUser{
+		Name:    "Ada",
+		Score:   &score,
+		Code:    "ab,12",
+		Address: validateAddress{City: "Oslo"},
+	}
+	if err := Validate(u); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+// TestValidateZeroValues tests that rules apply to zero values unless the
+// field is a nil pointer or omitempty.
+func TestValidateZeroValues(t *testing.T) {
+	type request struct {
+		Count    int      `json:"count" validate:"min=1"`
+		Role     string   `json:"role" validate:"oneof=admin member"`
+		Items    []string `json:"items" validate:"min=1"`
+		Limit    *int     `json:"limit" validate:"min=1"`
+		Page     int      `json:"page" validate:"omitempty,min=1"`
+		Priority int      `json:"priority" validate:"required,omitempty,min=1"`
+	}
+
+	zero := 0
+	err := Validate(request{Limit: &zero})
+	var verr *capacitor.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *capacitor.ValidationError, got %v", err)
+	}
+
+	expected := map[string]string{
+		"count":    "must be at least 1",
+		"role":     "must be one of [admin member]",
+		"items":    "must be at least 1 items",
+		"limit":    "must be at least 1",
+		"priority": "is required",
+	}
+	got := make(map[string]string, len(verr.Errors))
+	for _, fe := range verr.Errors {
+		got[fe.Field] = fe.Message
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected %d errors, got %d: %v", len(expected), len(got), got)
+	}
+	for field, msg := range expected {
+		if got[field] != msg {
+			t.Errorf("field %s: expected %q, got %q", field, msg, got[field])
+		}
+	}
+}
+
+// TestValidateInvalidTag tests that malformed rules panic.
+func TestValidateInvalidTag(t *testing.T) {
+	type bad struct {
+		N int `validate:"min=abc"`
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid rule")
+		}
+	}()
+	_ = Validate(bad{})
+}
+
+// TestCheckTags tests reporting malformed rules, including nested structs
+// and string-only rules on other kinds.
+func TestCheckTags(t *testing.T) {
+	type address struct {
+		Zip string `validate:"regex=["`
+	}
+	type nested struct {
+		Home *address
+	}
+	type intEmail struct {
+		N int `validate:"email"`
+	}
+	type sliceRegex struct {
+		Tags []string `validate:"regex=^a$"`
+	}
+	type unknown struct {
+		S string `validate:"uuid"`
+	}
+	type valid struct {
+		Email *string `validate:"email"`
+		Code  string  `validate:"regex=^[A-Z]{3}$"`
+		Home  *validateAddress
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nested regex", CheckTags[nested](), `field Zip: invalid regex "["`},
+		{"email on int", CheckTags[intEmail](), "field N: email requires a string field, got int"},
+		{"regex on slice", CheckTags[[]sliceRegex](), "field Tags: regex requires a string field, got slice"},
+		{"unknown rule", CheckTags[*unknown](), `field S: unknown validation rule "uuid"`},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.want, tt.err)
+		}
+	}
+
+	if err := CheckTags[valid](); err != nil {
+		t.Errorf("expected valid tags, got %v", err)
+	}
+	if err := CheckTags[int](); err != nil {
+		t.Errorf("expected nil for non-struct types, got %v", err)
+	}
+}
+
+// TestCheckTagsTypedRoute tests that typed routes reject malformed tags at
+// registration.
+func TestCheckTagsTypedRoute(t *testing.T) {
+	type bad struct {
+		Age int `json:"age" validate:"regex=^[0-9]+$"`
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "regex requires a string field") {
+			t.Errorf("expected registration panic, got %v", r)
+		}
+	}()
+	PostT(New(), "/", func(c *Context, req bad) Data[bad] { return OK(req) })
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/yourusername/shockwave/pkg/shockwave/websocket"
+)
+
+// WebSocket routes.
+//
+// The upgrade runs on the native Shockwave connection: the HTTP/1.1
+// connection is hijacked for the lifetime of the handler and closed by the
+// server when the handler returns. WebSocket routes are ordinary GET routes,
+// so route parameters, global/group middleware and ChainLink options apply.
+
+// WebSocketHandler handles an upgraded WebSocket connection.
+//
+// The connection is closed when the handler returns. Returning io.EOF
+// (what ReadMessage returns once the client closes) is not an error.
+type WebSocketHandler func(c *Context, ws *websocket.Conn) error
+
+// WebSocketConfig defines the config for WebSocket routes.
+type WebSocketConfig struct {
+	// CheckOrigin returns true if the request Origin is acceptable.
+	// Rejected requests get 403 Forbidden before the upgrade.
+	// Default: nil (same origin only; requests without Origin are allowed)
+	CheckOrigin func(c *Context) bool
+
+	// Subprotocols lists supported subprotocols in order of preference.
+	// Default: nil
+	Subprotocols []string
+
+	// ReadBufferSize and WriteBufferSize set the connection buffer sizes.
+	// Default: 4096
+	ReadBufferSize  int
+	WriteBufferSize int
+
+	// MaxMessageSize limits incoming messages (bytes). Zero keeps the
+	// websocket package default (32MB).
+	// Default: 0
+	MaxMessageSize int64
+}
+
+// DefaultWebSocketConfig returns the default WebSocket configuration.
+func DefaultWebSocketConfig() WebSocketConfig {
+	return WebSocketConfig{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+	}
+}
+
+// WebSocket registers a WebSocket route using DefaultWebSocketConfig.
+//
+// Example:
+//
+//	app.WebSocket("/ws/:room", func(c *bolt.Context, ws *websocket.Conn) error {
+//	    room := hub.Join(c.Param("room"), ws)
+//	    defer room.Leave(ws)
+//
+//	    for {
+//	        _, msg, err := ws.ReadMessage()
+//	        if err != nil {
+//	            return err
+//	        }
+//	        room.Broadcast(msg)
+//	    }
+//	}).Use(AuthMiddleware())
+func (app *App) WebSocket(path string, handler WebSocketHandler) *ChainLink {
+	return app.WebSocketWithConfig(path, DefaultWebSocketConfig(), handler)
+}
+
+// WebSocketWithConfig registers a WebSocket route with custom config.
+//
+// Example:
+//
+//	app.WebSocketWithConfig("/ws", bolt.WebSocketConfig{
+//	    CheckOrigin:  func(c *bolt.Context) bool { return c.GetHeader("Origin") == "https://app.example.com" },
+//	    Subprotocols: []string{"chat.v2"},
+//	}, chatHandler)
+func (app *App) WebSocketWithConfig(path string, config WebSocketConfig, handler WebSocketHandler) *ChainLink {
+	return app.addRoute(MethodGet, path, webSocketHandler(config, handler)).Hidden()
+}
+
+// WebSocket registers a WebSocket route in the group.
+func (g *Group) WebSocket(path string, handler WebSocketHandler) *ChainLink {
+	return g.WebSocketWithConfig(path, DefaultWebSocketConfig(), handler)
+}
+
+// WebSocketWithConfig registers a WebSocket route in the group with custom config.
+func (g *Group) WebSocketWithConfig(path string, config WebSocketConfig, handler WebSocketHandler) *ChainLink {
+	return g.app.addGroupRoute(g, MethodGet, joinPaths(g.prefix, path), webSocketHandler(config, handler)).Hidden()
+}
+
+// webSocketHandler returns the route handler performing the upgrade.
+func webSocketHandler(config WebSocketConfig, handler WebSocketHandler) Handler {
+	upgrader := &websocket.Upgrader{
+		Subprotocols:    config.Subprotocols,
+		ReadBufferSize:  config.ReadBufferSize,
+		WriteBufferSize: config.WriteBufferSize,
+	}
+	checkOrigin := config.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+
+	return func(c *Context) error {
+		if !checkOrigin(c) {
+			return fmt.Errorf("%w: origin not allowed", ErrForbidden)
+		}
+
+		ws, err := c.upgradeWebSocket(upgrader)
+		if err != nil {
+			return err
+		}
+		defer ws.Close()
+
+		if config.MaxMessageSize > 0 {
+			ws.SetMaxMessageSize(config.MaxMessageSize)
+		}
+
+		// Tell the client when the server shuts down
+		stop := context.AfterFunc(c.Context(), func() {
+			ws.CloseWithCode(websocket.CloseGoingAway, "server shutting down")
+		})
+		defer stop()
+
+		if err := handler(c, ws); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	}
+}
+
+// upgradeWebSocket performs the opening handshake on the underlying connection.
+//
+// On failure the upgrader has already written an error response.
+func (c *Context) upgradeWebSocket(upgrader *websocket.Upgrader) (*websocket.Conn, error) {
+	var r *http.Request
+	switch {
+	case c.httpReq != nil:
+		r = c.httpReq
+	case c.shockwaveReq != nil:
+		r = c.upgradeRequest()
+	default:
+		return nil, fmt.Errorf("%w: websocket upgrade needs a connection", ErrBadRequest)
+	}
+
+	ws, err := upgrader.Upgrade(&upgradeResponseWriter{c: c}, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+
+	c.statusCode = http.StatusSwitchingProtocols
+	c.written = true
+	return ws, nil
+}
+
+// upgradeRequest builds the net/http view of a Shockwave request needed by
+// the upgrader (method and headers).
+func (c *Context) upgradeRequest() *http.Request {
+	header := make(http.Header, c.shockwaveReq.Header.Len())
+	c.shockwaveReq.Header.VisitAll(func(name, value []byte) bool {
+		header.Add(string(name), string(value))
+		return true
+	})
+	return &http.Request{
+		Method: c.Method(),
+		URL:    &url.URL{Path: c.Path(), RawQuery: string(c.QueryBytes())},
+		Header: header,
+		Host:   header.Get("Host"),
+	}
+}
+
+// sameOrigin reports whether the Origin header is absent or matches Host.
+func sameOrigin(c *Context) bool {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := c.GetHeader("Host")
+	if host == "" && c.httpReq != nil {
+		host = c.httpReq.Host
+	}
+	return strings.EqualFold(u.Host, host)
+}
+
+// upgradeResponseWriter adapts the Context response to the net/http
+// interfaces (http.ResponseWriter, http.Hijacker) used by websocket.Upgrader.
+type upgradeResponseWriter struct {
+	c      *Context
+	header http.Header
+}
+
+// Header returns the response headers.
+func (w *upgradeResponseWriter) Header() http.Header {
+	if w.c.httpRes != nil {
+		return w.c.httpRes.Header()
+	}
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+// WriteHeader sends an error status (successful upgrades are hijacked).
+func (w *upgradeResponseWriter) WriteHeader(status int) {
+	c := w.c
+	c.statusCode = status
+	c.written = true
+
+	if c.httpRes != nil {
+		c.httpRes.WriteHeader(status)
+		return
+	}
+	for key, values := range w.header {
+		for _, value := range values {
+			_ = c.shockwaveRes.Header().Add([]byte(key), []byte(value))
+		}
+	}
+	c.shockwaveRes.WriteHeader(status)
+}
+
+// Write writes the error response body.
+func (w *upgradeResponseWriter) Write(p []byte) (int, error) {
+	if !w.c.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.c.httpRes != nil {
+		return w.c.httpRes.Write(p)
+	}
+	return w.c.shockwaveRes.Write(p)
+}
+
+// Hijack takes over the connection.
+func (w *upgradeResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.c.httpRes != nil {
+		hijacker, ok := w.c.httpRes.(http.Hijacker)
+		if !ok {
+			return nil, nil, errors.New("bolt: response writer doesn't support hijacking")
+		}
+		return hijacker.Hijack()
+	}
+	return w.c.shockwaveRes.Hijack()
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/shockwave/pkg/shockwave/websocket"
+)
+
+// registerEchoSocket registers an authenticated echo WebSocket route.
+func registerEchoSocket(app *App, closed chan<- error) {
+	auth := func(next Handler) Handler {
+		return func(c *Context) error {
+			if c.GetHeader("Authorization") != "Bearer token" {
+				return ErrUnauthorized
+			}
+			c.Set("user", "ada")
+			return next(c)
+		}
+	}
+
+	app.WebSocket("/ws/:room", func(c *Context, ws *websocket.Conn) error {
+		greeting := "joined " + c.Param("room") + " as " + c.Get("user").(string)
+		if err := ws.WriteMessage(websocket.TextMessage, []byte(greeting)); err != nil {
+			return err
+		}
+		for {
+			mt, msg, err := ws.ReadMessage()
+			if err != nil {
+				closed <- err
+				return err
+			}
+			if err := ws.WriteMessage(mt, msg); err != nil {
+				return err
+			}
+		}
+	}).Use(auth).Name("chat")
+}
+
+// testWebSocket runs the echo conversation against a server address.
+func testWebSocket(t *testing.T, addr string, closed <-chan error) {
+	t.Helper()
+
+	header := http.Header{"Authorization": {"Bearer token"}}
+	ws, err := websocket.Dial("ws://"+addr+"/ws/general", header)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+
+	_, msg, err := ws.ReadMessage()
+	if err != nil || string(msg) != "joined general as ada" {
+		t.Fatalf("unexpected greeting %q (%v)", msg, err)
+	}
+	for _, text := range []string{"hello", strings.Repeat("x", 10000)} {
+		if err := ws.WriteMessage(websocket.TextMessage, []byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		_, msg, err := ws.ReadMessage()
+		if err != nil || string(msg) != text {
+			t.Fatalf("unexpected echo of %d bytes: %d bytes (%v)", len(text), len(msg), err)
+		}
+	}
+
+	// Closing the client ends the handler
+	ws.Close()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler didn't return after client close")
+	}
+
+	// Middleware runs before the upgrade
+	if _, err := websocket.Dial("ws://"+addr+"/ws/general", nil); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 without credentials, got %v", err)
+	}
+	header.Set("Origin", "http://evil.example")
+	if _, err := websocket.Dial("ws://"+addr+"/ws/general", header); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected 403 for cross-origin request, got %v", err)
+	}
+}
+
+// TestWebSocketShockwave tests the upgrade on the native Shockwave connection.
+func TestWebSocketShockwave(t *testing.T) {
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+
+	closed := make(chan error, 1)
+	registerEchoSocket(ts.app, closed)
+	testWebSocket(t, ts.addr, closed)
+
+	// The server keeps serving plain HTTP requests
+	ts.app.Get("/health", func(c *Context) error { return c.JSON(200, map[string]string{"ok": "true"}) })
+	if resp := ts.Get("/health"); resp.statusCode != 200 {
+		t.Errorf("expected 200 after websocket session, got %d (%v)", resp.statusCode, resp.err)
+	}
+}
+
+// TestWebSocketHTTP tests the upgrade through net/http (ServeHTTP).
+func TestWebSocketHTTP(t *testing.T) {
+	app := New()
+	closed := make(chan error, 1)
+	registerEchoSocket(app, closed)
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	testWebSocket(t, strings.TrimPrefix(server.URL, "http://"), closed)
+}
+
+// TestWebSocketRoute tests route metadata and plain HTTP requests to WebSocket routes.
+func TestWebSocketRoute(t *testing.T) {
+	app := New()
+	app.WebSocket("/ws", func(c *Context, ws *websocket.Conn) error { return nil })
+
+	routes := app.Routes()
+	if len(routes) != 1 || routes[0].Method != MethodGet || !routes[0].Doc.Hidden {
+		t.Errorf("unexpected routes %+v", routes)
+	}
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/ws", nil))
+	if w.Code != 400 {
+		t.Errorf("expected 400 for non-upgrade request, got %d", w.Code)
+	}
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// upperWrapper uppercases bodies, appends "!" on Close and records sizes.
+type upperWrapper struct {
+	mu    sync.Mutex
+	sizes []int64
+}
+
+func (u *upperWrapper) WrapBody(c *Context, status int, w io.Writer, size int64) io.WriteCloser {
+	u.mu.Lock()
+	u.sizes = append(u.sizes, size)
+	u.mu.Unlock()
+	if c.GetResponseHeader("X-Skip") != "" {
+		return nil
+	}
+	c.SetHeader("X-Wrapped", "true")
+	return upperWriter{w}
+}
+
+type upperWriter struct{ w io.Writer }
+
+func (u upperWriter) Write(p []byte) (int, error) { return u.w.Write(bytes.ToUpper(p)) }
+func (u upperWriter) Close() error {
+	_, err := u.w.Write([]byte("!"))
+	return err
+}
+
+// registerWrapRoutes registers routes covering complete and streamed bodies.
+func registerWrapRoutes(app *App, wrapper BodyWrapper, dir string) {
+	app.Use(func(next Handler) Handler {
+		return func(c *Context) error {
+			c.SetBodyWrapper(wrapper)
+			return next(c)
+		}
+	})
+	app.Get("/blob", func(c *Context) error {
+		return c.Blob(200, "text/plain", []byte("hello"))
+	})
+	app.Get("/skip", func(c *Context) error {
+		c.SetHeader("X-Skip", "1")
+		return c.Blob(200, "text/plain", []byte("hello"))
+	})
+	app.Get("/events", func(c *Context) error {
+		return c.SSE(func(stream *SSEStream) error {
+			return stream.Send("", "", "a")
+		})
+	})
+	app.Static("/files", dir)
+}
+
+// TestBodyWrapper tests body wrappers over net/http and Shockwave.
+func TestBodyWrapper(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		body    string
+		size    int64
+		wrapped bool
+	}{
+		{"/blob", "HELLO!", 5, true},
+		{"/skip", "hello", 5, false},
+		{"/events", "DATA: A\n\n!", -1, true},
+		{"/files/f.txt", "FILE!", 4, true},
+	}
+
+	check := func(t *testing.T, wrapper *upperWrapper, path string, status int, header http.Header, body string) {
+		t.Helper()
+		for _, tt := range tests {
+			if tt.path != path {
+				continue
+			}
+			if status != 200 || body != tt.body || (header.Get("X-Wrapped") != "") != tt.wrapped {
+				t.Errorf("%s: unexpected response %d %q (wrapped %q)", path, status, body, header.Get("X-Wrapped"))
+			}
+			if cl := header.Get("Content-Length"); cl != "" && cl != strconv.Itoa(len(body)) {
+				t.Errorf("%s: Content-Length %s for a %d byte body", path, cl, len(body))
+			}
+			wrapper.mu.Lock()
+			if n := len(wrapper.sizes); n == 0 || wrapper.sizes[n-1] != tt.size {
+				t.Errorf("%s: expected size %d, got %v", path, tt.size, wrapper.sizes)
+			}
+			wrapper.mu.Unlock()
+		}
+	}
+
+	t.Run("http", func(t *testing.T) {
+		app := New()
+		wrapper := &upperWrapper{}
+		registerWrapRoutes(app, wrapper, dir)
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			check(t, wrapper, tt.path, w.Code, w.Header(), w.Body.String())
+		}
+	})
+
+	t.Run("shockwave", func(t *testing.T) {
+		ts := createTestServer(t)
+		defer ts.Shutdown()
+		wrapper := &upperWrapper{}
+		registerWrapRoutes(ts.app, wrapper, dir)
+
+		// Keep-alive: wrapped responses must be framed (Content-Length or chunked)
+		client := &http.Client{Timeout: 5 * time.Second}
+		defer client.CloseIdleConnections()
+		for _, tt := range tests {
+			resp, err := client.Get(ts.url + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			check(t, wrapper, tt.path, resp.StatusCode, resp.Header, string(body))
+		}
+	})
+}
+
+// TestShockwaveResponseFraming tests that Shockwave responses carry a
+// Content-Length, so pipelined keep-alive requests can be read back.
+func TestShockwaveResponseFraming(t *testing.T) {
+	ts := createTestServer(t)
+	defer ts.Shutdown()
+
+	text := func(c *Context) error { return c.Text(200, "hello") }
+	ts.app.Get("/text", text)
+	ts.app.Head("/text", text)
+	ts.app.Get("/json", func(c *Context) error { return c.JSON(201, map[string]int{"id": 1}) })
+	ts.app.Get("/ok", func(c *Context) error { return c.JSONOK() })
+	ts.app.Get("/empty", func(c *Context) error { return nil })
+	ts.app.Get("/none", func(c *Context) error { return c.NoContent() })
+
+	conn, err := net.Dial("tcp", ts.addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	tests := []struct {
+		method, path string
+		status       int
+		length       string
+		body         string
+	}{
+		{"GET", "/text", 200, "5", "hello"},
+		{"HEAD", "/text", 200, "5", ""},
+		{"GET", "/json", 201, "9", "{\"id\":1}\n"},
+		{"GET", "/ok", 200, strconv.Itoa(len(jsonOKBytes)), string(jsonOKBytes)},
+		{"GET", "/empty", 200, "0", ""},
+		{"GET", "/none", 204, "", ""},
+	}
+
+	// Pipelined: each response must end where the next one starts
+	var raw bytes.Buffer
+	for _, tt := range tests {
+		raw.WriteString(tt.method + " " + tt.path + " HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	}
+	if _, err := conn.Write(raw.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bufio.NewReader(conn)
+	for _, tt := range tests {
+		resp, err := http.ReadResponse(reader, &http.Request{Method: tt.method})
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		if resp.StatusCode != tt.status || resp.Header.Get("Content-Length") != tt.length || string(body) != tt.body {
+			t.Errorf("%s %s: got %d, Content-Length %q, body %q", tt.method, tt.path,
+				resp.StatusCode, resp.Header.Get("Content-Length"), body)
+		}
+	}
+}
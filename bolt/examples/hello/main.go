@@ -6,55 +6,6 @@ import (
 	"github.com/yourusername/bolt/core"
 )
 
-// sendData is a helper function to send Data[T] responses.
-// This is needed because Go doesn't support type parameters on methods.
-func sendData[T any](c *core.Context, data core.Data[T]) error {
-	// Set status code (default 200)
-	if data.Status == 0 {
-		data.Status = 200
-	}
-
-	// Handle error case
-	if data.Error != nil {
-		return sendErrorData(c, data)
-	}
-
-	// Set custom headers
-	for key, value := range data.Headers {
-		c.SetHeader(key, value)
-	}
-
-	// Build response
-	response := make(map[string]interface{}, 2)
-	response["data"] = data.Value
-
-	// Include metadata if present
-	if len(data.Metadata) > 0 {
-		response["meta"] = data.Metadata
-	}
-
-	return c.JSON(data.Status, response)
-}
-
-// sendErrorData sends an error response.
-func sendErrorData[T any](c *core.Context, data core.Data[T]) error {
-	// Set custom headers (even for errors)
-	for key, value := range data.Headers {
-		c.SetHeader(key, value)
-	}
-
-	// Build error response
-	response := make(map[string]interface{}, 2)
-	response["error"] = data.Error.Error()
-
-	// Include metadata if present
-	if len(data.Metadata) > 0 {
-		response["meta"] = data.Metadata
-	}
-
-	return c.JSON(data.Status, response)
-}
-
 // User represents a user in the system.
 type User struct {
 	ID    int    `json:"id"`
@@ -74,14 +25,13 @@ func main() {
 		})
 	})
 
-	// Data[T] API: Type-safe response using helper function
-	app.Get("/users/:id", func(c *core.Context) error {
+	// Data[T] API: Type-safe response
+	core.GetT(app, "/users/:id", func(c *core.Context) core.Data[User] {
 		id := c.Param("id")
 
 		// Simulate database lookup
 		if id == "" {
-			data := core.BadRequest[User](core.ErrBadRequest)
-			return sendData(c, data)
+			return core.BadRequest[User](core.ErrBadRequest)
 		}
 
 		user := User{
@@ -90,33 +40,20 @@ func main() {
 			Email: "alice@example.com",
 		}
 
-		data := core.OK(user).
+		return core.OK(user).
 			WithMeta("cached", true).
 			WithMeta("ttl", 3600).
 			WithHeader("X-Cache-Hit", "true")
-
-		return sendData(c, data)
 	})
 
 	// Data[T] API: Type-safe request and response
+	// The request is decoded and validated before the handler runs
 	type CreateUserRequest struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required,email"`
 	}
 
-	app.Post("/users", func(c *core.Context) error {
-		var req CreateUserRequest
-		if err := c.BindJSON(&req); err != nil {
-			data := core.BadRequest[User](core.ErrBadRequest)
-			return sendData(c, data)
-		}
-
-		// Validate
-		if req.Name == "" || req.Email == "" {
-			data := core.BadRequest[User](core.ErrBadRequest)
-			return sendData(c, data)
-		}
-
+	core.PostT(app, "/users", func(c *core.Context, req CreateUserRequest) core.Data[User] {
 		// Create user
 		user := User{
 			ID:    456,
@@ -124,10 +61,8 @@ func main() {
 			Email: req.Email,
 		}
 
-		data := core.Created(user).
+		return core.Created(user).
 			WithMeta("created_at", "2025-11-13T00:00:00Z")
-
-		return sendData(c, data)
 	})
 
 	// Health check endpoint
@@ -0,0 +1,340 @@
+package middleware
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/yourusername/bolt/core"
+)
+
+// CompressLevel trades compression ratio for CPU time.
+type CompressLevel int
+
+const (
+	// CompressDefault balances speed and ratio (gzip 6, brotli 4, zstd default).
+	CompressDefault CompressLevel = iota
+	// CompressFastest favors speed (gzip 1, brotli 1, zstd fastest).
+	CompressFastest
+	// CompressBest favors ratio (gzip 9, brotli 11, zstd best).
+	// Brotli 11 is slow; prefer precompressed files for static assets.
+	CompressBest
+)
+
+// Compress returns a middleware that compresses response bodies with
+// gzip, brotli or zstd, negotiated with the Accept-Encoding header.
+//
+// Responses are compressed when:
+//   - the client accepts one of config.Encodings (q-values are honored)
+//   - the Content-Type matches config.ContentTypes
+//   - the body is at least config.MinLength bytes (streams always qualify)
+//   - no Content-Encoding is set yet (e.g. precompressed static files)
+//     and Cache-Control doesn't contain no-transform
+//
+// Compressible responses get "Vary: Accept-Encoding". Streaming responses
+// (SSE, Static files) are compressed as they are written and flushed after
+// every event. Encoders are pooled per encoding. Zero fields take their
+// defaults (see DefaultCompressConfig).
+//
+// Example:
+//
+//	app.Use(middleware.Compress(middleware.CompressConfig{
+//	    Level:     middleware.CompressFastest,
+//	    MinLength: 512,
+//	    Encodings: []string{"br", "gzip"},
+//	    SkipPaths: []string{"/metrics"},
+//	}))
+func Compress(config CompressConfig) core.Middleware {
+	// Apply defaults
+	if len(config.Encodings) == 0 {
+		config.Encodings = []string{"zstd", "br", "gzip"}
+	}
+	if config.MinLength == 0 {
+		config.MinLength = 1024
+	}
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = defaultCompressTypes
+	}
+	if config.Level < CompressDefault || config.Level > CompressBest {
+		config.Level = CompressDefault
+	}
+
+	comp := &compressor{
+		minLength:    int64(config.MinLength),
+		contentTypes: make([]string, len(config.ContentTypes)),
+		encodings:    make([]*compressEncoding, len(config.Encodings)),
+	}
+	for i, ct := range config.ContentTypes {
+		comp.contentTypes[i] = strings.ToLower(ct)
+	}
+	for i, name := range config.Encodings {
+		comp.encodings[i] = newCompressEncoding(name, config.Level)
+	}
+
+	// Create skip map for O(1) lookup
+	skipMap := make(map[string]bool, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skipMap[path] = true
+	}
+
+	return func(next core.Handler) core.Handler {
+		return func(c *core.Context) error {
+			if skipMap[c.Path()] {
+				return next(c)
+			}
+
+			// Negotiation is deferred to the first body write, when the
+			// status, Content-Type and size are known
+			c.SetBodyWrapper(comp)
+			return next(c)
+		}
+	}
+}
+
+// CompressConfig defines configuration for compression middleware.
+type CompressConfig struct {
+	// Level is the compression level.
+	// Default: CompressDefault
+	Level CompressLevel
+
+	// Encodings are the supported encodings in server preference order,
+	// used when the client accepts several equally.
+	// Supported: "zstd", "br", "gzip"
+	// Default: ["zstd", "br", "gzip"]
+	Encodings []string
+
+	// MinLength is the minimum body size to compress. Smaller bodies grow
+	// or barely shrink, and aren't worth the CPU.
+	// Default: 1024
+	MinLength int
+
+	// ContentTypes lists compressible media types. Entries ending in "/"
+	// match a whole type ("text/"), entries starting with "+" a structured
+	// syntax suffix ("+json").
+	// Default: text/*, JSON, XML, JavaScript, SVG, WebAssembly and
+	// event streams
+	ContentTypes []string
+
+	// SkipPaths are paths to never compress.
+	SkipPaths []string
+}
+
+// DefaultCompressConfig returns default compression configuration.
+func DefaultCompressConfig() CompressConfig {
+	return CompressConfig{
+		Level:        CompressDefault,
+		Encodings:    []string{"zstd", "br", "gzip"},
+		MinLength:    1024,
+		ContentTypes: defaultCompressTypes,
+	}
+}
+
+// defaultCompressTypes are the media types compressed by default.
+var defaultCompressTypes = []string{
+	"text/",
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-javascript",
+	"application/wasm",
+	"application/x-ndjson",
+	"image/svg+xml",
+	"+json",
+	"+xml",
+}
+
+// compressor is the core.BodyWrapper installed by Compress.
+// It is shared by all requests.
+type compressor struct {
+	minLength    int64
+	contentTypes []string
+	encodings    []*compressEncoding // Server preference order
+}
+
+// WrapBody implements core.BodyWrapper.
+func (comp *compressor) WrapBody(c *core.Context, status int, w io.Writer, size int64) io.WriteCloser {
+	if status < 200 || status == 204 || status == 206 || status == 304 {
+		return nil
+	}
+	if size >= 0 && size < comp.minLength {
+		return nil
+	}
+	if ce := c.GetResponseHeader("Content-Encoding"); ce != "" && !strings.EqualFold(ce, "identity") {
+		return nil // Already encoded
+	}
+	if !comp.compressible(c.GetResponseHeader("Content-Type")) {
+		return nil
+	}
+	if strings.Contains(strings.ToLower(c.GetResponseHeader("Cache-Control")), "no-transform") {
+		return nil
+	}
+
+	// The response now depends on Accept-Encoding, compressed or not
+	c.Vary("Accept-Encoding")
+
+	enc := comp.negotiate(c.GetHeader("Accept-Encoding"))
+	if enc == nil {
+		return nil
+	}
+
+	c.SetHeader("Content-Encoding", enc.name)
+	if etag := c.GetResponseHeader("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		// Compressed bytes differ from the identity representation
+		c.SetHeader("ETag", "W/"+etag)
+	}
+	return enc.acquire(w)
+}
+
+// compressible reports whether contentType is in the allowlist.
+func (comp *compressor) compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" {
+		return false
+	}
+	for _, ct := range comp.contentTypes {
+		switch {
+		case strings.HasSuffix(ct, "/"):
+			if strings.HasPrefix(mediaType, ct) {
+				return true
+			}
+		case strings.HasPrefix(ct, "+"):
+			if strings.HasSuffix(mediaType, ct) {
+				return true
+			}
+		case mediaType == ct:
+			return true
+		}
+	}
+	return false
+}
+
+// negotiate returns the preferred encoding accepted by the client
+// (RFC 9110 §12.5.3), or nil for identity.
+func (comp *compressor) negotiate(acceptEncoding string) *compressEncoding {
+	var best *compressEncoding
+	bestQ := 0
+	for _, enc := range comp.encodings {
+		if q := acceptQuality(acceptEncoding, enc.name); q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the quality (0-1000) of coding in an
+// Accept-Encoding header. An explicit entry wins over "*".
+func acceptQuality(header, coding string) int {
+	wildcard := 0
+	for part := range strings.SplitSeq(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+
+		q := 1000
+		for param := range strings.SplitSeq(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(key), "q") {
+				q = core.ParseQuality(strings.TrimSpace(value))
+			}
+		}
+
+		switch {
+		case strings.EqualFold(name, coding):
+			return q
+		case name == "*":
+			wildcard = q
+		}
+	}
+	return wildcard
+}
+
+// compressEncoding is a content coding with a pool of encoders.
+type compressEncoding struct {
+	name string
+	pool sync.Pool // *compressWriter
+}
+
+// streamEncoder is implemented by the gzip, brotli and zstd writers.
+type streamEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// newCompressEncoding returns the pooled encoding for name.
+// Panics for unknown encodings (configuration error).
+func newCompressEncoding(name string, level CompressLevel) *compressEncoding {
+	enc := &compressEncoding{name: name}
+
+	var newEncoder func() streamEncoder
+	switch name {
+	case "gzip":
+		gzipLevel := [...]int{CompressDefault: 6, CompressFastest: 1, CompressBest: 9}[level]
+		newEncoder = func() streamEncoder {
+			w, _ := gzip.NewWriterLevel(io.Discard, gzipLevel)
+			return w
+		}
+	case "br":
+		brotliLevel := [...]int{CompressDefault: 4, CompressFastest: 1, CompressBest: 11}[level]
+		newEncoder = func() streamEncoder {
+			return brotli.NewWriterLevel(io.Discard, brotliLevel)
+		}
+	case "zstd":
+		zstdLevel := [...]zstd.EncoderLevel{
+			CompressDefault: zstd.SpeedDefault,
+			CompressFastest: zstd.SpeedFastest,
+			CompressBest:    zstd.SpeedBestCompression,
+		}[level]
+		newEncoder = func() streamEncoder {
+			w, _ := zstd.NewWriter(io.Discard,
+				zstd.WithEncoderLevel(zstdLevel),
+				zstd.WithEncoderConcurrency(1), // One goroutine per response
+				zstd.WithWindowSize(1<<20),     // Browsers accept up to 8MB (RFC 9659)
+				zstd.WithLowerEncoderMem(true),
+			)
+			return w
+		}
+	default:
+		panic("middleware: unsupported compression encoding " + strconv.Quote(name))
+	}
+
+	enc.pool.New = func() any {
+		return &compressWriter{enc: newEncoder(), pool: &enc.pool}
+	}
+	return enc
+}
+
+// acquire returns a pooled encoder writing to w.
+func (e *compressEncoding) acquire(w io.Writer) *compressWriter {
+	cw := e.pool.Get().(*compressWriter)
+	cw.enc.Reset(w)
+	return cw
+}
+
+// compressWriter is a pooled encoder. Close finishes the stream and
+// returns it to the pool.
+type compressWriter struct {
+	enc  streamEncoder
+	pool *sync.Pool
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	return w.enc.Write(p)
+}
+
+// Flush flushes buffered data so streamed events reach the client.
+func (w *compressWriter) Flush() error {
+	return w.enc.Flush()
+}
+
+// Close writes the end of the stream and releases the encoder.
+func (w *compressWriter) Close() error {
+	err := w.enc.Close()
+	w.enc.Reset(io.Discard) // Drop the response writer reference
+	w.pool.Put(w)
+	return err
+}
@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/yourusername/bolt/core"
+)
+
+var compressBody = strings.Repeat(`{"name":"bolt","fast":true}`, 100)
+
+// decompress decodes a response body by its Content-Encoding.
+func decompress(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case "":
+		r = body
+	case "gzip":
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case "br":
+		r = brotli.NewReader(body)
+	case "zstd":
+		zr, err := zstd.NewReader(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", encoding)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", encoding, err)
+	}
+	return string(data)
+}
+
+// TestCompress tests Accept-Encoding negotiation.
+func TestCompress(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"gzip", "gzip"},
+		{"br", "br"},
+		{"zstd", "zstd"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"gzip;q=0.5, br", "br"},
+		{"GZIP", "gzip"},
+		{"*", "zstd"},
+		{"gzip, *;q=0", "gzip"},
+		{"zstd;q=0, *", "br"},
+		{"br;q=1.0, gzip;q=1.0", "br"},
+		{"identity", ""},
+		{"", ""},
+		{"gzip;q=0", ""},
+	}
+
+	app := core.New()
+	app.Use(Compress(CompressConfig{}))
+	app.Get("/data", func(c *core.Context) error {
+		return c.Blob(200, "application/json", []byte(compressBody))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/data", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			encoding := w.Header().Get("Content-Encoding")
+			if encoding != tt.encoding {
+				t.Fatalf("expected Content-Encoding %q, got %q", tt.encoding, encoding)
+			}
+			if tt.encoding != "" && w.Body.Len() >= len(compressBody) {
+				t.Errorf("expected a smaller body, got %d bytes", w.Body.Len())
+			}
+			if body := decompress(t, encoding, w.Body); body != compressBody {
+				t.Errorf("unexpected body %q", body)
+			}
+			if w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("expected Vary: Accept-Encoding, got %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+// TestCompressSkip tests responses that are sent uncompressed.
+func TestCompressSkip(t *testing.T) {
+	app := core.New()
+	app.Use(Compress(CompressConfig{
+		Encodings: []string{"gzip"},
+		SkipPaths: []string{"/skipped"},
+	}))
+
+	app.Get("/small", func(c *core.Context) error {
+		return c.JSON(200, map[string]string{"status": "ok"})
+	})
+	app.Get("/image", func(c *core.Context) error {
+		return c.Blob(200, "image/png", []byte(compressBody))
+	})
+	app.Get("/encoded", func(c *core.Context) error {
+		c.SetHeader("Content-Encoding", "br")
+		return c.Blob(200, "text/plain", []byte(compressBody))
+	})
+	app.Get("/no-transform", func(c *core.Context) error {
+		c.SetHeader("Cache-Control", "public, no-transform")
+		return c.Blob(200, "text/plain", []byte(compressBody))
+	})
+	app.Get("/skipped", func(c *core.Context) error {
+		return c.Blob(200, "text/plain", []byte(compressBody))
+	})
+	app.Get("/empty", func(c *core.Context) error {
+		return c.NoContent()
+	})
+
+	for _, path := range []string{"/small", "/image", "/encoded", "/no-transform", "/skipped", "/empty"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if ce := w.Header().Get("Content-Encoding"); ce != "" && path != "/encoded" {
+				t.Errorf("expected no compression, got Content-Encoding %q", ce)
+			}
+			if path == "/encoded" && (w.Header().Get("Content-Encoding") != "br" || w.Body.String() != compressBody) {
+				t.Error("expected an already encoded body to pass through")
+			}
+			if w.Header().Get("Vary") != "" {
+				t.Errorf("expected no Vary, got %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+// TestCompressStreaming tests that SSE events are flushed as they are sent.
+func TestCompressStreaming(t *testing.T) {
+	app := core.New()
+	app.Use(Compress(CompressConfig{Encodings: []string{"gzip"}}))
+
+	next := make(chan struct{})
+	app.Get("/events", func(c *core.Context) error {
+		return c.SSE(func(stream *core.SSEStream) error {
+			if err := stream.Send("", "", "first"); err != nil {
+				return err
+			}
+			<-next
+			return stream.Send("", "", "second")
+		})
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzip stream, got %q", resp.Header.Get("Content-Encoding"))
+	}
+
+	gr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(gr)
+
+	// The first event arrives while the handler is still blocked
+	read := make(chan string, 1)
+	go func() {
+		line, _ := reader.ReadString('\n')
+		read <- line
+	}()
+	select {
+	case line := <-read:
+		if line != "data: first\n" {
+			t.Errorf("unexpected first line %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("first event wasn't flushed")
+	}
+	close(next)
+
+	rest, err := io.ReadAll(reader)
+	if err != nil || string(rest) != "\ndata: second\n\n" {
+		t.Errorf("unexpected rest of stream %q (%v)", rest, err)
+	}
+}
+
+// TestCompressStatic tests compression of streamed static files.
+func TestCompressStatic(t *testing.T) {
+	app := core.New()
+	app.Use(Compress(CompressConfig{Encodings: []string{"br"}}))
+	app.StaticFS("/assets", fstest.MapFS{
+		"app.js":   {Data: []byte(strings.Repeat("console.log('bolt');\n", 200)), ModTime: time.Now()},
+		"logo.png": {Data: bytes.Repeat([]byte{0x89}, 4096), ModTime: time.Now()},
+	})
+
+	get := func(path, rangeHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "br")
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/assets/app.js", "")
+	if w.Header().Get("Content-Encoding") != "br" || w.Header().Get("Content-Length") != "" {
+		t.Fatalf("expected a brotli stream, got %q (Content-Length %q)", w.Header().Get("Content-Encoding"), w.Header().Get("Content-Length"))
+	}
+	if body := decompress(t, "br", w.Body); body != strings.Repeat("console.log('bolt');\n", 200) {
+		t.Errorf("unexpected file body %q", body)
+	}
+	if etag := w.Header().Get("ETag"); !strings.HasPrefix(etag, "W/") {
+		t.Errorf("expected a weak ETag for the compressed file, got %q", etag)
+	}
+
+	// Range requests and binary files are sent as is
+	if w := get("/assets/app.js", "bytes=0-9"); w.Code != 206 || w.Header().Get("Content-Encoding") != "" || w.Body.String() != "console.lo" {
+		t.Errorf("unexpected range response %d %q", w.Code, w.Body.String())
+	}
+	if w := get("/assets/logo.png", ""); w.Header().Get("Content-Encoding") != "" || w.Body.Len() != 4096 {
+		t.Errorf("expected an uncompressed image, got %q", w.Header().Get("Content-Encoding"))
+	}
+}
+
+// TestCompressInvalidEncoding tests the panic for unsupported encodings.
+func TestCompressInvalidEncoding(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an unsupported encoding")
+		}
+	}()
+	Compress(CompressConfig{Encodings: []string{"deflate"}})
+}
+
+// BenchmarkCompress measures pooled gzip compression of a 2.7KB JSON body.
+func BenchmarkCompress(b *testing.B) {
+	app := core.New()
+	app.Use(Compress(CompressConfig{Encodings: []string{"gzip"}}))
+	app.Get("/data", func(c *core.Context) error {
+		return c.Blob(200, "application/json", []byte(compressBody))
+	})
+	req := httptest.NewRequest("GET", "/data", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+	}
+}
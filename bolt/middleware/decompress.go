@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/yourusername/bolt/core"
+)
+
+// Decompress returns a middleware that decodes gzip, zstd and brotli request
+// bodies (Content-Encoding) before Bind, BindJSON and form parsing.
+//
+// Uses default configuration:
+//   - Encodings: ["gzip", "zstd", "br"] ("x-gzip" is accepted as gzip)
+//   - MaxSize: Config.MaxRequestBodySize
+//
+// The wire size is still limited by Config.MaxRequestBodySize; the
+// decompressed size by MaxSize, so a small compressed body can't expand into
+// gigabytes (zip bomb). Bodies past either limit fail with 413, corrupt
+// bodies with 400, and unsupported encodings with 415 listing the supported
+// ones in Accept-Encoding (RFC 7694).
+//
+// Example:
+//
+//	app := bolt.New()
+//	app.Use(middleware.Decompress())
+//	app.Post("/ingest", func(c *bolt.Context) error {
+//	    var events []Event
+//	    if err := c.BindJSON(&events); err != nil { // gzip or zstd on the wire
+//	        return err
+//	    }
+//	    return c.JSON(202, map[string]int{"accepted": len(events)})
+//	})
+func Decompress() core.Middleware {
+	return DecompressWithConfig(DefaultDecompressConfig())
+}
+
+// DecompressWithConfig returns a decompression middleware with custom configuration.
+//
+// Example:
+//
+//	app.Use(middleware.DecompressWithConfig(middleware.DecompressConfig{
+//	    Encodings: []string{"zstd"},
+//	    MaxSize:   100 << 20, // 100MB decompressed
+//	}))
+func DecompressWithConfig(config DecompressConfig) core.Middleware {
+	// Apply defaults
+	if len(config.Encodings) == 0 {
+		config.Encodings = []string{"gzip", "zstd", "br"}
+	}
+
+	decoders := make(map[string]*decompressDecoder, len(config.Encodings)+1)
+	for _, name := range config.Encodings {
+		dec := newDecompressDecoder(name)
+		decoders[name] = dec
+		if name == "gzip" {
+			decoders["x-gzip"] = dec // RFC 9110 §8.4.1.3
+		}
+	}
+	acceptEncoding := strings.Join(config.Encodings, ", ")
+
+	return func(next core.Handler) core.Handler {
+		return func(c *core.Context) error {
+			header := c.GetHeader("Content-Encoding")
+			if header == "" {
+				return next(c)
+			}
+
+			// Codings are listed in the order they were applied
+			var codings []*decompressDecoder
+			for name := range strings.SplitSeq(header, ",") {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if name == "identity" || name == "" {
+					continue
+				}
+				dec, ok := decoders[name]
+				if !ok {
+					c.SetHeader("Accept-Encoding", acceptEncoding)
+					return fmt.Errorf("%w: content encoding %q", core.ErrUnsupportedMediaType, name)
+				}
+				codings = append(codings, dec)
+			}
+			if len(codings) == 0 {
+				return next(c)
+			}
+
+			body := &decompressBody{codings: codings}
+			defer body.release()
+			c.SetRequestBodyDecoder(body.decode, config.MaxSize)
+			return next(c)
+		}
+	}
+}
+
+// DecompressConfig defines configuration for decompression middleware.
+type DecompressConfig struct {
+	// Encodings are the accepted content codings.
+	// Supported: "gzip", "zstd", "br"
+	// Default: ["gzip", "zstd", "br"]
+	Encodings []string
+
+	// MaxSize is the maximum decompressed body size in bytes.
+	// Default: 0 (Config.MaxRequestBodySize); negative disables the limit
+	MaxSize int64
+}
+
+// DefaultDecompressConfig returns default decompression configuration.
+func DefaultDecompressConfig() DecompressConfig {
+	return DecompressConfig{
+		Encodings: []string{"gzip", "zstd", "br"},
+		MaxSize:   0,
+	}
+}
+
+// decompressDecoder is a content coding with a pool of decoders.
+type decompressDecoder struct {
+	pool sync.Pool // streamDecoder
+}
+
+// streamDecoder is implemented by the gzip, zstd and brotli readers.
+type streamDecoder interface {
+	io.Reader
+	Reset(r io.Reader) error
+}
+
+// newDecompressDecoder returns the pooled decoder for name.
+// Panics for unknown encodings (configuration error).
+func newDecompressDecoder(name string) *decompressDecoder {
+	dec := &decompressDecoder{}
+	switch name {
+	case "gzip":
+		dec.pool.New = func() any { return new(gzip.Reader) }
+	case "br":
+		dec.pool.New = func() any { return brotli.NewReader(nil) }
+	case "zstd":
+		dec.pool.New = func() any {
+			r, _ := zstd.NewReader(nil,
+				zstd.WithDecoderConcurrency(1),   // One goroutine per request
+				zstd.WithDecoderLowmem(true),     // Favor memory over speed
+				zstd.WithDecoderMaxWindow(8<<20), // Reject huge windows (RFC 8878 recommends 8MB)
+			)
+			return r
+		}
+	default:
+		panic("middleware: unsupported decompression encoding " + strconv.Quote(name))
+	}
+	return dec
+}
+
+// decompressBody decodes one request's body and releases its decoders.
+type decompressBody struct {
+	codings  []*decompressDecoder
+	acquired []acquiredDecoder
+}
+
+// acquiredDecoder is a decoder borrowed from a pool.
+type acquiredDecoder struct {
+	dec  streamDecoder
+	pool *sync.Pool
+}
+
+// decode returns the decompressed body, undoing the codings in reverse order.
+func (b *decompressBody) decode(raw io.Reader) io.Reader {
+	r := raw
+	for i := len(b.codings) - 1; i >= 0; i-- {
+		pool := &b.codings[i].pool
+		dec := pool.Get().(streamDecoder)
+		b.acquired = append(b.acquired, acquiredDecoder{dec, pool})
+
+		// gzip reads its header here; errors surface on the first Read
+		if err := dec.Reset(r); err != nil {
+			return errorReader{err}
+		}
+		r = dec
+	}
+	return r
+}
+
+// release returns the decoders to their pools.
+func (b *decompressBody) release() {
+	for _, a := range b.acquired {
+		// gzip reads a header on Reset and can't take nil; it keeps the body
+		// reference until its next use
+		if _, ok := a.dec.(*gzip.Reader); !ok {
+			_ = a.dec.Reset(nil) // Drop the body reference
+		}
+		a.pool.Put(a.dec)
+	}
+}
+
+// errorReader returns err from every Read.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
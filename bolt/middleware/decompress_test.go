@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/yourusername/bolt/core"
+)
+
+// compress encodes data with a content coding.
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		zw, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w = zw
+	default:
+		t.Fatalf("unexpected encoding %q", encoding)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newDecompressApp returns an app echoing the bound JSON name.
+func newDecompressApp(config core.Config, middleware core.Middleware) *core.App {
+	app := core.NewWithConfig(config)
+	app.Use(middleware)
+	app.Post("/users", func(c *core.Context) error {
+		var user struct {
+			Name string `json:"name"`
+		}
+		if err := c.BindJSON(&user); err != nil {
+			return err
+		}
+		return c.JSON(200, map[string]string{"name": user.Name})
+	})
+	return app
+}
+
+func postEncoded(app *core.App, encoding string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+// TestDecompress tests binding of compressed request bodies.
+func TestDecompress(t *testing.T) {
+	app := newDecompressApp(core.DefaultConfig(), Decompress())
+	body := []byte(`{"name":"bolt"}`)
+
+	tests := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"", body},
+		{"identity", body},
+		{"gzip", compress(t, "gzip", body)},
+		{"x-gzip", compress(t, "gzip", body)},
+		{"GZIP", compress(t, "gzip", body)},
+		{"zstd", compress(t, "zstd", body)},
+		{"br", compress(t, "br", body)},
+		{"gzip, zstd", compress(t, "zstd", compress(t, "gzip", body))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			w := postEncoded(app, tt.encoding, tt.body)
+			if w.Code != 200 || w.Body.String() != "{\"name\":\"bolt\"}\n" {
+				t.Errorf("unexpected response %d %q", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestDecompressErrors tests unsupported, corrupt and oversized bodies.
+func TestDecompressErrors(t *testing.T) {
+	config := core.DefaultConfig()
+	config.MaxRequestBodySize = 4 << 10
+	app := newDecompressApp(config, DecompressWithConfig(DecompressConfig{
+		Encodings: []string{"gzip", "zstd"},
+		MaxSize:   64 << 10,
+	}))
+
+	// 1MB of spaces compresses to about 1KB
+	bomb := append(append([]byte(`{"name":"bolt"`), bytes.Repeat([]byte(" "), 1<<20)...), '}')
+	padded := append(append([]byte(`{"name":"bolt"`), bytes.Repeat([]byte(" "), 32<<10)...), '}')
+	random := make([]byte, 8<<10)
+	rand.Read(random)
+	incompressible := []byte(`{"name":"` + base64.StdEncoding.EncodeToString(random) + `"}`)
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		status   int
+	}{
+		{"unsupported", "br", compress(t, "br", []byte(`{}`)), 415},
+		{"unknown", "compress", []byte(`{}`), 415},
+		{"corrupt gzip", "gzip", []byte("not gzip at all"), 400},
+		{"corrupt zstd", "zstd", []byte("not zstd at all"), 400},
+		{"decoded limit", "gzip", compress(t, "gzip", bomb), 413},
+		{"zstd decoded limit", "zstd", compress(t, "zstd", bomb), 413},
+		{"wire limit", "gzip", compress(t, "gzip", incompressible), 413},
+		{"above wire limit once decoded", "gzip", compress(t, "gzip", padded), 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postEncoded(app, tt.encoding, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == 415 && w.Header().Get("Accept-Encoding") != "gzip, zstd" {
+				t.Errorf("expected Accept-Encoding \"gzip, zstd\", got %q", w.Header().Get("Accept-Encoding"))
+			}
+		})
+	}
+
+	// The default decoded limit is Config.MaxRequestBodySize
+	app = newDecompressApp(config, Decompress())
+	if w := postEncoded(app, "gzip", compress(t, "gzip", padded)); w.Code != 413 {
+		t.Errorf("expected 413 past Config.MaxRequestBodySize, got %d", w.Code)
+	}
+
+	// Pooled decoders are reusable after errors
+	if w := postEncoded(app, "gzip", compress(t, "gzip", []byte(`{"name":"bolt"}`))); w.Code != 200 {
+		t.Errorf("expected 200 after errors, got %d", w.Code)
+	}
+}
+
+// TestDecompressInvalidEncoding tests the panic for unsupported encodings.
+func TestDecompressInvalidEncoding(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an unsupported encoding")
+		}
+	}()
+	DecompressWithConfig(DecompressConfig{Encodings: []string{"deflate"}})
+}
+
+// BenchmarkDecompress measures pooled gzip decoding of a 2.7KB JSON body.
+func BenchmarkDecompress(b *testing.B) {
+	app := core.New()
+	app.Use(Decompress())
+	app.Post("/data", func(c *core.Context) error {
+		var v []map[string]any
+		if err := c.BindJSON(&v); err != nil {
+			return err
+		}
+		return c.NoContent()
+	})
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Write([]byte("[" + strings.TrimSuffix(strings.Repeat(`{"name":"bolt","fast":true},`, 100), ",") + "]"))
+	gw.Close()
+	body := buf.Bytes()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		req := httptest.NewRequest("POST", "/data", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AsyncLogWriter buffers log lines in memory and writes them to another
+// writer from a background goroutine, so requests never wait on slow
+// output (files, pipes, terminals).
+//
+// Buffered lines are written every flush interval, or sooner when the
+// buffer is half full. When it is full, lines are dropped instead of
+// blocking requests (see Dropped). Close writes the remaining lines.
+//
+// Example:
+//
+//	out := middleware.NewAsyncLogWriter(os.Stdout, 0, 0)
+//	defer out.Close()
+//
+//	app.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{Output: out}))
+type AsyncLogWriter struct {
+	out  io.Writer
+	size int
+
+	mu     sync.Mutex
+	buf    []byte // Lines waiting to be written
+	closed bool
+
+	spare   []byte        // Lines being written (owned by the writer goroutine)
+	wake    chan struct{} // Buffer half full
+	done    chan struct{} // Close called
+	stopped chan struct{} // Writer goroutine exited
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// NewAsyncLogWriter returns an AsyncLogWriter writing to w.
+//
+// bufferSize is the maximum size of buffered lines (default: 256KB);
+// flushInterval is the maximum time a line is buffered (default: 1 second).
+func NewAsyncLogWriter(w io.Writer, bufferSize int, flushInterval time.Duration) *AsyncLogWriter {
+	if bufferSize <= 0 {
+		bufferSize = 256 << 10
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+
+	aw := &AsyncLogWriter{
+		out:     w,
+		size:    bufferSize,
+		buf:     make([]byte, 0, bufferSize),
+		spare:   make([]byte, 0, bufferSize),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	// Start writer goroutine
+	go aw.run(flushInterval)
+
+	return aw
+}
+
+// Write buffers p. It never blocks on the underlying writer: if the buffer
+// is full, p is dropped. It returns os.ErrClosed after Close.
+func (w *AsyncLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, os.ErrClosed
+	}
+	if len(w.buf)+len(p) > w.size {
+		w.mu.Unlock()
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+	w.buf = append(w.buf, p...)
+	halfFull := len(w.buf) >= w.size/2
+	w.mu.Unlock()
+
+	if halfFull {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of writes dropped because the buffer was full.
+func (w *AsyncLogWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close writes the buffered lines and stops the writer goroutine.
+// It doesn't close the underlying writer.
+func (w *AsyncLogWriter) Close() error {
+	w.once.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		w.mu.Unlock()
+		close(w.done)
+	})
+	<-w.stopped
+	return nil
+}
+
+// run writes buffered lines until Close.
+func (w *AsyncLogWriter) run(interval time.Duration) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		case <-w.done:
+			w.flush()
+			return
+		}
+		w.flush()
+	}
+}
+
+// flush swaps the buffers and writes the buffered lines.
+func (w *AsyncLogWriter) flush() {
+	w.mu.Lock()
+	w.buf, w.spare = w.spare[:0], w.buf
+	w.mu.Unlock()
+
+	if len(w.spare) == 0 {
+		return
+	}
+	if _, err := w.out.Write(w.spare); err != nil {
+		log.Printf("Failed to write log: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/bolt/core"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestAsyncLogWriter tests background writes, flushing and Close.
+func TestAsyncLogWriter(t *testing.T) {
+	var out syncBuffer
+	w := NewAsyncLogWriter(&out, 0, 10*time.Millisecond)
+
+	if _, err := w.Write([]byte("line 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "" {
+		t.Error("expected the write to be buffered")
+	}
+
+	// Flushed by the interval
+	deadline := time.Now().Add(time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if out.String() != "line 1\n" {
+		t.Errorf("expected flushed line, got %q", out.String())
+	}
+
+	// Flushed by Close
+	_, _ = w.Write([]byte("line 2\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "line 1\nline 2\n" {
+		t.Errorf("expected all lines after Close, got %q", out.String())
+	}
+
+	if _, err := w.Write([]byte("line 3\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected os.ErrClosed after Close, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("expected Close to be idempotent, got %v", err)
+	}
+}
+
+// TestAsyncLogWriterFull tests that writes are dropped when the buffer is full.
+func TestAsyncLogWriterFull(t *testing.T) {
+	block := make(chan struct{})
+	out := &blockingWriter{block: block}
+	w := NewAsyncLogWriter(out, 16, time.Hour)
+
+	// 8 bytes wake the writer, which blocks on its write
+	_, _ = w.Write([]byte("1234567\n"))
+	deadline := time.Now().Add(time.Second)
+	for !out.started() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	for range 3 {
+		n, err := w.Write([]byte("abcdefg\n"))
+		if n != 8 || err != nil {
+			t.Fatalf("unexpected write result %d, %v", n, err)
+		}
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped write, got %d", w.Dropped())
+	}
+
+	close(block)
+	_ = w.Close()
+	if got := out.buf.String(); got != "1234567\nabcdefg\nabcdefg\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+// blockingWriter blocks writes until block is closed.
+type blockingWriter struct {
+	mu    sync.Mutex
+	block chan struct{}
+	began bool
+	buf   strings.Builder
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.began = true
+	w.mu.Unlock()
+	<-w.block
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) started() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.began
+}
+
+// BenchmarkLoggerAsync benchmarks logging through an AsyncLogWriter.
+func BenchmarkLoggerAsync(b *testing.B) {
+	w := NewAsyncLogWriter(discardWriter{}, 0, 0)
+	defer w.Close()
+
+	handler := LoggerWithConfig(LoggerConfig{Output: w, Format: "combined"})(func(c *core.Context) error {
+		return nil
+	})
+
+	ctx := &core.Context{}
+	ctx.SetMethod("GET")
+	ctx.SetPath("/test")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = handler(ctx)
+	}
+}
+
+// discardWriter discards writes.
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -453,3 +459,280 @@ func TestLoggerWithEmptyFormat(t *testing.T) {
 		t.Error("expected log output")
 	}
 }
+
+// TestLoggerFields tests selected JSON fields over a real request.
+func TestLoggerFields(t *testing.T) {
+	var logBuf bytes.Buffer
+	app := core.NewWithConfig(core.Config{TrustedProxies: []string{"10.0.0.0/8"}})
+	app.Use(LoggerWithConfig(LoggerConfig{
+		Output: &logBuf,
+		Fields: []string{"method", "route", "uri", "query", "status", "remote_ip", "user_agent", "referer", "request_id", "bytes_in", "bytes_out", "host", "protocol"},
+	}))
+	app.Post("/users/:id", func(c *core.Context) error {
+		c.SetHeader("X-Request-ID", "req-1")
+		return c.JSON(201, map[string]string{"id": c.Param("id")})
+	})
+
+	req := httptest.NewRequest("POST", "http://api.example.com/users/42?debug=1", strings.NewReader("hello"))
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("User-Agent", "curl/8.0 \"quoted\"")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(logBuf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse log JSON %q: %v", logBuf.String(), err)
+	}
+	want := map[string]any{
+		"method":     "POST",
+		"route":      "/users/:id",
+		"uri":        "/users/42?debug=1",
+		"query":      "debug=1",
+		"status":     201.0,
+		"remote_ip":  "203.0.113.7",
+		"user_agent": "curl/8.0 \"quoted\"",
+		"request_id": "req-1",
+		"bytes_in":   5.0,
+		"bytes_out":  0.0, // net/http: no Content-Length set
+		"host":       "api.example.com",
+		"protocol":   "HTTP/1.1",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["referer"]; ok {
+		t.Error("expected empty referer to be omitted")
+	}
+	if len(entry) != len(want) {
+		t.Errorf("unexpected fields: %v", entry)
+	}
+}
+
+// TestLoggerHeaderNoAlloc tests that long User-Agent and Referer headers
+// (overflow storage in Shockwave requests) are logged without copying.
+func TestLoggerHeaderNoAlloc(t *testing.T) {
+	userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36 Edg/131.0.0.0"
+	referer := "https://www.example.com/search?q=bolt+web+framework+benchmarks&source=hp&ei=a1B2c3D4e5F6g7H8&oq=bolt+web&gs_lcp=Cgdnd3Mtd2l6EAEYADIFCAAQgAQ"
+	raw := []byte("GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: " + userAgent + "\r\nReferer: " + referer + "\r\n\r\n")
+
+	// allocs returns the allocations per request through a Shockwave connection
+	allocs := func(format string) float64 {
+		var logBuf bytes.Buffer
+		app := core.New()
+		app.Use(LoggerWithConfig(LoggerConfig{Output: &logBuf, Format: format}))
+		app.Get("/", func(c *core.Context) error { return c.NoContent() })
+
+		client, server := net.Pipe()
+		defer client.Close()
+		go app.ServeConn(server)
+		reader := bufio.NewReader(client)
+
+		serve := func() {
+			logBuf.Reset()
+			if _, err := client.Write(raw); err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.ReadResponse(reader, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+		}
+		serve()
+		if format == "combined" && !strings.Contains(logBuf.String(), `"`+referer+`" "`+userAgent+`"`) {
+			t.Fatalf("expected headers in %q", logBuf.String())
+		}
+		return testing.AllocsPerRun(100, serve)
+	}
+
+	// "combined" is "common" plus the referer and user agent
+	if common, combined := allocs("common"), allocs("combined"); combined > common {
+		t.Errorf("combined: %v allocs/op, want %v as for common", combined, common)
+	}
+}
+
+// TestLoggerFormats tests the Apache formats and templates.
+func TestLoggerFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"combined", `192.0.2.1 - - [TIME] "GET /search?q=a%20b HTTP/1.1" 404 0 "https://example.com/" "agent \"x\"\x0a"` + "\n"},
+		{"common", `192.0.2.1 - - [TIME] "GET /search?q=a%20b HTTP/1.1" 404 0` + "\n"},
+		{"${method} ${route} ${status} ${request_id} ${error}|", "GET /search 404 - not found|\n"},
+	}
+
+	for _, tt := range tests {
+		var logBuf bytes.Buffer
+		app := core.New()
+		app.Use(LoggerWithConfig(LoggerConfig{Output: &logBuf, Format: tt.format, TimeFormat: "TIME"}))
+		app.Get("/search", func(c *core.Context) error {
+			return core.ErrNotFound
+		})
+
+		req := httptest.NewRequest("GET", "/search?q=a%20b", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("Referer", "https://example.com/")
+		req.Header.Set("User-Agent", "agent \"x\"\n")
+		app.ServeHTTP(httptest.NewRecorder(), req)
+
+		if logBuf.String() != tt.want {
+			t.Errorf("%s:\nexpected %q\ngot      %q", tt.format, tt.want, logBuf.String())
+		}
+	}
+}
+
+// TestLoggerErrorStatus tests that errors log the status the error handler sends.
+func TestLoggerErrorStatus(t *testing.T) {
+	var logBuf bytes.Buffer
+	handler := LoggerWithConfig(LoggerConfig{Output: &logBuf, Format: "${status}"})(func(c *core.Context) error {
+		return core.NewHTTPError(409, "conflict", "")
+	})
+
+	_ = handler(&core.Context{})
+	if logBuf.String() != "409\n" {
+		t.Errorf("expected status 409, got %q", logBuf.String())
+	}
+}
+
+// TestLoggerSampling tests per-status sampling.
+func TestLoggerSampling(t *testing.T) {
+	var logBuf bytes.Buffer
+	status := 200
+	handler := LoggerWithConfig(LoggerConfig{
+		Output:   &logBuf,
+		Format:   "${status}",
+		Sampling: map[int]float64{2: 0, 204: 1, 404: 0.5},
+	})(func(c *core.Context) error {
+		return c.JSON(status, nil)
+	})
+
+	count := func(s, n int) int {
+		status = s
+		logBuf.Reset()
+		for range n {
+			_ = handler(&core.Context{})
+		}
+		return strings.Count(logBuf.String(), "\n")
+	}
+
+	if n := count(200, 100); n != 0 {
+		t.Errorf("expected 2xx to be dropped, got %d lines", n)
+	}
+	if n := count(204, 100); n != 100 {
+		t.Errorf("expected 204 to be logged, got %d lines", n)
+	}
+	if n := count(500, 100); n != 100 {
+		t.Errorf("expected unlisted statuses to be logged, got %d lines", n)
+	}
+	if n := count(404, 1000); n < 350 || n > 650 {
+		t.Errorf("expected about half of 404s to be logged, got %d lines", n)
+	}
+}
+
+// TestLoggerSlog tests slog output.
+func TestLoggerSlog(t *testing.T) {
+	var logBuf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logBuf, nil))
+
+	status := 200
+	handler := LoggerWithConfig(LoggerConfig{
+		Slog:   logger,
+		Fields: []string{"time", "method", "path", "status", "duration", "error"},
+	})(func(c *core.Context) error {
+		return c.JSON(status, nil)
+	})
+
+	for _, s := range []int{200, 404, 503} {
+		status = s
+		ctx := &core.Context{}
+		ctx.SetMethod("GET")
+		ctx.SetPath("/slog")
+		_ = handler(ctx)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logBuf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 records, got %q", logBuf.String())
+	}
+	for i, level := range []string{"INFO", "WARN", "ERROR"} {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &record); err != nil {
+			t.Fatal(err)
+		}
+		if record["level"] != level || record["msg"] != "request" || record["method"] != "GET" || record["path"] != "/slog" {
+			t.Errorf("unexpected record %v", record)
+		}
+		if _, ok := record["duration"].(float64); !ok {
+			t.Errorf("expected duration attribute, got %v", record)
+		}
+		if _, ok := record["error"]; ok {
+			t.Errorf("expected empty error to be omitted, got %v", record)
+		}
+	}
+}
+
+// TestLoggerInvalidFields tests that unknown fields panic.
+func TestLoggerInvalidFields(t *testing.T) {
+	for _, config := range []LoggerConfig{
+		{Fields: []string{"method", "nope"}},
+		{Format: "${method} ${nope}"},
+		{Format: "${method"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %+v", config)
+				}
+			}()
+			LoggerWithConfig(config)
+		}()
+	}
+}
+
+// TestLoggerTextLayout tests the "text" and "short" preset layouts.
+func TestLoggerTextLayout(t *testing.T) {
+	tests := []struct {
+		format string
+		want   *regexp.Regexp
+	}{
+		{"text", regexp.MustCompile(`^GET /ok - 204 - \S+\nGET /missing - 404 - \S+ - ERROR: not found\n$`)},
+		{"short", regexp.MustCompile(`^TIME GET /ok 204 \S+ -\nTIME GET /missing 404 \S+ not found\n$`)},
+	}
+
+	for _, tt := range tests {
+		var logBuf bytes.Buffer
+		app := core.New()
+		app.Use(LoggerWithConfig(LoggerConfig{Output: &logBuf, Format: tt.format, TimeFormat: "TIME"}))
+		app.Get("/ok", func(c *core.Context) error { return c.NoContent() })
+		app.Get("/missing", func(c *core.Context) error { return core.ErrNotFound })
+
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+		if !tt.want.MatchString(logBuf.String()) {
+			t.Errorf("%s: expected %s, got %q", tt.format, tt.want, logBuf.String())
+		}
+	}
+}
+
+// TestLoggerDefaultConfigPreset tests that presets pick their time layout
+// when starting from DefaultLoggerConfig.
+func TestLoggerDefaultConfigPreset(t *testing.T) {
+	var logBuf bytes.Buffer
+	cfg := DefaultLoggerConfig()
+	cfg.Output = &logBuf
+	cfg.Format = "combined"
+
+	app := core.New()
+	app.Use(LoggerWithConfig(cfg))
+	app.Get("/", func(c *core.Context) error { return c.NoContent() })
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	apacheTime := regexp.MustCompile(`\[\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\]`)
+	if !apacheTime.MatchString(logBuf.String()) {
+		t.Errorf("expected an Apache timestamp, got %q", logBuf.String())
+	}
+}
@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -19,13 +23,21 @@ import (
 //	    Burst:             20,
 //	}))
 //
-// Performance: <50ns overhead for cached limiters.
+// Performance: lock-free key lookup with the in-memory store.
 func RateLimit(config RateLimitConfig) core.Middleware {
 	return RateLimitWithConfig(config)
 }
 
 // RateLimitWithConfig returns rate limiting middleware with custom configuration.
 //
+// Every response carries RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers (IETF draft-ietf-httpapi-ratelimit-headers);
+// rejected requests also get Retry-After.
+//
+// Limits are kept in config.Store: the default in-memory store limits each
+// instance separately, so N replicas allow N times the quota. Share a store
+// (NewDALRateLimitStore) to enforce one limit across instances.
+//
 // Example:
 //
 //	app.Use(middleware.RateLimitWithConfig(middleware.RateLimitConfig{
@@ -37,6 +49,17 @@ func RateLimit(config RateLimitConfig) core.Middleware {
 //	        return user
 //	    },
 //	}))
+//
+// Per-route quotas use route middleware with a Name, so their state doesn't
+// mix with other limits in a shared store:
+//
+//	app.Post("/login", login).Use(middleware.RateLimitWithConfig(middleware.RateLimitConfig{
+//	    Name:      "login",
+//	    Limit:     5,
+//	    Window:    time.Minute,
+//	    Algorithm: middleware.RateLimitSlidingWindowLog,
+//	    Store:     store,
+//	}))
 func RateLimitWithConfig(config RateLimitConfig) core.Middleware {
 	// Apply defaults
 	if config.RequestsPerSecond == 0 {
@@ -54,36 +77,80 @@ func RateLimitWithConfig(config RateLimitConfig) core.Middleware {
 	if config.MaxAge == 0 {
 		config.MaxAge = 5 * time.Minute
 	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore(config.CleanupInterval)
+	}
 
-	// Initialize limiter store
-	store := &limiterStore{
-		limiters:        sync.Map{},
-		rate:            float64(config.RequestsPerSecond),
-		burst:           config.Burst,
-		cleanupInterval: config.CleanupInterval,
-		maxAge:          config.MaxAge,
+	quota := rateLimitQuota{limit: config.Limit, window: config.Window}
+	if quota.limit <= 0 || quota.window <= 0 {
+		// Burst requests, refilled at RequestsPerSecond
+		quota.limit = config.Burst
+		quota.window = time.Duration(config.Burst) * time.Second / time.Duration(config.RequestsPerSecond)
 	}
+	take, ttl := rateLimitAlgorithm(config.Algorithm, quota)
 
-	// Start cleanup goroutine
-	go store.cleanup()
+	prefix := ""
+	if config.Name != "" {
+		prefix = config.Name + ":"
+	}
+
+	// Pre-compute header values for performance
+	limitHeader := strconv.Itoa(quota.limit)
+	policyHeader := limitHeader + ";w=" + strconv.FormatInt(int64(math.Ceil(quota.window.Seconds())), 10)
+
+	// ✅ OPTIMIZATION: The in-memory store never blocks, so it doesn't need
+	// the request context (which is allocated on first use)
+	_, local := config.Store.(*memoryRateLimitStore)
+
+	// ✅ OPTIMIZATION: Pool the store callbacks (a closure per request would
+	// escape to the heap)
+	ops := sync.Pool{
+		New: func() interface{} {
+			op := &rateLimitOp{take: take, quota: quota}
+			op.update = op.apply
+			return op
+		},
+	}
 
 	return func(next core.Handler) core.Handler {
 		return func(c *core.Context) error {
 			// Get rate limit key
-			key := config.KeyFunc(c)
+			key := prefix + config.KeyFunc(c)
+
+			ctx := context.Background()
+			if !local {
+				ctx = c.Context()
+			}
+
+			op := ops.Get().(*rateLimitOp)
+			op.now = time.Now().UnixNano()
+			err := config.Store.Update(ctx, key, ttl, op.update)
+			result := op.result
+			ops.Put(op)
+			if err != nil {
+				if config.FailClosed {
+					return fmt.Errorf("rate limit: %w", err)
+				}
+				return next(c) // Fail open: a store outage must not take the API down
+			}
 
-			// Get or create limiter for this key
-			limiter := store.getLimiter(key)
+			if !config.DisableHeaders {
+				c.SetHeader("RateLimit-Limit", limitHeader)
+				c.SetHeader("RateLimit-Policy", policyHeader)
+				c.SetHeader("RateLimit-Remaining", strconv.Itoa(result.remaining))
+				c.SetHeader("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.reset), 10))
+			}
 
 			// Check if request is allowed
-			if !limiter.limiter.allow() {
+			if !result.allowed {
 				// Rate limit exceeded
+				c.SetHeader("Retry-After", strconv.FormatInt(max(ceilSeconds(result.retryAfter), 1), 10))
 				if config.ErrorHandler != nil {
 					return config.ErrorHandler(c)
 				}
 				return c.JSON(429, map[string]interface{}{
 					"error":   "Rate limit exceeded",
-					"retryIn": limiter.limiter.retryIn().Seconds(),
+					"retryIn": result.retryAfter.Seconds(),
 				})
 			}
 
@@ -95,26 +162,57 @@ func RateLimitWithConfig(config RateLimitConfig) core.Middleware {
 // RateLimitConfig defines rate limiting configuration.
 type RateLimitConfig struct {
 	// RequestsPerSecond is the number of requests allowed per second
+	// Ignored when Limit and Window are set
 	// Default: 100
 	RequestsPerSecond int
 
 	// Burst is the maximum burst size
+	// Ignored when Limit and Window are set
 	// Default: 20
 	Burst int
 
+	// Limit is the number of requests allowed per Window, e.g. 1000 per hour
+	// Default: Burst per Burst/RequestsPerSecond seconds
+	Limit  int
+	Window time.Duration
+
+	// Algorithm selects how requests are counted
+	// Default: RateLimitTokenBucket
+	Algorithm RateLimitAlgorithm
+
+	// Store keeps the rate limit state
+	// Default: in-memory store (per instance)
+	Store RateLimitStore
+
+	// Name namespaces the keys of this limit in the store, for several
+	// limits (e.g. per-route quotas) sharing one store
+	// Default: "" (no namespace)
+	Name string
+
 	// KeyFunc generates a unique key for rate limiting
-	// Default: IP address
+	// Default: client IP address (Context.RealIP)
 	KeyFunc func(*core.Context) string
 
 	// ErrorHandler is called when rate limit is exceeded
 	// Default: returns 429 with retry time
 	ErrorHandler func(*core.Context) error
 
-	// CleanupInterval is how often to clean up old limiters
+	// FailClosed rejects requests when the store fails, returning the error
+	// to the error handler
+	// Default: false (requests pass while the store is unavailable)
+	FailClosed bool
+
+	// DisableHeaders omits the RateLimit-* headers (Retry-After is always sent)
+	// Default: false
+	DisableHeaders bool
+
+	// CleanupInterval is how often the default in-memory store removes
+	// expired state
 	// Default: 1 minute
 	CleanupInterval time.Duration
 
 	// MaxAge is how long to keep inactive limiters
+	// Deprecated: state now expires once it no longer affects the limit.
 	// Default: 5 minutes
 	MaxAge time.Duration
 }
@@ -124,148 +222,221 @@ func DefaultRateLimitConfig() RateLimitConfig {
 	return RateLimitConfig{
 		RequestsPerSecond: 100,
 		Burst:             20,
+		Algorithm:         RateLimitTokenBucket,
 		KeyFunc:           defaultKeyFunc,
 		CleanupInterval:   1 * time.Minute,
 		MaxAge:            5 * time.Minute,
 	}
 }
 
-// defaultKeyFunc returns IP address as rate limit key.
+// defaultKeyFunc returns the client IP address as rate limit key.
+//
+// Forwarding headers only count behind Config.TrustedProxies (see
+// Context.RealIP): otherwise clients could pick a fresh key per request.
 func defaultKeyFunc(c *core.Context) string {
-	// Try X-Forwarded-For header first
-	if ip := c.GetHeader("X-Forwarded-For"); ip != "" {
-		return ip
-	}
+	return c.RealIP()
+}
 
-	// Try X-Real-IP header
-	if ip := c.GetHeader("X-Real-IP"); ip != "" {
-		return ip
-	}
+// RateLimitAlgorithm selects how RateLimitWithConfig counts requests.
+type RateLimitAlgorithm int
 
-	// Fall back to remote address
-	// In a real implementation, this would come from the connection
-	return "default"
-}
+const (
+	// RateLimitTokenBucket refills Limit tokens evenly over Window; each
+	// request takes one. Allows bursts of up to Limit requests.
+	RateLimitTokenBucket RateLimitAlgorithm = iota
+
+	// RateLimitSlidingWindowLog records the time of every allowed request and
+	// allows at most Limit in any Window. Exact, but stores up to Limit
+	// timestamps per key.
+	RateLimitSlidingWindowLog
+
+	// RateLimitSlidingWindowCounter counts requests in fixed windows and
+	// weighs the previous window by its overlap with the sliding one.
+	// Approximate, with constant state per key.
+	RateLimitSlidingWindowCounter
+
+	// RateLimitGCRA is the generic cell rate algorithm: equivalent to the
+	// token bucket, with a single timestamp as state.
+	RateLimitGCRA
+)
 
-// limiterStore manages rate limiters per key.
-type limiterStore struct {
-	limiters        sync.Map
-	rate            float64
-	burst           int
-	cleanupInterval time.Duration
-	maxAge          time.Duration
+// rateLimitQuota allows limit requests per window.
+type rateLimitQuota struct {
+	limit  int
+	window time.Duration
 }
 
-// limiterEntry wraps a token bucket limiter with last access time.
-type limiterEntry struct {
-	limiter    *tokenBucket
-	lastAccess time.Time
-	mu         sync.Mutex
+// rateLimitResult is the outcome of one request.
+type rateLimitResult struct {
+	allowed    bool
+	remaining  int           // Requests left right now
+	reset      time.Duration // Until the full limit is available again
+	retryAfter time.Duration // Until the next request is allowed (rejected requests)
 }
 
-// getLimiter returns or creates a rate limiter for the given key.
-func (ls *limiterStore) getLimiter(key string) *limiterEntry {
-	// Fast path: limiter exists
-	if entry, ok := ls.limiters.Load(key); ok {
-		e := entry.(*limiterEntry)
-		e.mu.Lock()
-		e.lastAccess = time.Now()
-		e.mu.Unlock()
-		return e
+// rateLimitTake counts one request at now (Unix nanoseconds) in state.
+type rateLimitTake func(state *RateLimitState, quota rateLimitQuota, now int64) rateLimitResult
+
+// rateLimitAlgorithm returns the take function of an algorithm and how long
+// its state matters.
+func rateLimitAlgorithm(algorithm RateLimitAlgorithm, quota rateLimitQuota) (rateLimitTake, time.Duration) {
+	switch algorithm {
+	case RateLimitTokenBucket:
+		return takeTokenBucket, quota.window
+	case RateLimitSlidingWindowLog:
+		return takeSlidingWindowLog, quota.window
+	case RateLimitSlidingWindowCounter:
+		return takeSlidingWindowCounter, 2 * quota.window
+	case RateLimitGCRA:
+		return takeGCRA, quota.window
 	}
+	panic("middleware: unknown rate limit algorithm " + strconv.Itoa(int(algorithm)))
+}
 
-	// Slow path: create new limiter
-	entry := &limiterEntry{
-		limiter:    newTokenBucket(ls.rate, ls.burst),
-		lastAccess: time.Now(),
+// takeTokenBucket implements the token bucket algorithm.
+// State: Tokens left at Time.
+func takeTokenBucket(state *RateLimitState, quota rateLimitQuota, now int64) (result rateLimitResult) {
+	limit := float64(quota.limit)
+	rate := limit / float64(quota.window) // Tokens per nanosecond
+
+	// Refill tokens based on elapsed time (a new key starts full)
+	if state.Time == 0 {
+		state.Tokens = limit
+	} else if elapsed := now - state.Time; elapsed > 0 {
+		state.Tokens = min(limit, state.Tokens+float64(elapsed)*rate)
 	}
+	state.Time = max(state.Time, now)
 
-	// Try to store, use existing if another goroutine created it
-	actual, loaded := ls.limiters.LoadOrStore(key, entry)
-	if loaded {
-		return actual.(*limiterEntry)
+	// Check if we have tokens available
+	if state.Tokens >= 1.0 {
+		state.Tokens -= 1.0
+		result.allowed = true
+	} else {
+		result.retryAfter = time.Duration((1.0 - state.Tokens) / rate)
 	}
-
-	return entry
+	result.remaining = int(state.Tokens)
+	result.reset = time.Duration((limit - state.Tokens) / rate)
+	return result
 }
 
-// cleanup periodically removes old limiters.
-func (ls *limiterStore) cleanup() {
-	ticker := time.NewTicker(ls.cleanupInterval)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		now := time.Now()
-		ls.limiters.Range(func(key, value interface{}) bool {
-			entry := value.(*limiterEntry)
-			entry.mu.Lock()
-			age := now.Sub(entry.lastAccess)
-			entry.mu.Unlock()
-
-			if age > ls.maxAge {
-				ls.limiters.Delete(key)
-			}
-			return true
-		})
+// takeGCRA implements the generic cell rate algorithm.
+// State: the theoretical arrival time (TAT) of the next request in Time.
+func takeGCRA(state *RateLimitState, quota rateLimitQuota, now int64) (result rateLimitResult) {
+	interval := int64(quota.window) / int64(quota.limit) // Emission interval
+	tat := max(state.Time, now)
+	next := tat + interval
+	allowAt := next - int64(quota.window)
+
+	if now < allowAt {
+		result.retryAfter = time.Duration(allowAt - now)
+		result.reset = time.Duration(tat - now)
+		return result
 	}
-}
 
-// tokenBucket implements token bucket rate limiting algorithm.
-type tokenBucket struct {
-	tokens         float64
-	maxTokens      float64
-	refillRate     float64 // tokens per second
-	lastRefill     time.Time
-	mu             sync.Mutex
+	state.Time = next
+	result.allowed = true
+	result.remaining = int((now - allowAt) / interval)
+	result.reset = time.Duration(next - now)
+	return result
 }
 
-// newTokenBucket creates a new token bucket.
-func newTokenBucket(rate float64, burst int) *tokenBucket {
-	return &tokenBucket{
-		tokens:     float64(burst),
-		maxTokens:  float64(burst),
-		refillRate: rate,
-		lastRefill: time.Now(),
+// takeSlidingWindowLog implements the sliding window log algorithm.
+// State: the times of the allowed requests of the last window in Log.
+func takeSlidingWindowLog(state *RateLimitState, quota rateLimitQuota, now int64) (result rateLimitResult) {
+	// Drop requests that left the window (in place)
+	cutoff := now - int64(quota.window)
+	log := state.Log[:0]
+	for _, t := range state.Log {
+		if t > cutoff {
+			log = append(log, t)
+		}
 	}
-}
-
-// allow checks if a request is allowed and consumes a token.
-func (tb *tokenBucket) allow() bool {
-	tb.mu.Lock()
-	defer tb.mu.Unlock()
 
-	// Refill tokens based on elapsed time
-	now := time.Now()
-	elapsed := now.Sub(tb.lastRefill).Seconds()
-	tb.tokens += elapsed * tb.refillRate
+	if len(log) < quota.limit {
+		log = append(log, now)
+		result.allowed = true
+	} else {
+		oldest := log[0]
+		for _, t := range log[1:] {
+			oldest = min(oldest, t) // Instances' clocks may disagree
+		}
+		result.retryAfter = time.Duration(oldest - cutoff)
+	}
+	state.Log = log
 
-	// Cap at max tokens
-	if tb.tokens > tb.maxTokens {
-		tb.tokens = tb.maxTokens
+	result.remaining = quota.limit - len(log)
+	newest := int64(0)
+	for _, t := range log {
+		newest = max(newest, t)
 	}
+	result.reset = time.Duration(max(newest-cutoff, 0))
+	return result
+}
 
-	tb.lastRefill = now
+// takeSlidingWindowCounter implements the sliding window counter algorithm.
+// State: Count requests in the fixed window starting at Time, Previous in
+// the window before.
+func takeSlidingWindowCounter(state *RateLimitState, quota rateLimitQuota, now int64) (result rateLimitResult) {
+	window := int64(quota.window)
+	start := now - now%window
+	if state.Time < start {
+		if state.Time == start-window {
+			state.Previous = state.Count
+		} else {
+			state.Previous = 0
+		}
+		state.Count = 0
+		state.Time = start
+	}
+	start = state.Time // Another instance's clock may be ahead
+
+	// Share of the previous window still inside the sliding window
+	weight := min(max(float64(start+window-now)/float64(window), 0), 1)
+	estimate := float64(state.Previous)*weight + float64(state.Count)
+	limit := float64(quota.limit)
+
+	if estimate+1 <= limit {
+		state.Count++
+		estimate++
+		result.allowed = true
+	} else if state.Count >= quota.limit {
+		// Blocked by this window alone: wait until its weight drops enough
+		// in the next one
+		wait := float64(window) * (1 - (limit-1)/float64(state.Count))
+		result.retryAfter = time.Duration(start + window - now + int64(wait))
+	} else {
+		// Wait until the previous window's weight drops enough
+		wait := float64(window) * (limit - 1 - float64(state.Count)) / float64(state.Previous)
+		result.retryAfter = time.Duration(start + window - int64(wait) - now)
+	}
 
-	// Check if we have tokens available
-	if tb.tokens >= 1.0 {
-		tb.tokens -= 1.0
-		return true
+	result.remaining = max(quota.limit-int(math.Ceil(estimate)), 0)
+	switch {
+	case state.Count > 0:
+		result.reset = time.Duration(start + 2*window - now)
+	case state.Previous > 0:
+		result.reset = time.Duration(start + window - now)
 	}
+	return result
+}
 
-	return false
+// rateLimitOp counts one request through RateLimitStore.Update.
+type rateLimitOp struct {
+	take   rateLimitTake
+	quota  rateLimitQuota
+	now    int64
+	result rateLimitResult
+	update func(*RateLimitState) // op.apply, bound once
 }
 
-// retryIn returns how long until next token is available.
-func (tb *tokenBucket) retryIn() time.Duration {
-	tb.mu.Lock()
-	defer tb.mu.Unlock()
+func (op *rateLimitOp) apply(state *RateLimitState) {
+	op.result = op.take(state, op.quota, op.now)
+}
 
-	// Calculate time needed to get 1 token
-	tokensNeeded := 1.0 - tb.tokens
-	if tokensNeeded <= 0 {
+// ceilSeconds rounds d up to whole seconds (0 if negative).
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
 		return 0
 	}
-
-	seconds := tokensNeeded / tb.refillRate
-	return time.Duration(seconds * float64(time.Second))
+	return int64((d + time.Second - 1) / time.Second)
 }
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+// RateLimitStore keeps rate limit state by key.
+//
+// Instances sharing a store share their limits. Implementations must be
+// safe for concurrent use.
+type RateLimitStore interface {
+	// Update loads the state of key (the zero state if missing or expired),
+	// calls update to modify it and stores it, expiring it after ttl.
+	// Updates of the same key must not interleave, or requests could slip
+	// past the limit.
+	Update(ctx context.Context, key string, ttl time.Duration, update func(*RateLimitState)) error
+}
+
+// RateLimitState is the state of one rate limit key. Its meaning depends on
+// the algorithm; stores only persist it.
+type RateLimitState struct {
+	Time     int64   `json:"t,omitempty"`   // Unix nanoseconds: last refill, window start or TAT
+	Tokens   float64 `json:"tok,omitempty"` // Token bucket
+	Count    int     `json:"c,omitempty"`   // Sliding window counter: current window
+	Previous int     `json:"p,omitempty"`   // Sliding window counter: previous window
+	Log      []int64 `json:"log,omitempty"` // Sliding window log: request times
+}
+
+// NewMemoryRateLimitStore returns a RateLimitStore keeping state in memory.
+// Limits are per instance; use NewDALRateLimitStore to share them.
+//
+// Expired state is removed every cleanupInterval (default: 1 minute).
+func NewMemoryRateLimitStore(cleanupInterval time.Duration) RateLimitStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+	store := &memoryRateLimitStore{}
+
+	// Start cleanup goroutine
+	go store.cleanup(cleanupInterval)
+
+	return store
+}
+
+// memoryRateLimitStore keeps state in a sync.Map, locking each key.
+type memoryRateLimitStore struct {
+	entries sync.Map // string → *memoryRateLimitEntry
+}
+
+// memoryRateLimitEntry is the state of one key.
+type memoryRateLimitEntry struct {
+	mu      sync.Mutex
+	state   RateLimitState
+	expires int64 // Unix nanoseconds
+	removed bool  // Deleted by cleanup: callers must load a new entry
+}
+
+func (s *memoryRateLimitStore) Update(ctx context.Context, key string, ttl time.Duration, update func(*RateLimitState)) error {
+	for {
+		// Fast path: entry exists
+		value, ok := s.entries.Load(key)
+		if !ok {
+			// Slow path: create entry, use existing if another goroutine created it
+			value, _ = s.entries.LoadOrStore(key, &memoryRateLimitEntry{})
+		}
+		entry := value.(*memoryRateLimitEntry)
+
+		entry.mu.Lock()
+		if entry.removed {
+			entry.mu.Unlock()
+			continue
+		}
+		now := time.Now().UnixNano()
+		if entry.expires != 0 && now >= entry.expires {
+			entry.state = RateLimitState{Log: entry.state.Log[:0]}
+		}
+		update(&entry.state)
+		entry.expires = now + int64(ttl)
+		entry.mu.Unlock()
+		return nil
+	}
+}
+
+// cleanup periodically removes expired state.
+func (s *memoryRateLimitStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now().UnixNano()
+		s.entries.Range(func(key, value interface{}) bool {
+			entry := value.(*memoryRateLimitEntry)
+			entry.mu.Lock()
+			if now >= entry.expires {
+				entry.removed = true
+				s.entries.Delete(key)
+			}
+			entry.mu.Unlock()
+			return true
+		})
+	}
+}
+
+// NewDALRateLimitStore returns a RateLimitStore backed by a capacitor DAL,
+// e.g. Redis or a database shared by all instances.
+//
+// Updates run in a DAL transaction when a layer supports them, making them
+// atomic across instances. Otherwise they are only serialized within this
+// instance: concurrent requests on several instances may exceed the limit
+// by a few requests.
+//
+// Example:
+//
+//	config, err := capacitor.NewBuilder[string, middleware.RateLimitState]().
+//	    WithLayer("redis", redisLayer, 0).
+//	    Build()
+//	dal, err := capacitor.NewMultiLayer(config)
+//
+//	store := middleware.NewDALRateLimitStore(dal)
+//	app.Use(middleware.RateLimitWithConfig(middleware.RateLimitConfig{
+//	    Limit:  1000,
+//	    Window: time.Hour,
+//	    Store:  store,
+//	}))
+func NewDALRateLimitStore(dal capacitor.DAL[string, RateLimitState]) RateLimitStore {
+	return &dalRateLimitStore{dal: dal, seed: maphash.MakeSeed()}
+}
+
+// dalRateLimitStore adapts a capacitor DAL to RateLimitStore.
+type dalRateLimitStore struct {
+	dal   capacitor.DAL[string, RateLimitState]
+	seed  maphash.Seed
+	locks [64]sync.Mutex // Serialize updates of a key within this instance
+	noTx  atomic.Bool    // BeginTx reported ErrTxNotSupported
+}
+
+func (s *dalRateLimitStore) Update(ctx context.Context, key string, ttl time.Duration, update func(*RateLimitState)) error {
+	lock := &s.locks[maphash.String(s.seed, key)%uint64(len(s.locks))]
+	lock.Lock()
+	defer lock.Unlock()
+
+	if !s.noTx.Load() {
+		tx, err := s.dal.BeginTx(ctx, capacitor.WithIsolation(capacitor.IsolationSerializable))
+		if err == nil {
+			defer tx.Rollback(ctx)
+			state, err := s.load(ctx, tx.Get, key)
+			if err != nil {
+				return err
+			}
+			update(&state)
+			if err := tx.Set(ctx, key, state, capacitor.WithTTL(int64(ttl))); err != nil {
+				return err
+			}
+			return tx.Commit(ctx)
+		}
+		if !errors.Is(err, capacitor.ErrTxNotSupported) {
+			return err
+		}
+		s.noTx.Store(true)
+	}
+
+	state, err := s.load(ctx, s.dal.Get, key)
+	if err != nil {
+		return err
+	}
+	update(&state)
+	return s.dal.Set(ctx, key, state, capacitor.WithTTL(int64(ttl)))
+}
+
+// load reads the state of key with get, returning the zero state if missing.
+// The log is copied: in-memory layers may share it with other readers.
+func (s *dalRateLimitStore) load(ctx context.Context, get func(context.Context, string) (RateLimitState, error), key string) (RateLimitState, error) {
+	state, err := get(ctx, key)
+	if err != nil {
+		if capacitor.IsNotFound(err) {
+			return RateLimitState{}, nil
+		}
+		return RateLimitState{}, err
+	}
+	state.Log = slices.Clone(state.Log)
+	return state, nil
+}
@@ -0,0 +1,288 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+	"github.com/yourusername/bolt/core"
+)
+
+// TestMemoryRateLimitStore tests per-key state and expiry.
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := NewMemoryRateLimitStore(10 * time.Millisecond).(*memoryRateLimitStore)
+	ctx := context.Background()
+	count := func(key string, ttl time.Duration) int {
+		var n int
+		if err := store.Update(ctx, key, ttl, func(state *RateLimitState) {
+			state.Count++
+			n = state.Count
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if count("key1", time.Minute) != 1 || count("key1", time.Minute) != 2 {
+		t.Error("expected the same state for the same key")
+	}
+	if count("key2", time.Minute) != 1 {
+		t.Error("expected a different state for a different key")
+	}
+
+	// Expired state starts over, and is removed by cleanup
+	count("short", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if count("short", time.Millisecond) != 1 {
+		t.Error("expected expired state to be reset")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := store.entries.Load("short"); ok {
+		t.Error("expected expired state to be removed")
+	}
+	if _, ok := store.entries.Load("key1"); !ok {
+		t.Error("expected live state to be kept")
+	}
+}
+
+// TestDALRateLimitStore tests one limit shared by several instances.
+func TestDALRateLimitStore(t *testing.T) {
+	for _, transactions := range []bool{false, true} {
+		layer := &rateLimitLayer{data: make(map[string]RateLimitState), transactions: transactions}
+		config, err := capacitor.NewBuilder[string, RateLimitState]().
+			WithLayer("shared", layer, 0).
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dal, err := capacitor.NewMultiLayer(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Two instances, each with its own store over the shared DAL
+		var instances []core.Handler
+		for range 2 {
+			instances = append(instances, RateLimitWithConfig(RateLimitConfig{
+				Name:      "api",
+				Limit:     3,
+				Window:    time.Minute,
+				Algorithm: RateLimitSlidingWindowLog,
+				Store:     NewDALRateLimitStore(dal),
+				KeyFunc:   func(c *core.Context) string { return "client" },
+			})(func(c *core.Context) error {
+				return c.NoContent()
+			}))
+		}
+
+		for i, want := range []int{204, 204, 204, 429, 429} {
+			ctx := &core.Context{}
+			_ = instances[i%2](ctx)
+			if ctx.StatusCode() != want {
+				t.Errorf("transactions %v, request %d: expected status %d, got %d", transactions, i+1, want, ctx.StatusCode())
+			}
+		}
+
+		layer.mu.Lock()
+		state, ok := layer.data["api:client"]
+		if !ok || len(state.Log) != 3 || (layer.commits > 0) != transactions {
+			t.Errorf("transactions %v: unexpected stored state %+v (commits %d)", transactions, state, layer.commits)
+		}
+		layer.mu.Unlock()
+	}
+}
+
+// TestDALRateLimitStoreConcurrent tests that transactions keep the limit
+// exact across instances.
+func TestDALRateLimitStoreConcurrent(t *testing.T) {
+	layer := &rateLimitLayer{data: make(map[string]RateLimitState), transactions: true}
+	config, err := capacitor.NewBuilder[string, RateLimitState]().WithLayer("shared", layer, 0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dal, err := capacitor.NewMultiLayer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := []RateLimitStore{NewDALRateLimitStore(dal), NewDALRateLimitStore(dal)}
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := range 40 {
+		wg.Go(func() {
+			err := stores[i%2].Update(context.Background(), "key", time.Minute, func(state *RateLimitState) {
+				if takeGCRA(state, rateLimitQuota{limit: 10, window: time.Minute}, time.Now().UnixNano()).allowed {
+					allowed.Add(1)
+				}
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if allowed.Load() != 10 {
+		t.Errorf("expected 10 allowed requests, got %d", allowed.Load())
+	}
+}
+
+// TestRateLimitStoreErrors tests failing open and closed.
+func TestRateLimitStoreErrors(t *testing.T) {
+	for _, failClosed := range []bool{false, true} {
+		handler := RateLimitWithConfig(RateLimitConfig{Store: failingStore{}, FailClosed: failClosed})(func(c *core.Context) error {
+			return c.NoContent()
+		})
+
+		ctx := &core.Context{}
+		err := handler(ctx)
+		if failClosed != (err != nil) || (err != nil && !errors.Is(err, capacitor.ErrConnectionFailed)) {
+			t.Errorf("fail closed %v: unexpected error %v", failClosed, err)
+		}
+		if !failClosed && ctx.StatusCode() != 204 {
+			t.Errorf("expected the request to pass, got %d", ctx.StatusCode())
+		}
+	}
+}
+
+// failingStore is a RateLimitStore that is always down.
+type failingStore struct{}
+
+func (failingStore) Update(ctx context.Context, key string, ttl time.Duration, update func(*RateLimitState)) error {
+	return capacitor.ErrConnectionFailed
+}
+
+// rateLimitLayer is a minimal capacitor.Layer for tests, shared by several
+// "instances". With transactions, a transaction holds the layer until it
+// ends (serializable).
+type rateLimitLayer struct {
+	mu           sync.Mutex
+	data         map[string]RateLimitState
+	transactions bool
+	txMu         sync.Mutex
+	commits      int
+}
+
+func (l *rateLimitLayer) Get(ctx context.Context, key string) (RateLimitState, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.data[key]
+	if !ok {
+		return v, capacitor.ErrNotFound
+	}
+	return v, nil
+}
+
+func (l *rateLimitLayer) Set(ctx context.Context, key string, value RateLimitState, opts ...capacitor.SetOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.data[key] = value
+	return nil
+}
+
+func (l *rateLimitLayer) Delete(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.data, key)
+	return nil
+}
+
+func (l *rateLimitLayer) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := l.Get(ctx, key)
+	return err == nil, nil
+}
+
+func (l *rateLimitLayer) Stats() capacitor.LayerStats { return capacitor.LayerStats{} }
+func (l *rateLimitLayer) Close() error                { return nil }
+
+func (l *rateLimitLayer) GetMulti(ctx context.Context, keys []string) (map[string]RateLimitState, map[string]error) {
+	return nil, nil
+}
+
+func (l *rateLimitLayer) SetMulti(ctx context.Context, items map[string]RateLimitState, opts ...capacitor.SetOption) map[string]error {
+	return nil
+}
+
+func (l *rateLimitLayer) DeleteMulti(ctx context.Context, keys []string) map[string]error { return nil }
+
+func (l *rateLimitLayer) Range(ctx context.Context, f func(key string, value RateLimitState) bool) error {
+	return capacitor.ErrIterationNotSupported
+}
+
+func (l *rateLimitLayer) BeginTx(ctx context.Context, opts ...capacitor.TxOption) (capacitor.Tx[string, RateLimitState], error) {
+	if !l.transactions {
+		return nil, capacitor.ErrTxNotSupported
+	}
+	l.txMu.Lock()
+	return &rateLimitTx{layer: l, writes: make(map[string]RateLimitState)}, nil
+}
+
+// rateLimitTx buffers writes until Commit.
+type rateLimitTx struct {
+	layer  *rateLimitLayer
+	writes map[string]RateLimitState
+	done   bool
+}
+
+func (tx *rateLimitTx) Get(ctx context.Context, key string) (RateLimitState, error) {
+	if v, ok := tx.writes[key]; ok {
+		return v, nil
+	}
+	return tx.layer.Get(ctx, key)
+}
+
+func (tx *rateLimitTx) Set(ctx context.Context, key string, value RateLimitState, opts ...capacitor.SetOption) error {
+	tx.writes[key] = value
+	return nil
+}
+
+func (tx *rateLimitTx) Delete(ctx context.Context, key string) error {
+	return capacitor.ErrTxNotSupported
+}
+
+func (tx *rateLimitTx) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := tx.Get(ctx, key)
+	return err == nil, nil
+}
+
+func (tx *rateLimitTx) GetMulti(ctx context.Context, keys []string) (map[string]RateLimitState, map[string]error) {
+	return nil, nil
+}
+
+func (tx *rateLimitTx) SetMulti(ctx context.Context, items map[string]RateLimitState, opts ...capacitor.SetOption) map[string]error {
+	return nil
+}
+
+func (tx *rateLimitTx) DeleteMulti(ctx context.Context, keys []string) map[string]error { return nil }
+
+func (tx *rateLimitTx) Commit(ctx context.Context) error {
+	if tx.done {
+		return capacitor.ErrClosed
+	}
+	for key, value := range tx.writes {
+		_ = tx.layer.Set(ctx, key, value)
+	}
+	tx.layer.mu.Lock()
+	tx.layer.commits++
+	tx.layer.mu.Unlock()
+	return tx.end()
+}
+
+func (tx *rateLimitTx) Rollback(ctx context.Context) error {
+	if tx.done {
+		return nil
+	}
+	return tx.end()
+}
+
+func (tx *rateLimitTx) end() error {
+	tx.done = true
+	tx.layer.txMu.Unlock()
+	return nil
+}
+
+func (tx *rateLimitTx) ID() string { return "tx" }
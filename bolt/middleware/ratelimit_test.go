@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -305,113 +306,220 @@ func TestDefaultRateLimitConfig(t *testing.T) {
 
 // TestTokenBucket tests token bucket algorithm.
 func TestTokenBucket(t *testing.T) {
-	tb := newTokenBucket(10.0, 5) // 10 req/s, burst of 5
+	quota := rateLimitQuota{limit: 5, window: 500 * time.Millisecond} // 10 req/s, burst of 5
+	var state RateLimitState
+	now := time.Now().UnixNano()
 
 	// Initial state: 5 tokens available
 	for i := 0; i < 5; i++ {
-		if !tb.allow() {
-			t.Errorf("request %d should be allowed (initial burst)", i+1)
+		if r := takeTokenBucket(&state, quota, now); !r.allowed || r.remaining != 4-i || r.retryAfter != 0 {
+			t.Errorf("request %d should be allowed (initial burst), got %+v", i+1, r)
 		}
 	}
 
-	// 6th request should fail (no tokens)
-	if tb.allow() {
-		t.Error("6th request should be denied (no tokens)")
+	// 6th request should fail (no tokens), one token refills in 0.1s
+	r := takeTokenBucket(&state, quota, now)
+	if r.allowed || r.retryAfter != 100*time.Millisecond || r.reset != 500*time.Millisecond {
+		t.Errorf("6th request should be denied (no tokens), got %+v", r)
 	}
 
 	// Wait for 1 token to refill (0.1s at 10 req/s)
-	time.Sleep(150 * time.Millisecond)
-
-	// Should have 1 token available
-	if !tb.allow() {
+	now += int64(150 * time.Millisecond)
+	if r := takeTokenBucket(&state, quota, now); !r.allowed {
 		t.Error("request should be allowed after refill")
 	}
 
-	// Next request should fail again
-	if tb.allow() {
-		t.Error("request should be denied (tokens exhausted)")
+	// Next request should fail again, the partial token is kept
+	if r := takeTokenBucket(&state, quota, now); r.allowed || r.retryAfter != 50*time.Millisecond {
+		t.Errorf("request should be denied (tokens exhausted), got %+v", r)
 	}
 }
 
-// TestTokenBucketRetryIn tests retry time calculation.
-func TestTokenBucketRetryIn(t *testing.T) {
-	tb := newTokenBucket(10.0, 1) // 10 req/s, burst of 1
+// TestGCRA tests the generic cell rate algorithm.
+func TestGCRA(t *testing.T) {
+	quota := rateLimitQuota{limit: 5, window: 500 * time.Millisecond}
+	var state RateLimitState
+	now := time.Now().UnixNano()
+
+	for i := 0; i < 5; i++ {
+		if r := takeGCRA(&state, quota, now); !r.allowed || r.remaining != 4-i {
+			t.Errorf("request %d should be allowed (initial burst), got %+v", i+1, r)
+		}
+	}
+	r := takeGCRA(&state, quota, now)
+	if r.allowed || r.retryAfter != 100*time.Millisecond || r.reset != 500*time.Millisecond {
+		t.Errorf("6th request should be denied, got %+v", r)
+	}
 
-	// Consume the token
-	tb.allow()
+	now += int64(100 * time.Millisecond)
+	if r := takeGCRA(&state, quota, now); !r.allowed || r.remaining != 0 {
+		t.Errorf("request should be allowed after one interval, got %+v", r)
+	}
+	if r := takeGCRA(&state, quota, now); r.allowed {
+		t.Error("request should be denied")
+	}
+}
 
-	// Calculate retry time
-	retryIn := tb.retryIn()
+// TestSlidingWindowLog tests the sliding window log algorithm.
+func TestSlidingWindowLog(t *testing.T) {
+	quota := rateLimitQuota{limit: 3, window: time.Second}
+	var state RateLimitState
+	now := time.Now().UnixNano()
 
-	// Should be approximately 0.1 seconds (1/10)
-	expectedMin := 90 * time.Millisecond
-	expectedMax := 110 * time.Millisecond
+	for i := 0; i < 3; i++ {
+		if r := takeSlidingWindowLog(&state, quota, now+int64(i)*int64(100*time.Millisecond)); !r.allowed || r.remaining != 2-i {
+			t.Errorf("request %d should be allowed, got %+v", i+1, r)
+		}
+	}
 
-	if retryIn < expectedMin || retryIn > expectedMax {
-		t.Errorf("expected retry time between %v and %v, got %v", expectedMin, expectedMax, retryIn)
+	// The oldest request leaves the window at now+1s
+	r := takeSlidingWindowLog(&state, quota, now+int64(500*time.Millisecond))
+	if r.allowed || r.retryAfter != 500*time.Millisecond || r.reset != 700*time.Millisecond {
+		t.Errorf("4th request should be denied, got %+v", r)
+	}
+	if r := takeSlidingWindowLog(&state, quota, now+int64(time.Second)); !r.allowed || len(state.Log) != 3 {
+		t.Errorf("request should be allowed once the oldest left, got %+v (log %d)", r, len(state.Log))
 	}
 }
 
-// TestLimiterStore tests limiter store management.
-func TestLimiterStore(t *testing.T) {
-	store := &limiterStore{
-		rate:            10.0,
-		burst:           5,
-		cleanupInterval: 100 * time.Millisecond,
-		maxAge:          200 * time.Millisecond,
+// TestSlidingWindowCounter tests the sliding window counter algorithm.
+func TestSlidingWindowCounter(t *testing.T) {
+	quota := rateLimitQuota{limit: 4, window: time.Second}
+	var state RateLimitState
+	start := time.Now().Truncate(time.Second).UnixNano()
+
+	for i := 0; i < 4; i++ {
+		if r := takeSlidingWindowCounter(&state, quota, start); !r.allowed || r.remaining != 3-i {
+			t.Errorf("request %d should be allowed, got %+v", i+1, r)
+		}
+	}
+
+	// Blocked by the current window: in the next one, 4 previous requests
+	// weigh 4 * 3/4 = 3 after a quarter second
+	r := takeSlidingWindowCounter(&state, quota, start+int64(200*time.Millisecond))
+	if r.allowed || r.retryAfter != 1050*time.Millisecond {
+		t.Errorf("5th request should be denied, got %+v", r)
+	}
+
+	// Half way through the next window: 4 * 1/2 = 2 weighed requests
+	now := start + int64(1500*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if r := takeSlidingWindowCounter(&state, quota, now); !r.allowed {
+			t.Errorf("request %d in the next window should be allowed, got %+v", i+1, r)
+		}
+	}
+	r = takeSlidingWindowCounter(&state, quota, now)
+	if r.allowed || r.retryAfter != 250*time.Millisecond || state.Previous != 4 || state.Count != 2 {
+		t.Errorf("request should be denied until the previous window weighs 1, got %+v %+v", r, state)
 	}
 
-	// Get limiter for key1
-	limiter1 := store.getLimiter("key1")
-	if limiter1 == nil {
-		t.Fatal("expected limiter to be created")
+	// Two windows later nothing is left
+	if r := takeSlidingWindowCounter(&state, quota, start+int64(3*time.Second)); !r.allowed || r.remaining != 3 {
+		t.Errorf("request should be allowed after two windows, got %+v", r)
 	}
+}
+
+// TestRateLimitHeaders tests the RateLimit-* and Retry-After headers.
+func TestRateLimitHeaders(t *testing.T) {
+	for _, algorithm := range []RateLimitAlgorithm{RateLimitTokenBucket, RateLimitSlidingWindowLog, RateLimitSlidingWindowCounter, RateLimitGCRA} {
+		handler := RateLimitWithConfig(RateLimitConfig{Limit: 2, Window: time.Minute, Algorithm: algorithm})(func(c *core.Context) error {
+			return c.NoContent()
+		})
+
+		for i, want := range []struct{ status, remaining string }{{"204", "1"}, {"204", "0"}, {"429", "0"}} {
+			ctx := &core.Context{}
+			_ = handler(ctx)
 
-	// Get same limiter again
-	limiter2 := store.getLimiter("key1")
-	if limiter1 != limiter2 {
-		t.Error("expected same limiter instance for same key")
+			got := []string{
+				fmt.Sprint(ctx.StatusCode()),
+				ctx.GetResponseHeader("RateLimit-Limit"),
+				ctx.GetResponseHeader("RateLimit-Policy"),
+				ctx.GetResponseHeader("RateLimit-Remaining"),
+			}
+			if fmt.Sprint(got) != fmt.Sprint([]string{want.status, "2", "2;w=60", want.remaining}) {
+				t.Errorf("algorithm %d, request %d: unexpected headers %q", algorithm, i+1, got)
+			}
+			if reset := ctx.GetResponseHeader("RateLimit-Reset"); reset == "" || reset == "0" {
+				t.Errorf("algorithm %d, request %d: expected RateLimit-Reset, got %q", algorithm, i+1, reset)
+			}
+			if retry := ctx.GetResponseHeader("Retry-After"); (retry != "") != (want.status == "429") {
+				t.Errorf("algorithm %d, request %d: unexpected Retry-After %q", algorithm, i+1, retry)
+			}
+		}
 	}
 
-	// Get limiter for different key
-	limiter3 := store.getLimiter("key2")
-	if limiter1 == limiter3 {
-		t.Error("expected different limiter for different key")
+	// DisableHeaders keeps Retry-After only
+	handler := RateLimitWithConfig(RateLimitConfig{Limit: 1, Window: time.Minute, DisableHeaders: true})(func(c *core.Context) error {
+		return c.NoContent()
+	})
+	_ = handler(&core.Context{})
+	ctx := &core.Context{}
+	_ = handler(ctx)
+	if ctx.GetResponseHeader("RateLimit-Limit") != "" || ctx.GetResponseHeader("Retry-After") != "60" {
+		t.Errorf("unexpected headers %q %q", ctx.GetResponseHeader("RateLimit-Limit"), ctx.GetResponseHeader("Retry-After"))
 	}
 }
 
-// TestDefaultKeyFunc tests default key function.
+// TestRateLimitInvalidAlgorithm tests that unknown algorithms panic.
+func TestRateLimitInvalidAlgorithm(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an unknown algorithm")
+		}
+	}()
+	RateLimitWithConfig(RateLimitConfig{Algorithm: 42})
+}
+
+// TestDefaultKeyFunc tests that the default key is the client IP, with
+// forwarding headers honored only from trusted proxies.
 func TestDefaultKeyFunc(t *testing.T) {
+	app := core.NewWithConfig(core.Config{TrustedProxies: []string{"10.0.0.0/8"}})
+	var key string
+	app.Get("/", func(c *core.Context) error {
+		key = defaultKeyFunc(c)
+		return c.NoContent()
+	})
+
 	testCases := []struct {
-		name     string
-		headers  map[string]string
-		expected string
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
 	}{
 		{
-			name:     "X-Forwarded-For",
-			headers:  map[string]string{"X-Forwarded-For": "192.168.1.1"},
-			expected: "192.168.1.1",
+			name:       "No headers",
+			remoteAddr: "203.0.113.7:1234",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "Spoofed X-Forwarded-For",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.1.1"},
+			expected:   "203.0.113.7",
 		},
 		{
-			name:     "X-Real-IP",
-			headers:  map[string]string{"X-Real-IP": "10.0.0.1"},
-			expected: "10.0.0.1",
+			name:       "X-Forwarded-For from trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.1.1"},
+			expected:   "192.168.1.1",
 		},
 		{
-			name:     "No headers",
-			headers:  map[string]string{},
-			expected: "default",
+			name:       "X-Real-IP from trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Real-IP": "10.1.2.3"},
+			expected:   "10.1.2.3",
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := &core.Context{}
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tc.remoteAddr
 			for k, v := range tc.headers {
-				ctx.SetRequestHeader(k, v)
+				req.Header.Set(k, v)
 			}
+			app.ServeHTTP(httptest.NewRecorder(), req)
 
-			key := defaultKeyFunc(ctx)
 			if key != tc.expected {
 				t.Errorf("expected key %s, got %s", tc.expected, key)
 			}
@@ -518,35 +626,3 @@ func TestLimiterStoreCleanup(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
-
-// TestTokenBucketRetryInWithTokens tests retryIn when tokens are available.
-func TestTokenBucketRetryInWithTokens(t *testing.T) {
-	tb := newTokenBucket(10.0, 5) // 10 req/s, burst of 5
-
-	// Don't consume any tokens - should have 5 available
-	retryIn := tb.retryIn()
-
-	// Should be 0 since we have tokens available
-	if retryIn != 0 {
-		t.Errorf("expected retry time 0 when tokens available, got %v", retryIn)
-	}
-}
-
-// TestTokenBucketRetryInPartialTokens tests retryIn with partial tokens.
-func TestTokenBucketRetryInPartialTokens(t *testing.T) {
-	tb := newTokenBucket(10.0, 1) // 10 req/s, burst of 1
-
-	// Consume the token
-	tb.allow()
-
-	// Immediately check retry time
-	retryIn := tb.retryIn()
-
-	// Should be approximately 0.1 seconds
-	expectedMin := 50 * time.Millisecond
-	expectedMax := 150 * time.Millisecond
-
-	if retryIn < expectedMin || retryIn > expectedMax {
-		t.Errorf("expected retry time between %v and %v, got %v", expectedMin, expectedMax, retryIn)
-	}
-}
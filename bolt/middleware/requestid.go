@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+
+	"github.com/yourusername/bolt/core"
+)
+
+// RequestID returns a middleware that gives every request an ID.
+//
+// The ID sent by the client (or a proxy) in X-Request-ID is kept if valid;
+// otherwise a random one is generated. Either way it is set on the response
+// header, where handlers and the Logger "request_id" field read it.
+//
+// Example:
+//
+//	app.Use(middleware.RequestID())
+//	app.Use(middleware.Logger())
+//
+//	app.Get("/", func(c *bolt.Context) error {
+//	    id := c.GetResponseHeader("X-Request-ID")
+//	    // ...
+//	})
+func RequestID() core.Middleware {
+	return RequestIDWithConfig(DefaultRequestIDConfig())
+}
+
+// RequestIDWithConfig returns a request ID middleware with custom configuration.
+//
+// Example:
+//
+//	app.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+//	    Header:    "X-Correlation-ID",
+//	    Generator: uuid.NewString,
+//	}))
+func RequestIDWithConfig(config RequestIDConfig) core.Middleware {
+	// Apply defaults
+	if config.Header == "" {
+		config.Header = "X-Request-ID"
+	}
+	header := []byte(config.Header)
+
+	return func(next core.Handler) core.Handler {
+		return func(c *core.Context) error {
+			if id := c.GetHeaderBytes(config.Header); validRequestID(id) {
+				c.SetHeaderBytes(header, id)
+			} else if config.Generator != nil {
+				c.SetHeader(config.Header, config.Generator())
+			} else {
+				var buf [32]byte
+				c.SetHeaderBytes(header, newRequestID(&buf))
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequestIDConfig defines configuration for request ID middleware.
+type RequestIDConfig struct {
+	// Header is the request and response header carrying the ID.
+	// Default: "X-Request-ID"
+	Header string
+
+	// Generator generates IDs for requests without a valid one.
+	// Default: 32 random hex digits
+	Generator func() string
+}
+
+// DefaultRequestIDConfig returns default request ID configuration.
+func DefaultRequestIDConfig() RequestIDConfig {
+	return RequestIDConfig{
+		Header: "X-Request-ID",
+	}
+}
+
+// newRequestID writes 128 random bits as hex into buf.
+func newRequestID(buf *[32]byte) []byte {
+	var raw [16]byte
+	binary.LittleEndian.PutUint64(raw[:8], rand.Uint64())
+	binary.LittleEndian.PutUint64(raw[8:], rand.Uint64())
+	hex.Encode(buf[:], raw[:])
+	return buf[:]
+}
+
+// validRequestID reports whether an incoming ID is safe to keep: 1-128
+// visible ASCII characters, so it can't inject into headers or logs.
+func validRequestID(id []byte) bool {
+	if len(id) == 0 || len(id) > 128 {
+		return false
+	}
+	for _, ch := range id {
+		if ch <= ' ' || ch > '~' {
+			return false
+		}
+	}
+	return true
+}
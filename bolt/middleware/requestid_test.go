@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/bolt/core"
+)
+
+// TestRequestID tests keeping valid incoming IDs and generating the rest.
+func TestRequestID(t *testing.T) {
+	app := core.New()
+	app.Use(RequestID())
+
+	var seen string
+	app.Get("/", func(c *core.Context) error {
+		seen = c.GetResponseHeader("X-Request-ID")
+		return c.NoContent()
+	})
+
+	serve := func(id string) string {
+		req := httptest.NewRequest("GET", "/", nil)
+		if id != "" {
+			req.Header.Set("X-Request-ID", id)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if got := w.Header().Get("X-Request-ID"); got != seen {
+			t.Errorf("handler saw %q, response has %q", seen, got)
+		}
+		return seen
+	}
+
+	if id := serve("req-42"); id != "req-42" {
+		t.Errorf("expected incoming ID to be kept, got %q", id)
+	}
+
+	generated := map[string]bool{}
+	for _, incoming := range []string{"", "has space", "tab\there", strings.Repeat("a", 129), "é"} {
+		id := serve(incoming)
+		if len(id) != 32 || strings.Trim(id, "0123456789abcdef") != "" {
+			t.Errorf("%q: expected 32 hex digits, got %q", incoming, id)
+		}
+		generated[id] = true
+	}
+	if len(generated) != 5 {
+		t.Errorf("expected distinct IDs, got %v", generated)
+	}
+}
+
+// TestRequestIDWithConfig tests custom header and generator.
+func TestRequestIDWithConfig(t *testing.T) {
+	app := core.New()
+	app.Use(RequestIDWithConfig(RequestIDConfig{
+		Header:    "X-Correlation-ID",
+		Generator: func() string { return "generated" },
+	}))
+	app.Get("/", func(c *core.Context) error { return c.NoContent() })
+
+	tests := []struct {
+		incoming string
+		want     string
+	}{
+		{"", "generated"},
+		{"abc-123", "abc-123"},
+		{strings.Repeat("x", 128), strings.Repeat("x", 128)},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.incoming != "" {
+			req.Header.Set("X-Correlation-ID", tt.incoming)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if got := w.Header().Get("X-Correlation-ID"); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.incoming, tt.want, got)
+		}
+		if w.Header().Get("X-Request-ID") != "" {
+			t.Error("expected no X-Request-ID header")
+		}
+	}
+}
+
+// TestRequestIDLogger tests that generated IDs reach the access log.
+func TestRequestIDLogger(t *testing.T) {
+	var logBuf bytes.Buffer
+	app := core.New()
+	app.Use(LoggerWithConfig(LoggerConfig{Output: &logBuf, Fields: []string{"request_id"}}))
+	app.Use(RequestID())
+	app.Get("/", func(c *core.Context) error { return c.NoContent() })
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	var entry map[string]any
+	if err := json.Unmarshal(logBuf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry %q: %v", logBuf.String(), err)
+	}
+	if id := w.Header().Get("X-Request-ID"); id == "" || entry["request_id"] != id {
+		t.Errorf("expected request_id %q, got %v", id, entry["request_id"])
+	}
+}
+
+// BenchmarkRequestID benchmarks ID generation.
+func BenchmarkRequestID(b *testing.B) {
+	handler := RequestID()(func(c *core.Context) error {
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx := &core.Context{}
+		_ = handler(ctx)
+	}
+}
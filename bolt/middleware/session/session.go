@@ -0,0 +1,463 @@
+// Package session provides session management middleware for Bolt.
+//
+// Sessions are stored server-side in any Store (a capacitor DAL, the
+// capacitor memory cache, ...) with only a random session ID in the cookie,
+// or client-side in an encrypted cookie (CookieStore). Handlers access the
+// session with c.Session().
+//
+// Example:
+//
+//	app.Use(session.Session())
+//
+//	app.Post("/cart", func(c *bolt.Context) error {
+//	    sess := c.Session()
+//	    items, _ := sess.Get("items").(int)
+//	    sess.Set("items", items+1)
+//	    sess.Flash("notice", "Added to cart")
+//	    return c.JSON(200, map[string]int{"items": items + 1})
+//	})
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+	"github.com/yourusername/bolt/core"
+)
+
+// SessionData is the stored state of a session.
+//
+// Values must be serializable by the store: with CookieStore (JSON), numbers
+// read back as float64 and structs as map[string]any.
+type SessionData struct {
+	Values     map[string]any   `json:"values,omitempty"`
+	Flashes    map[string][]any `json:"flashes,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	AccessedAt time.Time        `json:"accessed_at"`
+}
+
+// clone returns a copy of d whose maps can be changed without affecting d.
+func (d SessionData) clone() SessionData {
+	d.Values = maps.Clone(d.Values)
+	d.Flashes = maps.Clone(d.Flashes)
+	return d
+}
+
+// Session returns session middleware with default configuration
+// (server-side sessions in an in-memory capacitor cache).
+//
+// Example:
+//
+//	app.Use(session.Session())
+func Session() core.Middleware {
+	return SessionWithConfig(DefaultSessionConfig())
+}
+
+// SessionWithConfig returns session middleware with custom configuration.
+//
+// Example:
+//
+//	dal, _ := capacitor.NewMultiLayer(dalConfig) // e.g. memory + Redis layers
+//
+//	app.Use(session.SessionWithConfig(session.SessionConfig{
+//	    Store:           session.NewDALStore(dal),
+//	    CookieSecure:    true,
+//	    IdleTimeout:     15 * time.Minute,
+//	    AbsoluteTimeout: 8 * time.Hour,
+//	}))
+func SessionWithConfig(config SessionConfig) core.Middleware {
+	// Apply defaults
+	if config.Store == nil {
+		config.Store = NewMemoryStore(nil)
+	}
+	if config.CookieName == "" {
+		config.CookieName = "bolt_session"
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = "/"
+	}
+	if config.CookieSameSite == core.SameSiteDefaultMode {
+		config.CookieSameSite = core.SameSiteLaxMode
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = 30 * time.Minute
+	}
+	if config.AbsoluteTimeout == 0 {
+		config.AbsoluteTimeout = 24 * time.Hour
+	}
+
+	m := &manager{config: config}
+	m.cookieStore = config.Store == cookieStoreMarker
+
+	return func(next core.Handler) core.Handler {
+		return func(c *core.Context) error {
+			s, err := m.load(c)
+			if err != nil {
+				return err
+			}
+			c.Set(core.SessionKey, s)
+
+			err = next(c)
+			if saveErr := m.save(c, s); err == nil {
+				err = saveErr
+			}
+			return err
+		}
+	}
+}
+
+// SessionConfig defines the config for session middleware.
+type SessionConfig struct {
+	// Store holds server-side sessions. Use CookieStore() to keep sessions
+	// in an encrypted cookie instead (requires core.Config.CookieKeys).
+	// Default: NewMemoryStore with a 100k entry LRU capacitor memory cache
+	Store Store
+
+	// CookieName is the name of the session cookie.
+	// Default: "bolt_session"
+	CookieName string
+
+	// CookiePath and CookieDomain scope the session cookie.
+	// Default: "/" and "" (current host)
+	CookiePath   string
+	CookieDomain string
+
+	// CookieSecure sends the cookie over HTTPS only. Enable in production.
+	// Default: false
+	CookieSecure bool
+
+	// CookieSameSite sets the SameSite attribute.
+	// Default: core.SameSiteLaxMode
+	CookieSameSite core.SameSite
+
+	// IdleTimeout expires sessions not used for this long.
+	// Negative disables idle expiry.
+	// Default: 30 minutes
+	IdleTimeout time.Duration
+
+	// AbsoluteTimeout expires sessions this long after creation, however
+	// active they are. Negative disables absolute expiry.
+	// Default: 24 hours
+	AbsoluteTimeout time.Duration
+}
+
+// DefaultSessionConfig returns default session configuration.
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		Store:           NewMemoryStore(nil),
+		CookieName:      "bolt_session",
+		CookiePath:      "/",
+		CookieSameSite:  core.SameSiteLaxMode,
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 24 * time.Hour,
+	}
+}
+
+// manager loads and saves sessions for one middleware instance.
+type manager struct {
+	config      SessionConfig
+	cookieStore bool
+}
+
+// sessionIDLen is the length of an encoded session ID (32 random bytes).
+const sessionIDLen = 43
+
+// newSessionID returns a random, URL-safe session ID.
+func newSessionID() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// validSessionID reports whether id looks like a session ID, so garbage
+// cookies don't reach the store.
+func validSessionID(id string) bool {
+	if len(id) != sessionIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		b := id[i]
+		if !('a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '-' || b == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// expired reports whether data passed the idle or absolute timeout.
+func (m *manager) expired(data *SessionData, now time.Time) bool {
+	if m.config.IdleTimeout > 0 && now.Sub(data.AccessedAt) > m.config.IdleTimeout {
+		return true
+	}
+	return m.config.AbsoluteTimeout > 0 && now.Sub(data.CreatedAt) > m.config.AbsoluteTimeout
+}
+
+// touchInterval is how long a session can go unsaved after an access, so
+// reads don't write to the store on every request.
+func (m *manager) touchInterval() time.Duration {
+	if m.config.IdleTimeout > 0 {
+		return min(time.Minute, m.config.IdleTimeout/2)
+	}
+	return time.Minute
+}
+
+// ttl returns how long the store should keep data (0: no expiry).
+func (m *manager) ttl(data *SessionData, now time.Time) time.Duration {
+	var ttl time.Duration
+	if m.config.IdleTimeout > 0 {
+		ttl = m.config.IdleTimeout
+	}
+	if m.config.AbsoluteTimeout > 0 {
+		remaining := data.CreatedAt.Add(m.config.AbsoluteTimeout).Sub(now)
+		if ttl == 0 || remaining < ttl {
+			ttl = max(remaining, time.Second)
+		}
+	}
+	return ttl
+}
+
+// load returns the request's session, or a new one if the request has no
+// valid session.
+func (m *manager) load(c *core.Context) (*state, error) {
+	now := time.Now()
+	s := &state{c: c, m: m, isNew: true}
+
+	if m.cookieStore {
+		raw, err := c.EncryptedCookie(m.config.CookieName)
+		if err != nil && !errors.Is(err, core.ErrNoCookie) && !errors.Is(err, core.ErrInvalidCookie) {
+			// Missing core.Config.CookieKeys
+			return nil, fmt.Errorf("session: %w", err)
+		}
+		if err == nil && json.Unmarshal([]byte(raw), &s.data) == nil && !m.expired(&s.data, now) {
+			s.isNew = false
+			// The cookie holds the idle timer: refresh it before the handler
+			// writes the response
+			if now.Sub(s.data.AccessedAt) >= m.touchInterval() {
+				s.data.AccessedAt = now
+				s.writeDataCookie()
+			}
+		}
+	} else if id := c.Cookie(m.config.CookieName); validSessionID(id) {
+		data, err := m.config.Store.Get(c.Context(), id)
+		switch {
+		case err == nil && !m.expired(&data, now):
+			s.id, s.data, s.isNew = id, data.clone(), false
+		case err == nil:
+			_ = m.config.Store.Delete(c.Context(), id)
+		case !errors.Is(err, capacitor.ErrNotFound):
+			return nil, fmt.Errorf("session: load: %w", err)
+		}
+	}
+
+	if s.isNew {
+		s.data = SessionData{CreatedAt: now, AccessedAt: now}
+	}
+	return s, nil
+}
+
+// save persists a server-side session after the handler ran.
+// Cookie sessions are written as they change (see state.changed).
+func (m *manager) save(c *core.Context, s *state) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	if m.cookieStore || s.id == "" {
+		// Cookie session, or a new session that was never written to
+		return nil
+	}
+
+	now := time.Now()
+	if !s.dirty && now.Sub(s.data.AccessedAt) < m.touchInterval() {
+		return nil
+	}
+	s.data.AccessedAt = now
+	if err := m.config.Store.Set(c.Context(), s.id, s.data.clone(), m.ttl(&s.data, now)); err != nil {
+		return fmt.Errorf("session: save: %w", err)
+	}
+	return nil
+}
+
+// cookie returns the session cookie carrying value.
+func (m *manager) cookie(value string, data *SessionData) *core.Cookie {
+	cookie := &core.Cookie{
+		Name:     m.config.CookieName,
+		Value:    value,
+		Path:     m.config.CookiePath,
+		Domain:   m.config.CookieDomain,
+		Secure:   m.config.CookieSecure,
+		HttpOnly: true,
+		SameSite: m.config.CookieSameSite,
+	}
+	if m.config.AbsoluteTimeout > 0 {
+		remaining := data.CreatedAt.Add(m.config.AbsoluteTimeout).Sub(time.Now())
+		cookie.MaxAge = max(int(remaining/time.Second), 1)
+	}
+	return cookie
+}
+
+// state is the core.Session implementation.
+type state struct {
+	c *core.Context
+	m *manager
+
+	mu    sync.Mutex
+	id    string // "" until a new server-side session is first written to
+	data  SessionData
+	isNew bool  // Not loaded from the store/cookie
+	dirty bool  // Data changed since load
+	err   error // First error writing the session, returned by the middleware
+}
+
+var _ core.Session = (*state)(nil)
+
+// ID returns the session ID.
+func (s *state) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// Get returns a session value.
+func (s *state) Get(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Values[key]
+}
+
+// Set stores a session value.
+func (s *state) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Values == nil {
+		s.data.Values = make(map[string]any)
+	}
+	s.data.Values[key] = value
+	s.changed()
+}
+
+// Delete removes a session value.
+func (s *state) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Values[key]; ok {
+		delete(s.data.Values, key)
+		s.changed()
+	}
+}
+
+// Flash stores a value read once by Flashes.
+func (s *state) Flash(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Flashes == nil {
+		s.data.Flashes = make(map[string][]any)
+	}
+	s.data.Flashes[key] = append(s.data.Flashes[key], value)
+	s.changed()
+}
+
+// Flashes returns and removes the flash values stored under key.
+func (s *state) Flashes(key string) []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values, ok := s.data.Flashes[key]
+	if ok {
+		delete(s.data.Flashes, key)
+		s.changed()
+	}
+	return values
+}
+
+// Regenerate issues a new session ID and keeps the data.
+func (s *state) Regenerate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.m.cookieStore {
+		// No server-side ID: re-encrypting the cookie is all there is to rotate
+		s.changed()
+		return s.err
+	}
+
+	if s.id != "" {
+		if err := s.m.config.Store.Delete(s.c.Context(), s.id); err != nil {
+			return fmt.Errorf("session: regenerate: %w", err)
+		}
+		s.id = ""
+	}
+	s.changed()
+	return s.err
+}
+
+// Destroy deletes the session and clears the session cookie.
+func (s *state) Destroy() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.id != "" {
+		if err := s.m.config.Store.Delete(s.c.Context(), s.id); err != nil {
+			return fmt.Errorf("session: destroy: %w", err)
+		}
+	}
+	if s.id != "" || s.dirty || s.c.HasCookie(s.m.config.CookieName) {
+		s.c.ClearCookie(s.m.config.CookieName, s.m.config.CookiePath, s.m.config.CookieDomain)
+	}
+
+	// Later writes start a fresh session
+	now := time.Now()
+	s.id = ""
+	s.data = SessionData{CreatedAt: now, AccessedAt: now}
+	s.isNew = true
+	s.dirty = false
+	return nil
+}
+
+// changed records a change, issuing the session cookie if needed.
+// s.mu must be held.
+func (s *state) changed() {
+	s.dirty = true
+	if s.m.cookieStore {
+		s.writeDataCookie()
+		return
+	}
+	if s.id == "" {
+		id, err := newSessionID()
+		if err != nil {
+			s.setErr(err)
+			return
+		}
+		s.id = id
+		s.c.SetCookie(s.m.cookie(id, &s.data))
+	}
+}
+
+// writeDataCookie stores the session data in the encrypted cookie.
+func (s *state) writeDataCookie() {
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		s.setErr(err)
+		return
+	}
+	if err := s.c.SetEncryptedCookie(s.m.cookie(string(data), &s.data)); err != nil {
+		s.setErr(err)
+	}
+}
+
+// setErr records the first error writing the session.
+func (s *state) setErr(err error) {
+	if s.err == nil {
+		s.err = fmt.Errorf("session: %w", err)
+	}
+}
@@ -0,0 +1,293 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+	"github.com/yourusername/bolt/core"
+)
+
+// newSessionApp returns an app with session middleware and test routes.
+func newSessionApp(appConfig core.Config, config SessionConfig) *core.App {
+	app := core.NewWithConfig(appConfig)
+	app.Use(SessionWithConfig(config))
+
+	app.Get("/get", func(c *core.Context) error {
+		sess := c.Session()
+		return c.JSON(200, map[string]any{"id": sess.ID(), "user": sess.Get("user"), "flashes": sess.Flashes("notice")})
+	})
+	app.Post("/set", func(c *core.Context) error {
+		sess := c.Session()
+		sess.Set("user", c.Query("user"))
+		sess.Flash("notice", "saved")
+		return c.JSON(200, map[string]string{"id": sess.ID()})
+	})
+	app.Post("/login", func(c *core.Context) error {
+		sess := c.Session()
+		if err := sess.Regenerate(); err != nil {
+			return err
+		}
+		sess.Set("role", "admin")
+		return c.JSON(200, map[string]string{"id": sess.ID()})
+	})
+	app.Post("/logout", func(c *core.Context) error {
+		if err := c.Session().Destroy(); err != nil {
+			return err
+		}
+		return c.JSON(200, map[string]string{})
+	})
+	return app
+}
+
+// sessionClient sends requests with a cookie jar.
+type sessionClient struct {
+	t      *testing.T
+	url    string
+	client *http.Client
+}
+
+func newSessionClient(t *testing.T, app *core.App) *sessionClient {
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+	jar, _ := cookiejar.New(nil)
+	return &sessionClient{t: t, url: server.URL, client: &http.Client{Jar: jar}}
+}
+
+func (sc *sessionClient) do(method, path string) (string, *http.Response) {
+	sc.t.Helper()
+	req, _ := http.NewRequest(method, sc.url+path, nil)
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		sc.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp
+}
+
+// cookie returns the session cookie held by the client ("" if none).
+func (sc *sessionClient) cookie() string {
+	req, _ := http.NewRequest("GET", sc.url, nil)
+	for _, ck := range sc.client.Jar.Cookies(req.URL) {
+		if ck.Name == "bolt_session" {
+			return ck.Value
+		}
+	}
+	return ""
+}
+
+// testSessionFlow runs the common session scenario against a store.
+func testSessionFlow(t *testing.T, appConfig core.Config, config SessionConfig) {
+	sc := newSessionClient(t, newSessionApp(appConfig, config))
+	cookieStore := config.Store == cookieStoreMarker
+
+	// Reading doesn't create a session
+	if body, resp := sc.do("GET", "/get"); len(resp.Cookies()) != 0 || !strings.Contains(body, `"user":null`) {
+		t.Fatalf("unexpected new session %q (cookies %v)", body, resp.Cookies())
+	}
+
+	sc.do("POST", "/set?user=ada")
+	first := sc.cookie()
+	if first == "" {
+		t.Fatal("expected a session cookie")
+	}
+	if cookieStore && strings.Contains(first, "ada") {
+		t.Error("cookie session leaks its data")
+	}
+
+	// Values persist, flashes are read once
+	if body, _ := sc.do("GET", "/get"); !strings.Contains(body, `"user":"ada"`) || !strings.Contains(body, `"flashes":["saved"]`) {
+		t.Errorf("unexpected session %q", body)
+	}
+	if body, _ := sc.do("GET", "/get"); !strings.Contains(body, `"flashes":null`) {
+		t.Errorf("expected flashes to be consumed, got %q", body)
+	}
+
+	// Regenerate rotates the ID and keeps the data
+	sc.do("POST", "/login")
+	second := sc.cookie()
+	if second == first {
+		t.Error("expected a new session cookie after Regenerate")
+	}
+	if body, _ := sc.do("GET", "/get"); !strings.Contains(body, `"user":"ada"`) {
+		t.Errorf("expected data to survive Regenerate, got %q", body)
+	}
+	if !cookieStore {
+		req, _ := http.NewRequest("GET", sc.url+"/get", nil)
+		req.AddCookie(&http.Cookie{Name: "bolt_session", Value: first})
+		resp, _ := http.DefaultClient.Do(req)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), `"user":null`) {
+			t.Errorf("expected the old session ID to be invalid, got %q", body)
+		}
+	}
+
+	// Destroy clears the cookie and the data
+	if _, resp := sc.do("POST", "/logout"); len(resp.Cookies()) != 1 || resp.Cookies()[0].MaxAge >= 0 {
+		t.Errorf("expected the session cookie to be cleared, got %v", resp.Cookies())
+	}
+	if body, _ := sc.do("GET", "/get"); !strings.Contains(body, `"user":null`) || sc.cookie() != "" {
+		t.Errorf("expected no session after Destroy, got %q", body)
+	}
+}
+
+// TestSessionMemoryStore tests server-side sessions in the capacitor memory cache.
+func TestSessionMemoryStore(t *testing.T) {
+	testSessionFlow(t, core.DefaultConfig(), SessionConfig{})
+}
+
+// TestSessionDALStore tests server-side sessions in a capacitor multi-layer DAL.
+func TestSessionDALStore(t *testing.T) {
+	l1, l2 := newMapLayer(), newMapLayer()
+	config, err := capacitor.NewBuilder[string, SessionData]().
+		WithLayer("L1", l1, time.Minute).
+		WithLayer("L2", l2, 0).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dal, err := capacitor.NewMultiLayer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testSessionFlow(t, core.DefaultConfig(), SessionConfig{Store: NewDALStore(dal)})
+
+	// Sessions were written through to both layers, and deleted from both
+	l2.mu.Lock()
+	defer l2.mu.Unlock()
+	if l2.sets == 0 || len(l2.data) != 0 {
+		t.Errorf("expected writes to reach L2 and sessions to be deleted (sets %d, left %d)", l2.sets, len(l2.data))
+	}
+}
+
+// TestSessionCookieStore tests sessions kept in an encrypted cookie.
+func TestSessionCookieStore(t *testing.T) {
+	appConfig := core.DefaultConfig()
+	appConfig.CookieKeys = [][]byte{bytes.Repeat([]byte("k"), 32)}
+	testSessionFlow(t, appConfig, SessionConfig{Store: CookieStore()})
+
+	// Without keys, the middleware fails before running the handler
+	sc := newSessionClient(t, newSessionApp(core.DefaultConfig(), SessionConfig{Store: CookieStore()}))
+	if _, resp := sc.do("POST", "/set?user=ada"); resp.StatusCode != 500 {
+		t.Errorf("expected 500 without cookie keys, got %d", resp.StatusCode)
+	}
+}
+
+// TestSessionExpiry tests idle and absolute timeouts.
+func TestSessionExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SessionConfig
+	}{
+		{"idle", SessionConfig{IdleTimeout: 50 * time.Millisecond}},
+		{"absolute", SessionConfig{IdleTimeout: -1, AbsoluteTimeout: 50 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := newSessionClient(t, newSessionApp(core.DefaultConfig(), tt.config))
+			sc.do("POST", "/set?user=ada")
+			id := sc.cookie()
+
+			if body, _ := sc.do("GET", "/get"); !strings.Contains(body, `"user":"ada"`) {
+				t.Fatalf("expected a live session, got %q", body)
+			}
+			time.Sleep(80 * time.Millisecond)
+
+			// The browser may still send the cookie; the server must ignore it
+			req, _ := http.NewRequest("GET", sc.url+"/get", nil)
+			req.AddCookie(&http.Cookie{Name: "bolt_session", Value: id})
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if !strings.Contains(string(body), `"user":null`) {
+				t.Errorf("expected an expired session, got %q", body)
+			}
+		})
+	}
+}
+
+// TestSessionWithoutMiddleware tests the Context.Session panic.
+func TestSessionWithoutMiddleware(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic without session middleware")
+		}
+	}()
+	(&core.Context{}).Session()
+}
+
+// mapLayer is a minimal capacitor.Layer for tests.
+type mapLayer struct {
+	mu   sync.Mutex
+	data map[string]SessionData
+	sets int
+}
+
+func newMapLayer() *mapLayer {
+	return &mapLayer{data: make(map[string]SessionData)}
+}
+
+func (l *mapLayer) Get(ctx context.Context, key string) (SessionData, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.data[key]
+	if !ok {
+		return v, capacitor.ErrNotFound
+	}
+	return v, nil
+}
+
+func (l *mapLayer) Set(ctx context.Context, key string, value SessionData, opts ...capacitor.SetOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.data[key] = value
+	l.sets++
+	return nil
+}
+
+func (l *mapLayer) Delete(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.data, key)
+	return nil
+}
+
+func (l *mapLayer) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := l.Get(ctx, key)
+	return err == nil, nil
+}
+
+func (l *mapLayer) Stats() capacitor.LayerStats { return capacitor.LayerStats{} }
+func (l *mapLayer) Close() error                { return nil }
+
+func (l *mapLayer) GetMulti(ctx context.Context, keys []string) (map[string]SessionData, map[string]error) {
+	return nil, nil
+}
+
+func (l *mapLayer) SetMulti(ctx context.Context, items map[string]SessionData, opts ...capacitor.SetOption) map[string]error {
+	return nil
+}
+
+func (l *mapLayer) DeleteMulti(ctx context.Context, keys []string) map[string]error { return nil }
+
+func (l *mapLayer) Range(ctx context.Context, f func(key string, value SessionData) bool) error {
+	return capacitor.ErrIterationNotSupported
+}
+
+func (l *mapLayer) BeginTx(ctx context.Context, opts ...capacitor.TxOption) (capacitor.Tx[string, SessionData], error) {
+	return nil, capacitor.ErrTxNotSupported
+}
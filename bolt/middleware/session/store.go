@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/watt-toolkit/capacitor/pkg/cache/memory"
+	"github.com/watt-toolkit/capacitor/pkg/capacitor"
+)
+
+// Store holds server-side sessions by ID.
+//
+// Implementations must be safe for concurrent use and must not share the
+// maps of stored SessionData with callers (the middleware already copies
+// them on Get and Set).
+type Store interface {
+	// Get returns the session with the given ID.
+	// Returns capacitor.ErrNotFound if there is none.
+	Get(ctx context.Context, id string) (SessionData, error)
+
+	// Set stores a session, expiring it after ttl (0: no expiry).
+	Set(ctx context.Context, id string, data SessionData, ttl time.Duration) error
+
+	// Delete removes a session. Deleting a missing session is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewDALStore returns a Store backed by a capacitor DAL, e.g. a multi-layer
+// stack of an in-memory cache over Redis or a database.
+//
+// Example:
+//
+//	config, err := capacitor.NewBuilder[string, session.SessionData]().
+//	    WithLayer("memory", l1, time.Minute).
+//	    WithLayer("redis", l2, 0).
+//	    Build()
+//	dal, err := capacitor.NewMultiLayer(config)
+//
+//	app.Use(session.SessionWithConfig(session.SessionConfig{
+//	    Store: session.NewDALStore(dal),
+//	}))
+func NewDALStore(dal capacitor.DAL[string, SessionData]) Store {
+	return dalStore{dal: dal}
+}
+
+// dalStore adapts a capacitor DAL to Store.
+type dalStore struct {
+	dal capacitor.DAL[string, SessionData]
+}
+
+func (s dalStore) Get(ctx context.Context, id string) (SessionData, error) {
+	return s.dal.Get(ctx, id)
+}
+
+func (s dalStore) Set(ctx context.Context, id string, data SessionData, ttl time.Duration) error {
+	if ttl > 0 {
+		return s.dal.Set(ctx, id, data, capacitor.WithTTL(int64(ttl)))
+	}
+	return s.dal.Set(ctx, id, data)
+}
+
+func (s dalStore) Delete(ctx context.Context, id string) error {
+	return s.dal.Delete(ctx, id)
+}
+
+// NewMemoryStore returns a Store backed by a capacitor in-memory cache.
+// Sessions are lost on restart and not shared between instances; use
+// NewDALStore for that.
+//
+// A nil cache creates one holding up to 100k sessions (LRU eviction).
+//
+// Example:
+//
+//	cache := memory.New[string, session.SessionData](memory.Config{
+//	    MaxSize:      1_000_000,
+//	    EvictionMode: memory.EvictionLRU,
+//	})
+//	store := session.NewMemoryStore(cache)
+func NewMemoryStore(cache *memory.Cache[string, SessionData]) Store {
+	if cache == nil {
+		cache = memory.New[string, SessionData](memory.Config{
+			MaxSize:         100_000,
+			EvictionMode:    memory.EvictionLRU,
+			CleanupInterval: time.Minute,
+		})
+	}
+	return memoryStore{cache: cache}
+}
+
+// memoryStore adapts a capacitor memory cache to Store.
+type memoryStore struct {
+	cache *memory.Cache[string, SessionData]
+}
+
+func (s memoryStore) Get(ctx context.Context, id string) (SessionData, error) {
+	return s.cache.Get(ctx, id)
+}
+
+func (s memoryStore) Set(ctx context.Context, id string, data SessionData, ttl time.Duration) error {
+	return s.cache.Set(ctx, id, data, memory.WithTTL(ttl))
+}
+
+func (s memoryStore) Delete(ctx context.Context, id string) error {
+	return s.cache.Delete(ctx, id)
+}
+
+// CookieStore returns a Store marker that keeps whole sessions in an
+// encrypted cookie (AES-256-GCM, see core.Context.SetEncryptedCookie)
+// instead of server-side.
+//
+// Nothing is stored on the server, so sessions survive restarts and work
+// across instances, but session data is limited to ~4KB and a destroyed
+// session can't be revoked before it expires. Requires core.Config.CookieKeys.
+//
+// Example:
+//
+//	app := bolt.NewWithConfig(bolt.Config{CookieKeys: [][]byte{key}})
+//	app.Use(session.SessionWithConfig(session.SessionConfig{
+//	    Store: session.CookieStore(),
+//	}))
+func CookieStore() Store {
+	return cookieStoreMarker
+}
+
+// cookieStoreMarker is the Store returned by CookieStore. The middleware
+// reads and writes the session cookie itself; these methods are never used.
+var cookieStoreMarker Store = &cookieStore{}
+
+type cookieStore struct{}
+
+func (*cookieStore) Get(ctx context.Context, id string) (SessionData, error) {
+	return SessionData{}, capacitor.ErrNotFound
+}
+
+func (*cookieStore) Set(ctx context.Context, id string, data SessionData, ttl time.Duration) error {
+	return nil
+}
+
+func (*cookieStore) Delete(ctx context.Context, id string) error {
+	return nil
+}
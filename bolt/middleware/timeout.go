@@ -14,17 +14,24 @@ var ErrRequestTimeout = errors.New("request timeout")
 // Timeout returns a middleware that cancels requests exceeding the specified duration.
 //
 // If a request takes longer than the timeout:
-//   - Context is cancelled
+//   - Request context (c.Context()) is cancelled
 //   - Handler receives context cancellation error
-//   - Returns 408 Request Timeout
-//   - Resources are cleaned up properly
+//   - Returns 408 Request Timeout once the handler returns, unless the
+//     handler already wrote a response
+//
+// Handlers run on the request goroutine, so they must watch c.Context()
+// to stop early.
 //
 // Example:
 //
 //	app := bolt.New()
 //	app.Use(Timeout(5 * time.Second))
 //	app.Get("/slow", func(c *bolt.Context) error {
-//	    time.Sleep(10 * time.Second) // Will timeout after 5s
+//	    select {
+//	    case <-time.After(10 * time.Second):
+//	    case <-c.Context().Done(): // Will timeout after 5s
+//	        return c.Context().Err()
+//	    }
 //	    return c.JSON(200, map[string]string{"status": "ok"})
 //	})
 //
@@ -67,37 +74,34 @@ func TimeoutWithConfig(config TimeoutConfig) core.Middleware {
 				return next(c)
 			}
 
-			// Create context with timeout
-			ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+			// Derive deadline from the request context so handlers observe it
+			// (also cancelled on client disconnect or shutdown)
+			parent := c.Context()
+			ctx, cancel := context.WithTimeout(parent, config.Timeout)
 			defer cancel()
 
-			// Channel to receive handler result
-			done := make(chan error, 1)
-
-			// Execute handler in goroutine
-			go func() {
-				done <- next(c)
-			}()
+			// Run the handler inline: the Context goes back to the pool once
+			// this returns, so no goroutine may still be using it
+			c.WithContext(ctx)
+			err := next(c)
+			c.WithContext(parent)
 
-			// Wait for completion or timeout
-			select {
-			case err := <-done:
-				// Handler completed in time
+			// Late responses have already gone out; leave them alone
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) || c.Written() {
 				return err
+			}
 
-			case <-ctx.Done():
-				// Timeout occurred
-				if config.Handler != nil {
-					// Use custom timeout handler
-					return config.Handler(c)
-				}
-
-				// Default timeout response
-				return c.JSON(408, map[string]interface{}{
-					"error":   "Request timeout",
-					"timeout": config.Timeout.String(),
-				})
+			// Timeout occurred
+			if config.Handler != nil {
+				// Use custom timeout handler
+				return config.Handler(c)
 			}
+
+			// Default timeout response
+			return c.JSON(408, map[string]interface{}{
+				"error":   "Request timeout",
+				"timeout": config.Timeout.String(),
+			})
 		}
 	}
 }
@@ -13,7 +13,11 @@ func TestTimeout(t *testing.T) {
 
 	handler := middleware(func(c *core.Context) error {
 		// Sleep longer than timeout
-		time.Sleep(200 * time.Millisecond)
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-c.Context().Done():
+			return c.Context().Err()
+		}
 		return c.JSON(200, map[string]string{"status": "ok"})
 	})
 
@@ -71,7 +75,11 @@ func TestTimeoutSkipPaths(t *testing.T) {
 
 	handler := middleware(func(c *core.Context) error {
 		// Sleep longer than timeout
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-c.Context().Done():
+			return c.Context().Err()
+		}
 		return c.JSON(200, map[string]string{"status": "ok"})
 	})
 
@@ -129,6 +137,33 @@ func TestTimeoutWithCustomHandler(t *testing.T) {
 	}
 }
 
+// TestTimeoutWaitsForHandler tests that a handler ignoring the deadline has
+// finished with the Context before the middleware returns it to the pool.
+func TestTimeoutWaitsForHandler(t *testing.T) {
+	middleware := Timeout(20 * time.Millisecond)
+
+	finished := false
+	handler := middleware(func(c *core.Context) error {
+		time.Sleep(60 * time.Millisecond)
+		c.SetHeader("X-Late", "1")
+		finished = true
+		return nil
+	})
+
+	ctx := &core.Context{}
+	ctx.SetMethod("GET")
+	ctx.SetPath("/slow")
+
+	_ = handler(ctx)
+
+	if !finished {
+		t.Error("expected handler to finish before the middleware returns")
+	}
+	if ctx.StatusCode() != 408 {
+		t.Errorf("expected status 408, got %d", ctx.StatusCode())
+	}
+}
+
 // TestTimeoutHandlerError tests timeout when handler returns error.
 func TestTimeoutHandlerError(t *testing.T) {
 	middleware := Timeout(200 * time.Millisecond)
@@ -270,3 +305,34 @@ func BenchmarkTimeoutSkipPath(b *testing.B) {
 		_ = handler(ctx)
 	}
 }
+
+// TestTimeoutContextDeadline tests that handlers observe the timeout through c.Context().
+func TestTimeoutContextDeadline(t *testing.T) {
+	middleware := Timeout(50 * time.Millisecond)
+
+	cancelled := make(chan struct{})
+	handler := middleware(func(c *core.Context) error {
+		if _, ok := c.Context().Deadline(); !ok {
+			t.Error("expected request context to carry a deadline")
+		}
+		<-c.Context().Done()
+		close(cancelled)
+		return c.Context().Err()
+	})
+
+	ctx := &core.Context{}
+	ctx.SetMethod("GET")
+	ctx.SetPath("/db")
+
+	_ = handler(ctx)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected handler to observe context cancellation")
+	}
+
+	if ctx.StatusCode() != 408 {
+		t.Errorf("expected status 408, got %d", ctx.StatusCode())
+	}
+}
@@ -0,0 +1,401 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// Exporter receives sampled spans when their request ends.
+//
+// ExportSpan is called on the request path: implementations must not block
+// (queue spans and send them in the background). The span must not be
+// modified after export.
+type Exporter interface {
+	ExportSpan(span *Span)
+}
+
+// MemoryExporter keeps exported spans in memory, for tests.
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// NewMemoryExporter returns an empty MemoryExporter.
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpan records span.
+func (e *MemoryExporter) ExportSpan(span *Span) {
+	e.mu.Lock()
+	e.spans = append(e.spans, span)
+	e.mu.Unlock()
+}
+
+// Spans returns the spans exported so far, oldest first.
+func (e *MemoryExporter) Spans() []*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*Span(nil), e.spans...)
+}
+
+// Reset removes all spans.
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	e.spans = nil
+	e.mu.Unlock()
+}
+
+// ErrExporterClosed is returned by Flush and Shutdown after Shutdown.
+var ErrExporterClosed = errors.New("tracing: exporter closed")
+
+// OTLPExporter sends spans to an OpenTelemetry collector with OTLP over
+// HTTP, JSON encoded.
+//
+// Spans are queued and sent in batches from a background goroutine. When
+// the queue is full, spans are dropped (see Dropped). Call Shutdown to send
+// the queued spans before exiting.
+//
+// Example:
+//
+//	exporter := tracing.NewOTLPExporter(tracing.OTLPConfig{
+//	    Endpoint:    "http://otel-collector:4318/v1/traces",
+//	    ServiceName: "orders",
+//	})
+//	defer exporter.Shutdown(context.Background())
+type OTLPExporter struct {
+	config   OTLPConfig
+	resource otlpResource
+
+	queue   chan *Span
+	flush   chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// OTLPConfig defines configuration for the OTLP exporter.
+type OTLPConfig struct {
+	// Endpoint is the collector's OTLP/HTTP traces URL.
+	// Default: "http://localhost:4318/v1/traces"
+	Endpoint string
+
+	// Headers are sent with every export (e.g. authentication).
+	Headers map[string]string
+
+	// ServiceName identifies this service (service.name resource attribute).
+	// Default: "bolt"
+	ServiceName string
+
+	// ResourceAttributes describe this service further
+	// (e.g. deployment.environment.name, service.version).
+	ResourceAttributes []Attribute
+
+	// BatchSize is the maximum number of spans per export.
+	// Default: 512
+	BatchSize int
+
+	// QueueSize is the maximum number of queued spans.
+	// Default: 2048
+	QueueSize int
+
+	// FlushInterval is the maximum time a span is queued.
+	// Default: 5 seconds
+	FlushInterval time.Duration
+
+	// Client sends the exports.
+	// Default: an http.Client with a 10 second timeout
+	Client *http.Client
+}
+
+// NewOTLPExporter returns an OTLPExporter and starts its sender goroutine.
+func NewOTLPExporter(config OTLPConfig) *OTLPExporter {
+	// Apply defaults
+	if config.Endpoint == "" {
+		config.Endpoint = "http://localhost:4318/v1/traces"
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = "bolt"
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 512
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 2048
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	attributes := append([]Attribute{{Key: "service.name", Value: config.ServiceName}}, config.ResourceAttributes...)
+	e := &OTLPExporter{
+		config:   config,
+		resource: otlpResource{Attributes: otlpAttributes(attributes)},
+		queue:    make(chan *Span, config.QueueSize),
+		flush:    make(chan chan error),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	// Start sender goroutine
+	go e.run()
+
+	return e
+}
+
+// ExportSpan queues span, or drops it if the queue is full.
+func (e *OTLPExporter) ExportSpan(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of spans dropped because the queue was full.
+func (e *OTLPExporter) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+// Flush sends the queued spans and returns the export error, if any.
+func (e *OTLPExporter) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case e.flush <- reply:
+	case <-e.stopped:
+		return ErrExporterClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown sends the queued spans and stops the exporter. Spans exported
+// afterwards are dropped.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	err := e.Flush(ctx)
+	e.once.Do(func() { close(e.done) })
+	select {
+	case <-e.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
+
+// run batches queued spans until Shutdown.
+func (e *OTLPExporter) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(e.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, e.config.BatchSize)
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := e.send(batch)
+		clear(batch)
+		batch = batch[:0]
+		return err
+	}
+	// drain moves queued spans to the batch, sending full batches
+	drain := func() error {
+		var err error
+		for {
+			select {
+			case span := <-e.queue:
+				batch = append(batch, span)
+				if len(batch) == cap(batch) {
+					err = errors.Join(err, send())
+				}
+			default:
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) < cap(batch) {
+				continue
+			}
+		case <-ticker.C:
+		case reply := <-e.flush:
+			reply <- errors.Join(drain(), send())
+			continue
+		case <-e.done:
+			_ = drain()
+			_ = send()
+			return
+		}
+		if err := send(); err != nil {
+			log.Printf("Failed to export spans: %v", err)
+		}
+	}
+}
+
+// send exports a batch.
+func (e *OTLPExporter) send(batch []*Span) error {
+	spans := make([]otlpSpan, len(batch))
+	for i, span := range batch {
+		spans[i] = newOTLPSpan(span)
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: e.resource,
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/yourusername/bolt/middleware/tracing"},
+			Spans: spans,
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("tracing: encode spans: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", e.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("tracing: export spans: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tracing: export spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("tracing: export spans: %s", resp.Status)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON encoding (opentelemetry-proto, trace/v1). IDs are hex,
+// 64-bit integers are strings.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	TraceState        string         `json:"traceState,omitempty"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    SpanStatus `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// otlpSpanKindServer is SPAN_KIND_SERVER.
+const otlpSpanKindServer = 2
+
+func newOTLPSpan(span *Span) otlpSpan {
+	s := otlpSpan{
+		TraceID:           span.TraceID.String(),
+		SpanID:            span.SpanID.String(),
+		TraceState:        span.TraceState,
+		Name:              span.Name,
+		Kind:              otlpSpanKindServer,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Attributes:        otlpAttributes(span.Attributes),
+		Status:            otlpStatus{Code: span.Status, Message: span.StatusMessage},
+	}
+	if span.Parent.IsValid() {
+		s.ParentSpanID = span.Parent.String()
+	}
+	return s
+}
+
+// otlpAttributes converts attributes. Values of other types are sent as
+// strings (fmt %v).
+func otlpAttributes(attributes []Attribute) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, len(attributes))
+	for i, attr := range attributes {
+		kvs[i].Key = attr.Key
+		switch v := attr.Value.(type) {
+		case string:
+			kvs[i].Value.StringValue = &v
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			kvs[i].Value.IntValue = &s
+		case int:
+			s := strconv.Itoa(v)
+			kvs[i].Value.IntValue = &s
+		case float64:
+			kvs[i].Value.DoubleValue = &v
+		case bool:
+			kvs[i].Value.BoolValue = &v
+		default:
+			s := fmt.Sprint(v)
+			kvs[i].Value.StringValue = &s
+		}
+	}
+	return kvs
+}
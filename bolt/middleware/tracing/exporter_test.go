@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestOTLPExporter tests OTLP/HTTP JSON export to a stub collector.
+func TestOTLPExporter(t *testing.T) {
+	var mu sync.Mutex
+	var requests []map[string]any
+	var auth string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if r.Method != "POST" || r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s %s", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		requests = append(requests, body)
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:           collector.URL + "/v1/traces",
+		Headers:            map[string]string{"Authorization": "Bearer token"},
+		ServiceName:        "orders",
+		ResourceAttributes: []Attribute{{Key: "service.version", Value: "1.2.3"}},
+		BatchSize:          2,
+		FlushInterval:      time.Hour,
+	})
+
+	parent, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	start := time.Unix(1700000000, 0)
+	for i := range 3 {
+		span := &Span{
+			SpanContext: SpanContext{TraceID: parent.TraceID, SpanID: SpanID{0, 0, 0, 0, 0, 0, 0, byte(i + 1)}, Flags: FlagSampled, TraceState: "vendor=abc"},
+			Parent:      parent.SpanID,
+			Name:        "GET /orders/:id",
+			Start:       start,
+			End:         start.Add(1500 * time.Microsecond),
+			Attributes: []Attribute{
+				{Key: "http.route", Value: "/orders/:id"},
+				{Key: "http.response.status_code", Value: int64(500)},
+				{Key: "cache.hit", Value: false},
+				{Key: "ratio", Value: 0.5},
+			},
+		}
+		span.SetStatus(StatusError, "database down")
+		exporter.ExportSpan(span)
+	}
+
+	// First batch is full: sent without waiting; the rest on Flush
+	if err := exporter.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 2 || auth != "Bearer token" {
+		t.Fatalf("expected 2 authenticated exports, got %d (%q)", len(requests), auth)
+	}
+
+	resourceSpans := requests[0]["resourceSpans"].([]any)[0].(map[string]any)
+	resource := resourceSpans["resource"].(map[string]any)["attributes"].([]any)
+	if kv := resource[0].(map[string]any); kv["key"] != "service.name" || kv["value"].(map[string]any)["stringValue"] != "orders" {
+		t.Errorf("unexpected resource %v", resource)
+	}
+	if len(resource) != 2 {
+		t.Errorf("expected 2 resource attributes, got %v", resource)
+	}
+
+	spans := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans in the first batch, got %d", len(spans))
+	}
+	span := spans[0].(map[string]any)
+	want := map[string]any{
+		"traceId":           "4bf92f3577b34da6a3ce929d0e0e4736",
+		"spanId":            "0000000000000001",
+		"parentSpanId":      "00f067aa0ba902b7",
+		"traceState":        "vendor=abc",
+		"name":              "GET /orders/:id",
+		"kind":              2.0,
+		"startTimeUnixNano": "1700000000000000000",
+		"endTimeUnixNano":   "1700000000001500000",
+	}
+	for key, value := range want {
+		if span[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, span[key])
+		}
+	}
+	if status := span["status"].(map[string]any); status["code"] != 2.0 || status["message"] != "database down" {
+		t.Errorf("unexpected status %v", status)
+	}
+
+	attributes := span["attributes"].([]any)
+	values := []map[string]any{
+		{"stringValue": "/orders/:id"},
+		{"intValue": "500"},
+		{"boolValue": false},
+		{"doubleValue": 0.5},
+	}
+	for i, value := range values {
+		got := attributes[i].(map[string]any)["value"].(map[string]any)
+		for k, v := range value {
+			if got[k] != v || len(got) != 1 {
+				t.Errorf("attribute %d: expected %v, got %v", i, value, got)
+			}
+		}
+	}
+}
+
+// TestOTLPExporterErrors tests failed exports, full queues and shutdown.
+func TestOTLPExporterErrors(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{}, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(503)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{Endpoint: collector.URL, BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour})
+
+	// The first span is sent and blocks the sender; the second fills the queue
+	exporter.ExportSpan(&Span{})
+	<-received
+	for range 3 {
+		exporter.ExportSpan(&Span{})
+	}
+	if exporter.Dropped() != 2 {
+		t.Errorf("expected 2 dropped spans, got %d", exporter.Dropped())
+	}
+
+	close(release)
+	_ = exporter.Shutdown(context.Background())
+	if err := exporter.Flush(context.Background()); !errors.Is(err, ErrExporterClosed) {
+		t.Errorf("expected ErrExporterClosed, got %v", err)
+	}
+
+	// Spans waiting for a batch are sent by Flush, which reports the failure
+	exporter = NewOTLPExporter(OTLPConfig{Endpoint: collector.URL, FlushInterval: time.Hour})
+	defer exporter.Shutdown(context.Background())
+	exporter.ExportSpan(&Span{})
+	if err := exporter.Flush(context.Background()); err == nil || err.Error() != "tracing: export spans: 503 Service Unavailable" {
+		t.Errorf("expected export error, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package tracing
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+	"time"
+)
+
+// TraceID identifies a trace: all spans of a request across services.
+type TraceID [16]byte
+
+// IsValid reports whether t is not all zeros.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns t as 32 lowercase hex digits.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// IsValid reports whether s is not all zeros.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String returns s as 16 lowercase hex digits.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// FlagSampled is the trace flag set when the trace is recorded.
+const FlagSampled byte = 0x01
+
+// SpanContext is the part of a span propagated to other services in the
+// W3C traceparent and tracestate headers.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte   // Trace flags (FlagSampled)
+	TraceState string // Vendor data (tracestate header), propagated as is
+}
+
+// IsValid reports whether sc has a trace and span ID.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Sampled reports whether the trace is recorded.
+func (sc SpanContext) Sampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// TraceParent returns the traceparent header value of sc, e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func (sc SpanContext) TraceParent() string {
+	var buf [55]byte
+	return string(sc.appendTraceParent(buf[:0]))
+}
+
+// appendTraceParent appends the traceparent header value of sc.
+func (sc SpanContext) appendTraceParent(b []byte) []byte {
+	b = append(b, "00-"...)
+	b = hex.AppendEncode(b, sc.TraceID[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, sc.SpanID[:])
+	b = append(b, '-')
+	return hex.AppendEncode(b, []byte{sc.Flags})
+}
+
+// ParseTraceParent parses a W3C traceparent header value. It reports
+// false if the value is invalid, in which case a new trace should start.
+//
+// Versions above 00 are parsed as version 00, ignoring extra fields, as the
+// specification requires.
+func ParseTraceParent(s string) (SpanContext, bool) {
+	var sc SpanContext
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return sc, false
+	}
+	version, ok := parseHexByte(s[0:2])
+	if !ok || version == 0xff || (version == 0 && len(s) != 55) || (len(s) > 55 && s[55] != '-') {
+		return sc, false
+	}
+	if !decodeLowerHex(sc.TraceID[:], s[3:35]) || !decodeLowerHex(sc.SpanID[:], s[36:52]) {
+		return sc, false
+	}
+	if sc.Flags, ok = parseHexByte(s[53:55]); !ok || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	return sc, true
+}
+
+// parseHexByte parses 2 lowercase hex digits.
+func parseHexByte(s string) (byte, bool) {
+	var b [1]byte
+	return b[0], decodeLowerHex(b[:], s)
+}
+
+// decodeLowerHex decodes s into dst, which must be len(s)/2 bytes. The
+// specification only allows lowercase hex digits.
+func decodeLowerHex(dst []byte, s string) bool {
+	for i := 0; i < len(s); i++ {
+		if ch := s[i]; (ch < '0' || ch > '9') && (ch < 'a' || ch > 'f') {
+			return false
+		}
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// newTraceID returns a random trace ID.
+func newTraceID() TraceID {
+	var t TraceID
+	binary.LittleEndian.PutUint64(t[:8], rand.Uint64())
+	binary.LittleEndian.PutUint64(t[8:], rand.Uint64())
+	return t
+}
+
+// newSpanID returns a random, non-zero span ID.
+func newSpanID() SpanID {
+	var s SpanID
+	binary.LittleEndian.PutUint64(s[:], rand.Uint64()|1)
+	return s
+}
+
+// SpanStatus is the status of a span (OTLP status codes).
+type SpanStatus int
+
+const (
+	StatusUnset SpanStatus = 0 // Default: the request didn't fail
+	StatusOK    SpanStatus = 1 // Explicitly marked successful
+	StatusError SpanStatus = 2 // Failed: 5xx response or handler error
+)
+
+// Attribute is a span attribute. Value is a string, int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is a server span: one request handled by this service.
+//
+// Handlers can add attributes and set the status of the current span (see
+// SpanFrom); it is exported when the request ends.
+type Span struct {
+	SpanContext
+
+	// Parent is the caller's span (zero for traces started here).
+	Parent SpanID
+
+	// Name is the operation, e.g. "GET /users/:id".
+	Name string
+
+	Start time.Time
+	End   time.Time
+
+	// Attributes follow the OpenTelemetry HTTP semantic conventions
+	// (http.request.method, http.route, http.response.status_code, ...).
+	Attributes []Attribute
+
+	Status        SpanStatus
+	StatusMessage string
+}
+
+// SetAttribute adds or replaces an attribute.
+func (s *Span) SetAttribute(key string, value any) {
+	for i := range s.Attributes {
+		if s.Attributes[i].Key == key {
+			s.Attributes[i].Value = value
+			return
+		}
+	}
+	s.Attributes = append(s.Attributes, Attribute{Key: key, Value: value})
+}
+
+// SetStatus sets the status and its message.
+func (s *Span) SetStatus(status SpanStatus, message string) {
+	s.Status = status
+	s.StatusMessage = message
+}
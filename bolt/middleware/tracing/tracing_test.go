@@ -0,0 +1,220 @@
+package tracing
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/bolt/core"
+	"github.com/yourusername/shockwave/pkg/shockwave/client"
+)
+
+// TestParseTraceParent tests traceparent validation.
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0g", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		sc, ok := ParseTraceParent(tt.value)
+		if ok != tt.valid {
+			t.Errorf("%q: expected valid %v, got %v", tt.value, tt.valid, ok)
+			continue
+		}
+		if ok && sc.TraceParent()[3:] != "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-"+tt.value[53:55] {
+			t.Errorf("%q: round trip gave %q", tt.value, sc.TraceParent())
+		}
+	}
+}
+
+// TestTracing tests trace continuation, new traces, sampling and span data.
+func TestTracing(t *testing.T) {
+	exporter := NewMemoryExporter()
+	app := core.New()
+	app.Use(TracingWithConfig(TracingConfig{Exporter: exporter}))
+
+	var current *Span
+	app.Get("/users/:id", func(c *core.Context) error {
+		current = SpanFrom(c)
+		current.SetAttribute("user.id", c.Param("id"))
+		return c.NoContent()
+	})
+	app.Get("/fail", func(c *core.Context) error {
+		return errors.New("database down")
+	})
+	app.Get("/missing", func(c *core.Context) error {
+		return core.ErrNotFound
+	})
+
+	serve := func(path, traceparent string) {
+		req := httptest.NewRequest("GET", path, nil)
+		if traceparent != "" {
+			req.Header.Set("traceparent", traceparent)
+			req.Header.Set("tracestate", "vendor=abc")
+		}
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Continued trace
+	serve("/users/42", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0] != current {
+		t.Fatalf("expected the handler's span to be exported, got %v", spans)
+	}
+	span := spans[0]
+	if span.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent.String() != "00f067aa0ba902b7" ||
+		span.TraceState != "vendor=abc" || !span.SpanID.IsValid() || span.SpanID == span.Parent {
+		t.Errorf("unexpected span context %+v", span.SpanContext)
+	}
+	if span.Name != "GET /users/:id" || span.Status != StatusUnset || span.End.Before(span.Start) {
+		t.Errorf("unexpected span %+v", span)
+	}
+	want := map[string]any{
+		"user.id":                   "42",
+		"http.route":                "/users/:id",
+		"http.request.method":       "GET",
+		"url.path":                  "/users/42",
+		"url.scheme":                "http",
+		"http.response.status_code": int64(204),
+		"server.address":            "example.com",
+		"client.address":            "192.0.2.1",
+	}
+	for _, attr := range span.Attributes {
+		if value, ok := want[attr.Key]; ok && value != attr.Value {
+			t.Errorf("%s: expected %v, got %v", attr.Key, value, attr.Value)
+		}
+		delete(want, attr.Key)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing attributes %v", want)
+	}
+
+	// Unsampled caller: propagated, not exported
+	exporter.Reset()
+	serve("/users/42", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if len(exporter.Spans()) != 0 || current == nil || current.Sampled() {
+		t.Error("expected an unsampled span that isn't exported")
+	}
+
+	// New trace, failures
+	serve("/fail", "invalid")
+	serve("/missing", "")
+	spans = exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent.IsValid() || !spans[0].TraceID.IsValid() || spans[0].TraceState != "" {
+		t.Errorf("expected a new trace, got %+v", spans[0].SpanContext)
+	}
+	if spans[0].Status != StatusError || spans[0].StatusMessage != "database down" {
+		t.Errorf("expected error status, got %v %q", spans[0].Status, spans[0].StatusMessage)
+	}
+	if spans[1].Status != StatusUnset {
+		t.Errorf("expected 4xx to leave the status unset, got %v", spans[1].Status)
+	}
+	if spans[0].TraceID == spans[1].TraceID {
+		t.Error("expected distinct trace IDs")
+	}
+}
+
+// TestTracingSampleRate tests sampling of new traces.
+func TestTracingSampleRate(t *testing.T) {
+	exporter := NewMemoryExporter()
+	app := core.New()
+	app.Use(TracingWithConfig(TracingConfig{Exporter: exporter, SampleRate: 0.25}))
+	app.Get("/", func(c *core.Context) error { return c.NoContent() })
+
+	for range 1000 {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+	if n := len(exporter.Spans()); n < 150 || n > 350 {
+		t.Errorf("expected about 250 sampled spans, got %d", n)
+	}
+}
+
+// TestInjectTrace tests propagation to outbound Shockwave and net/http requests.
+func TestInjectTrace(t *testing.T) {
+	var received []string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("traceparent")+" "+r.Header.Get("tracestate"))
+	}))
+	defer downstream.Close()
+
+	httpClient := client.NewClient()
+	defer httpClient.Close()
+	httpClient.OnRequest(InjectTrace)
+
+	exporter := NewMemoryExporter()
+	app := core.New()
+	app.Use(TracingWithConfig(TracingConfig{Exporter: exporter}))
+	app.Get("/", func(c *core.Context) error {
+		resp, err := httpClient.DoContext(c.Context(), "GET", downstream.URL+"/items", nil)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body())
+		resp.Close()
+
+		req, _ := http.NewRequestWithContext(c.Context(), "GET", downstream.URL+"/stock", nil)
+		Inject(req.Context(), req.Header)
+		resp2, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp2.Body.Close()
+		return c.NoContent()
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("tracestate", "vendor=abc")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Code != 204 {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body)
+	}
+
+	span := exporter.Spans()[0]
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + span.SpanID.String() + "-01 vendor=abc"
+	if len(received) != 2 || received[0] != want || received[1] != want {
+		t.Errorf("expected %q downstream, got %q", want, received)
+	}
+}
+
+// BenchmarkTracing benchmarks tracing middleware overhead.
+func BenchmarkTracing(b *testing.B) {
+	handler := TracingWithConfig(TracingConfig{Exporter: discardExporter{}})(func(c *core.Context) error {
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx := &core.Context{}
+		ctx.SetMethod("GET")
+		ctx.SetPath("/test")
+		_ = handler(ctx)
+	}
+}
+
+// discardExporter drops spans.
+type discardExporter struct{}
+
+func (discardExporter) ExportSpan(span *Span) {}
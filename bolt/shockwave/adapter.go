@@ -101,6 +101,12 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.srv.Shutdown(ctx)
 }
 
+// Stats returns the server statistics (connections, requests, errors).
+// Request counts are kept even with DisableStats.
+func (s *Server) Stats() *server.Stats {
+	return s.srv.Stats()
+}
+
 // Request is just an alias to http11.Request for convenience.
 // No wrapping needed - Bolt works directly with Shockwave types.
 type Request = http11.Request
@@ -2,12 +2,15 @@ module github.com/yourusername/shockwave
 
 go 1.25.3
 
+require (
+	github.com/valyala/fasthttp v1.68.0
+	golang.org/x/crypto v0.44.0
+	golang.org/x/sys v0.38.0
+)
+
 require (
 	github.com/andybalholm/brotli v1.2.0 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/klauspost/compress v1.18.1 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/valyala/fasthttp v1.68.0 // indirect
-	golang.org/x/crypto v0.44.0 // indirect
-	golang.org/x/sys v0.38.0 // indirect
 )
@@ -206,6 +206,17 @@ func (r *ClientRequest) SetContext(ctx context.Context) {
 	r.ctx = ctx
 }
 
+// Context returns the request context, or context.Background() if none
+// was set.
+//
+// Allocation behavior: 0 allocs/op
+func (r *ClientRequest) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 // GetMethod returns the method bytes.
 // Zero-copy for known methods.
 //
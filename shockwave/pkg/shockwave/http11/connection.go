@@ -2,6 +2,8 @@ package http11
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/tls"
 	"io"
 	"net"
 	"sync/atomic"
@@ -64,7 +66,9 @@ type Connection struct {
 	requests atomic.Int32 // Request counter (lock-free)
 
 	// Network connection
-	conn net.Conn
+	conn       net.Conn
+	remoteAddr string // conn.RemoteAddr().String(), cached on first request
+	tls        bool   // conn is a *tls.Conn
 
 	// Buffered I/O
 	reader *bufio.Reader
@@ -84,8 +88,23 @@ type Connection struct {
 	// Close channel (signals connection should close)
 	closeCh chan struct{}
 	closed  atomic.Bool
+
+	// Set by Shutdown: close once the response in progress is sent
+	draining atomic.Bool
+
+	// Disconnect watcher for the in-flight request (see watchDisconnect)
+	// nil unless the handler called Request.Context()
+	watchStop chan struct{}
+	watchDone chan struct{}
+	watchPeek bool
+
+	// Set when the handler took over the connection (see ResponseWriter.Hijack)
+	hijacked bool
 }
 
+// aLongTimeAgo is a non-zero time in the past, used to unblock pending reads.
+var aLongTimeAgo = time.Unix(1, 0)
+
 // ConnectionConfig holds configuration for an HTTP connection
 type ConnectionConfig struct {
 	// KeepAliveTimeout is the maximum duration an idle connection will be kept alive
@@ -131,6 +150,8 @@ func NewConnection(conn net.Conn, config ConnectionConfig, handler Handler) *Con
 		closeCh:          make(chan struct{}),
 	}
 
+	_, c.tls = conn.(*tls.Conn)
+
 	// Initialize lock-free atomic state
 	c.state.Store(int32(StateNew))
 	c.lastUse.Store(time.Now().UnixNano())
@@ -187,33 +208,50 @@ func (c *Connection) Serve() error {
 			return err
 		}
 
-		// Parse next request
-		c.setState(StateActive)
+		// Parse next request (the connection stays idle until one arrived)
 		req, err := c.parser.Parse(c.reader)
 		if err != nil {
-			if err == io.EOF || err == ErrUnexpectedEOF {
-				// Clean connection close (EOF or unexpected EOF between requests)
+			if err == io.EOF || err == ErrUnexpectedEOF || c.closed.Load() {
+				// Clean connection close (EOF or unexpected EOF between requests, or Shutdown)
 				return nil
 			}
 			// Parse error
 			return err
 		}
 
+		// Shutdown closed the idle connection while the request arrived: drop it
+		if !c.activate() {
+			PutRequest(req)
+			return nil
+		}
+
 		// CRITICAL: Request is from pool, must be returned when done
 		// We explicitly return it before continuing the loop for zero-alloc keep-alive
 		// Only use defer for panic recovery
 
+		// Link request to connection (enables disconnect detection in Request.Context)
+		req.conn = c
+
+		// Client address (formatted once per connection)
+		if c.remoteAddr == "" {
+			if addr := c.conn.RemoteAddr(); addr != nil {
+				c.remoteAddr = addr.String()
+			}
+		}
+		req.RemoteAddr = c.remoteAddr
+
 		// Increment request counter (lock-free)
 		requestNum := c.requests.Add(1)
 
 		// Get response writer from pool
 		rw := GetResponseWriter(c.writer)
+		rw.owner = c
 
 		// Check if this will be the last request (before handling)
 		willCloseAfterThis := c.maxRequests > 0 && requestNum >= c.maxRequests
 
 		// Set Connection: close if this is the last request
-		if willCloseAfterThis {
+		if willCloseAfterThis || c.draining.Load() {
 			rw.Header().Set(headerConnection, headerClose)
 		}
 
@@ -223,6 +261,16 @@ func (c *Connection) Serve() error {
 		// Production handlers should use recover() internally if needed.
 		handlerErr := c.handler(req, rw)
 
+		// Stop disconnect watcher before the connection is reused
+		c.stopWatch()
+
+		// Hijacked: the handler was done with the connection, close it
+		if c.hijacked {
+			PutResponseWriter(rw)
+			PutRequest(req)
+			return handlerErr
+		}
+
 		// Flush response
 		if err := rw.Flush(); err != nil {
 			PutResponseWriter(rw)
@@ -248,9 +296,41 @@ func (c *Connection) Serve() error {
 	}
 }
 
+// activate moves an idle connection to StateActive. It fails if Shutdown
+// claimed the connection first.
+func (c *Connection) activate() bool {
+	for {
+		state := c.state.Load()
+		if state != int32(StateNew) && state != int32(StateIdle) {
+			return false
+		}
+		if c.state.CompareAndSwap(state, int32(StateActive)) {
+			c.lastUse.Store(time.Now().UnixNano())
+			return true
+		}
+	}
+}
+
+// Shutdown closes the connection gracefully: immediately if it's waiting
+// for a request, otherwise once the response in progress is sent. Safe to
+// call from any goroutine.
+func (c *Connection) Shutdown() {
+	c.draining.Store(true)
+	for {
+		state := c.state.Load()
+		if state != int32(StateNew) && state != int32(StateIdle) {
+			return // Serve stops after the current request
+		}
+		if c.state.CompareAndSwap(state, int32(StateClosed)) {
+			c.Close()
+			return
+		}
+	}
+}
+
 // shouldClose checks if the connection should close immediately
 func (c *Connection) shouldClose() bool {
-	if c.closed.Load() {
+	if c.closed.Load() || c.draining.Load() {
 		return true
 	}
 
@@ -296,6 +376,100 @@ func (c *Connection) shouldCloseAfterRequest(req *Request, rw *ResponseWriter, r
 	return false
 }
 
+// watchDisconnect cancels the request context when the client goes away.
+//
+// Called lazily by Request.Context(). If the request has no body and no
+// pipelined data is pending, the connection is idle until the response is
+// written, so a background Peek detects EOF (client disconnect) without
+// consuming data. Otherwise reading would race with the handler, and only
+// Close() is watched.
+//
+// Allocation behavior: 1 goroutine + 2 channels per watched request
+func (c *Connection) watchDisconnect(req *Request) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	cancel := req.cancel
+
+	c.watchStop = stop
+	c.watchDone = done
+	c.watchPeek = !req.HasBody() && c.reader.Buffered() == 0 && len(c.parser.unreadBuf) == 0
+
+	if !c.watchPeek {
+		go func() {
+			defer close(done)
+			select {
+			case <-c.closeCh:
+				cancel()
+			case <-stop:
+			}
+		}()
+		return
+	}
+
+	reader := c.reader
+	go func() {
+		defer close(done)
+
+		// Blocks until data arrives, the client disconnects, or stopWatch unblocks it
+		_, err := reader.Peek(1)
+
+		select {
+		case <-stop:
+			// Unblocked by stopWatch - request completed normally
+		default:
+			if err != nil {
+				cancel()
+			}
+		}
+	}()
+}
+
+// stopWatch stops the disconnect watcher of the current request, if any.
+func (c *Connection) stopWatch() {
+	if c.watchDone == nil {
+		return
+	}
+
+	close(c.watchStop)
+	if c.watchPeek {
+		// Unblock the pending Peek (buffered data is preserved)
+		c.conn.SetReadDeadline(aLongTimeAgo)
+	}
+	<-c.watchDone
+
+	if c.watchPeek {
+		// Clear the past deadline; Serve re-arms the keep-alive deadline
+		c.conn.SetReadDeadline(time.Time{})
+	}
+
+	c.watchStop = nil
+	c.watchDone = nil
+	c.watchPeek = false
+}
+
+// hijack hands the connection over to the current handler (see ResponseWriter.Hijack).
+func (c *Connection) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// The handler reads from the connection now: stop the background Peek
+	c.stopWatch()
+
+	if err := c.writer.Flush(); err != nil {
+		return nil, nil, err
+	}
+	if err := c.conn.SetDeadline(time.Time{}); err != nil {
+		return nil, nil, err
+	}
+
+	c.hijacked = true
+
+	// Data the parser read past the request belongs to the new owner
+	reader := c.reader
+	if unread := c.parser.unreadBuf; len(unread) > 0 {
+		c.parser.unreadBuf = nil
+		reader = bufio.NewReader(io.MultiReader(bytes.NewReader(unread), c.reader))
+	}
+	return c.conn, bufio.NewReadWriter(reader, c.writer), nil
+}
+
 // setDeadline sets the read/write deadline for keep-alive timeout
 func (c *Connection) setDeadline() error {
 	if c.keepAliveTimeout > 0 {
@@ -1,8 +1,11 @@
 package http11
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"strings"
 	"sync"
 	"testing"
@@ -107,6 +110,10 @@ func TestConnectionSingleRequest(t *testing.T) {
 			t.Errorf("Path = %s, want /test", req.Path())
 		}
 
+		if req.RemoteAddr != "127.0.0.1:12345" || req.IsTLS() {
+			t.Errorf("RemoteAddr = %q, IsTLS = %v, want 127.0.0.1:12345 without TLS", req.RemoteAddr, req.IsTLS())
+		}
+
 		// Write response
 		rw.WriteHeader(200)
 		rw.Write([]byte("OK"))
@@ -337,6 +344,55 @@ func TestConnectionHandlerError(t *testing.T) {
 	}
 }
 
+func TestConnectionHijack(t *testing.T) {
+	// Request followed by data for the new protocol, buffered by the server
+	mockConn := newMockConn("GET /ws HTTP/1.1\r\nUpgrade: test\r\n\r\nEXTRA")
+	config := DefaultConnectionConfig()
+
+	var received string
+	handler := func(req *Request, rw *ResponseWriter) error {
+		rw.Header().Set([]byte("X-Ignored"), []byte("1"))
+
+		conn, brw, err := rw.Hijack()
+		if err != nil {
+			return err
+		}
+		if _, _, err := rw.Hijack(); err != ErrHijacked {
+			t.Errorf("second Hijack() error = %v, want ErrHijacked", err)
+		}
+		if !rw.Hijacked() || conn == nil {
+			t.Error("expected hijacked connection")
+		}
+
+		buf := make([]byte, 5)
+		if _, err := io.ReadFull(brw, buf); err != nil {
+			return err
+		}
+		received = string(buf)
+
+		_, err = conn.Write([]byte("HELLO"))
+		return err
+	}
+
+	conn := NewConnection(mockConn, config, handler)
+	defer conn.Close()
+
+	if err := conn.Serve(); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	if received != "EXTRA" {
+		t.Errorf("handler read %q, want buffered EXTRA", received)
+	}
+	if output := mockConn.writeData.String(); output != "HELLO" {
+		t.Errorf("output = %q, want only the hijacked protocol data", output)
+	}
+
+	// Not attached to a connection
+	if _, _, err := NewResponseWriter(io.Discard).Hijack(); err != ErrHijackUnsupported {
+		t.Errorf("Hijack() error = %v, want ErrHijackUnsupported", err)
+	}
+}
+
 func TestConnectionEOF(t *testing.T) {
 	// Empty connection (immediate EOF)
 	mockConn := newMockConn("")
@@ -384,6 +440,161 @@ func TestConnectionConcurrentClose(t *testing.T) {
 	}
 }
 
+func TestConnectionShutdownIdle(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	config := DefaultConnectionConfig()
+	config.KeepAliveTimeout = 0
+
+	handler := func(req *Request, rw *ResponseWriter) error {
+		return nil
+	}
+
+	conn := NewConnection(server, config, handler)
+	served := make(chan error, 1)
+	go func() { served <- conn.Serve() }()
+
+	// Waiting for the first request: Shutdown closes right away
+	conn.Shutdown()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve() after Shutdown = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Serve() did not return after Shutdown")
+	}
+	if conn.State() != StateClosed {
+		t.Errorf("State after Shutdown = %v, want StateClosed", conn.State())
+	}
+}
+
+func TestConnectionShutdownActive(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	config := DefaultConnectionConfig()
+	config.KeepAliveTimeout = 0
+
+	var conn *Connection
+	handler := func(req *Request, rw *ResponseWriter) error {
+		// Shutdown during the request: the response is still sent
+		conn.Shutdown()
+		rw.WriteHeader(200)
+		rw.Write([]byte("OK"))
+		return nil
+	}
+
+	conn = NewConnection(server, config, handler)
+	served := make(chan error, 1)
+	go func() {
+		served <- conn.Serve()
+		server.Close() // The server closes the connection once Serve returns
+	}()
+
+	go client.Write([]byte("GET /test HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	response, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(response), "HTTP/1.1 200") || !strings.HasSuffix(string(response), "OK") {
+		t.Errorf("unexpected response %q", response)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve() after Shutdown = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Serve() did not return after the response")
+	}
+}
+
+func TestConnectionContextClientDisconnect(t *testing.T) {
+	server, client := net.Pipe()
+	config := DefaultConnectionConfig()
+
+	cancelled := make(chan error, 1)
+	handler := func(req *Request, rw *ResponseWriter) error {
+		ctx := req.Context()
+
+		// Client goes away while the handler is still working
+		client.Close()
+
+		select {
+		case <-ctx.Done():
+			cancelled <- ctx.Err()
+		case <-time.After(time.Second):
+			cancelled <- nil
+		}
+		return nil
+	}
+
+	conn := NewConnection(server, config, handler)
+	defer conn.Close()
+
+	go client.Write([]byte("GET /slow HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	conn.Serve()
+
+	if err := <-cancelled; err != context.Canceled {
+		t.Errorf("ctx.Err() = %v, want context.Canceled", err)
+	}
+}
+
+func TestConnectionContextKeepAlive(t *testing.T) {
+	server, client := net.Pipe()
+	config := DefaultConnectionConfig()
+	config.KeepAliveTimeout = 0
+
+	var contexts []context.Context
+	handler := func(req *Request, rw *ResponseWriter) error {
+		ctx := req.Context()
+		if ctx.Err() != nil {
+			t.Errorf("request %d: context cancelled in handler: %v", len(contexts), ctx.Err())
+		}
+		contexts = append(contexts, ctx)
+
+		rw.WriteHeader(200)
+		rw.Write([]byte("OK"))
+		return nil
+	}
+
+	conn := NewConnection(server, config, handler)
+	done := make(chan struct{})
+	go func() {
+		conn.Serve()
+		close(done)
+	}()
+
+	// Two requests on the same connection: the watcher of the first
+	// request must not break reading the second one
+	reader := bufio.NewReader(client)
+	for i := 0; i < 2; i++ {
+		client.Write([]byte("GET /test HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		line, err := reader.ReadString('\n')
+		if err != nil || !strings.Contains(line, "200") {
+			t.Fatalf("request %d: status line = %q, err = %v", i, line, err)
+		}
+		for line != "\r\n" {
+			line, _ = reader.ReadString('\n')
+		}
+		reader.Discard(2) // Body "OK"
+	}
+
+	client.Close()
+	<-done
+
+	if len(contexts) != 2 {
+		t.Fatalf("handled %d requests, want 2", len(contexts))
+	}
+	for i, ctx := range contexts {
+		if ctx.Err() == nil {
+			t.Errorf("request %d: context not cancelled after completion", i)
+		}
+	}
+}
+
 // Benchmarks
 
 func BenchmarkConnectionServe(b *testing.B) {
@@ -73,6 +73,12 @@ var (
 
 	// ErrMaxRequestsExceeded indicates max requests per connection exceeded
 	ErrMaxRequestsExceeded = errors.New("http11: max requests per connection exceeded")
+
+	// ErrHijackUnsupported indicates the response writer isn't attached to a connection
+	ErrHijackUnsupported = errors.New("http11: response writer not attached to a connection")
+
+	// ErrHijacked indicates the connection was already hijacked
+	ErrHijacked = errors.New("http11: connection already hijacked")
 )
 
 // Response errors
@@ -22,9 +22,37 @@ type Header struct {
 	// Number of headers currently stored (0-32)
 	count uint8
 
-	// Fallback storage for >32 headers (heap-allocated, rare case)
-	// nil for typical requests
-	overflow map[string]string
+	// Fallback storage for >32 headers or large values (heap-allocated, rare case)
+	// nil for typical requests. A slice (not a map) so repeated headers such
+	// as several large Set-Cookie values are all kept, in order
+	overflow []overflowHeader
+}
+
+// overflowHeader is a header stored outside the inline arrays.
+// Byte slices (not strings) so Get and VisitAll return them without copying.
+type overflowHeader struct {
+	name  []byte
+	value []byte
+}
+
+// newOverflowHeader copies name and value into a single allocation.
+func newOverflowHeader(name, value []byte) overflowHeader {
+	buf := make([]byte, len(name)+len(value))
+	n := copy(buf, name)
+	copy(buf[n:], value)
+	return overflowHeader{name: buf[:n:n], value: buf[n:]}
+}
+
+// overflowIndex returns the index of the first overflow header named name
+// (case-insensitive), or -1.
+func (h *Header) overflowIndex(name []byte) int {
+	for i := range h.overflow {
+		if len(h.overflow[i].name) == len(name) &&
+			bytesEqualCaseInsensitive(h.overflow[i].name, name) {
+			return i
+		}
+	}
+	return -1
 }
 
 // Add adds a header to the collection.
@@ -85,10 +113,7 @@ func (h *Header) Add(name, value []byte) error {
 
 	// Slow path: overflow storage (rare case, >32 headers OR large values >128 bytes)
 	// P1 FIX #2: Use overflow for large header values to avoid rejecting them
-	if h.overflow == nil {
-		h.overflow = make(map[string]string, 8)
-	}
-	h.overflow[string(name)] = string(value)
+	h.overflow = append(h.overflow, newOverflowHeader(name, value))
 	return nil
 }
 
@@ -96,9 +121,9 @@ func (h *Header) Add(name, value []byte) error {
 // Returns nil if the header is not found.
 //
 // The returned byte slice references internal storage and is valid
-// only until the next call to Reset() or Add().
+// only until the next call to Reset(), Set() or Del().
 //
-// Allocation behavior: 0 allocs/op for inline storage lookup
+// Allocation behavior: 0 allocs/op (inline and overflow storage)
 func (h *Header) Get(name []byte) []byte {
 	// Linear scan through inline storage
 	// For N≤32, this is faster than map lookup due to cache locality
@@ -110,11 +135,8 @@ func (h *Header) Get(name []byte) []byte {
 	}
 
 	// Check overflow storage if present
-	if h.overflow != nil {
-		// Convert to string for map lookup (small allocation, acceptable for rare case)
-		if val, ok := h.overflow[string(name)]; ok {
-			return []byte(val)
-		}
+	if i := h.overflowIndex(name); i >= 0 {
+		return h.overflow[i].value
 	}
 
 	return nil
@@ -145,12 +167,7 @@ func (h *Header) Has(name []byte) bool {
 	}
 
 	// Check overflow
-	if h.overflow != nil {
-		_, ok := h.overflow[string(name)]
-		return ok
-	}
-
-	return false
+	return h.overflowIndex(name) >= 0
 }
 
 // Set sets a header value, replacing any existing value.
@@ -195,7 +212,7 @@ func (h *Header) Set(name, value []byte) error {
 			} else {
 				// Value too large for inline storage
 				// Delete from inline and move to overflow
-				nameStr := string(h.names[i][:h.nameLens[i]])
+				oh := newOverflowHeader(h.names[i][:h.nameLens[i]], value)
 
 				// Remove from inline storage by shifting
 				if i < h.count-1 {
@@ -207,33 +224,27 @@ func (h *Header) Set(name, value []byte) error {
 				h.count--
 
 				// Add to overflow
-				if h.overflow == nil {
-					h.overflow = make(map[string]string, 8)
-				}
-				h.overflow[nameStr] = string(value)
+				h.overflow = append(h.overflow, oh)
 				return nil
 			}
 		}
 	}
 
 	// Check overflow
-	if h.overflow != nil {
-		nameStr := string(name)
-		if _, ok := h.overflow[nameStr]; ok {
-			h.overflow[nameStr] = string(value)
-			return nil
-		}
+	if i := h.overflowIndex(name); i >= 0 {
+		h.overflow[i].value = append(h.overflow[i].value[:0], value...)
+		return nil
 	}
 
 	// Header doesn't exist, add it
 	return h.Add(name, value)
 }
 
-// Del deletes a header by name (case-insensitive).
+// Del deletes all values of a header by name (case-insensitive).
 // Allocation behavior: 0 allocs/op
 func (h *Header) Del(name []byte) {
 	// Find and delete from inline storage
-	for i := uint8(0); i < h.count; i++ {
+	for i := uint8(0); i < h.count; {
 		if h.nameLens[i] == uint8(len(name)) &&
 			bytesEqualCaseInsensitive(h.names[i][:h.nameLens[i]], name) {
 			// Shift remaining headers down
@@ -244,13 +255,14 @@ func (h *Header) Del(name []byte) {
 				copy(h.valueLens[i:], h.valueLens[i+1:])
 			}
 			h.count--
-			return
+			continue
 		}
+		i++
 	}
 
 	// Delete from overflow if present
-	if h.overflow != nil {
-		delete(h.overflow, string(name))
+	for i := h.overflowIndex(name); i >= 0; i = h.overflowIndex(name) {
+		h.overflow = append(h.overflow[:i], h.overflow[i+1:]...)
 	}
 }
 
@@ -264,8 +276,8 @@ func (h *Header) Len() int {
 }
 
 // Reset clears all headers for reuse (e.g., when returning to pool).
-// This does not deallocate the overflow map, but clears it.
-// The GC will clean up the map when the Header is no longer referenced.
+// The overflow storage is released; the GC cleans it up when the Header
+// is no longer referenced.
 //
 // Allocation behavior: 0 allocs/op
 func (h *Header) Reset() {
@@ -289,11 +301,9 @@ func (h *Header) VisitAll(visitor func(name, value []byte) bool) {
 	}
 
 	// Visit overflow headers
-	if h.overflow != nil {
-		for name, value := range h.overflow {
-			if !visitor([]byte(name), []byte(value)) {
-				return
-			}
+	for _, oh := range h.overflow {
+		if !visitor(oh.name, oh.value) {
+			return
 		}
 	}
 }
@@ -2,6 +2,7 @@ package http11
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -549,6 +550,43 @@ func BenchmarkHeaderVisitAll(b *testing.B) {
 	}
 }
 
+// TestHeaderLargeValueNoAlloc tests that large values (overflow storage)
+// are read without copying.
+func TestHeaderLargeValueNoAlloc(t *testing.T) {
+	var h Header
+	cookie := strings.Repeat("session=0123456789abcdef; ", 10) // 260 bytes
+	h.Add([]byte("Content-Type"), []byte("text/html"))
+	h.Add([]byte("Cookie"), []byte(cookie))
+
+	name := []byte("cookie")
+	if got := h.Get(name); string(got) != cookie {
+		t.Fatalf("Get = %q, want %q", got, cookie)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = h.Get(name) }); allocs != 0 {
+		t.Errorf("Get allocs = %v, want 0", allocs)
+	}
+	visit := func(name, value []byte) bool { return true }
+	if allocs := testing.AllocsPerRun(100, func() { h.VisitAll(visit) }); allocs != 0 {
+		t.Errorf("VisitAll allocs = %v, want 0", allocs)
+	}
+
+	// Set replaces overflow values and moves grown inline values, keeping names
+	longer := cookie + "theme=dark"
+	h.Set([]byte("Cookie"), []byte(longer))
+	h.Set([]byte("Content-Type"), []byte(cookie))
+	if got := h.Get(name); string(got) != longer {
+		t.Errorf("Get after Set = %q, want %q", got, longer)
+	}
+	var names []string
+	h.VisitAll(func(name, value []byte) bool {
+		names = append(names, string(name))
+		return true
+	})
+	if strings.Join(names, ",") != "Cookie,Content-Type" || h.GetString([]byte("Content-Type")) != cookie {
+		t.Errorf("unexpected headers %v", names)
+	}
+}
+
 // Additional tests for 100% coverage of overflow paths
 
 func TestHeaderHasInOverflow(t *testing.T) {
@@ -639,6 +677,36 @@ func TestHeaderDelInOverflow(t *testing.T) {
 	}
 }
 
+func TestHeaderRepeatedLargeValues(t *testing.T) {
+	var h Header
+
+	// Large values go to overflow storage; repeated headers must all be kept
+	small := []byte("a=1")
+	large1 := []byte("b=" + strings.Repeat("x", 200))
+	large2 := []byte("c=" + strings.Repeat("y", 200))
+	h.Add([]byte("Set-Cookie"), small)
+	h.Add([]byte("Set-Cookie"), large1)
+	h.Add([]byte("set-cookie"), large2)
+
+	var got []string
+	h.VisitAll(func(name, value []byte) bool {
+		got = append(got, string(value))
+		return true
+	})
+	if len(got) != 3 || got[1] != string(large1) || got[2] != string(large2) {
+		t.Fatalf("VisitAll = %d values, want all 3 in order", len(got))
+	}
+	if string(h.Get([]byte("SET-COOKIE"))) != string(small) {
+		t.Error("Get should return the first value")
+	}
+
+	// Del removes every value
+	h.Del([]byte("Set-Cookie"))
+	if h.Len() != 0 || h.Has([]byte("Set-Cookie")) {
+		t.Errorf("Len after Del = %d, want 0", h.Len())
+	}
+}
+
 func TestHeaderVisitAllWithOverflow(t *testing.T) {
 	var h Header
 
@@ -104,9 +104,11 @@ func (p *Parser) Parse(r io.Reader) (*Request, error) {
 
 	// Setup body reader if needed
 	// P1 FIX #1: Pass unreadBuf for chunked/body reading
-	// The unreadBuf may contain body data that was read along with headers
+	// The unreadBuf may contain body data that was read along with headers.
+	// Without a body it holds the next pipelined request (or, after a
+	// protocol upgrade, the new protocol's data): keep it for Parse/hijack.
 	bodyReader := r
-	if len(p.unreadBuf) > 0 {
+	if len(p.unreadBuf) > 0 && (req.ContentLength > 0 || len(req.TransferEncoding) > 0) {
 		bodyReader = io.MultiReader(bytes.NewReader(p.unreadBuf), r)
 		p.unreadBuf = nil
 	}
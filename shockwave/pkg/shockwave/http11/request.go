@@ -1,6 +1,7 @@
 package http11
 
 import (
+	"context"
 	"io"
 	"net/url"
 )
@@ -60,15 +61,31 @@ type Request struct {
 	// or if HTTP/1.0 without "Connection: keep-alive"
 	Close bool
 
-	// RemoteAddr is the network address of the client
+	// RemoteAddr is the network address of the client ("IP:port"),
+	// set by Connection.Serve
 	RemoteAddr string
 
+	// Request context (lazy allocation)
+	// Only allocated if Context() is called
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Connection serving this request (nil when parsed standalone)
+	conn *Connection
+
 	// Internal buffer reference (for zero-copy safety)
 	// This buffer is pooled and will be reused after request completes
 	// All zero-copy slices reference this buffer
 	buf []byte
 }
 
+// IsTLS reports whether the request was received over TLS.
+//
+// Allocation behavior: 0 allocs/op
+func (r *Request) IsTLS() bool {
+	return r.conn != nil && r.conn.tls
+}
+
 // Method returns the HTTP method as a string.
 // Uses pre-compiled constants for zero allocations.
 //
@@ -150,6 +167,24 @@ func (r *Request) ParsedURL() (*url.URL, error) {
 	return r.pathParsed, nil
 }
 
+// Context returns the request context.
+//
+// The context is cancelled when the client disconnects, when the connection
+// is closed (e.g. forced server shutdown), or when the request completes.
+// It is lazily allocated only when called, so handlers that never use it
+// stay allocation-free.
+//
+// Allocation behavior: Multiple allocs/op on first call, 0 on subsequent
+func (r *Request) Context() context.Context {
+	if r.ctx == nil {
+		r.ctx, r.cancel = context.WithCancel(context.Background())
+		if r.conn != nil && !r.conn.hijacked {
+			r.conn.watchDisconnect(r)
+		}
+	}
+	return r.ctx
+}
+
 // GetHeader retrieves a header value by name (case-insensitive).
 // Returns nil if not found.
 //
@@ -263,6 +298,12 @@ func (r *Request) Reset() {
 	r.TransferEncoding = nil
 	r.Close = false
 	r.RemoteAddr = ""
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.ctx = nil
+	r.cancel = nil
+	r.conn = nil
 	r.buf = nil
 }
 
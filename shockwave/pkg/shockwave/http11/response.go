@@ -1,8 +1,13 @@
 package http11
 
 import (
+	"bufio"
 	"io"
+	"net"
+	"os"
 	"strconv"
+
+	"github.com/yourusername/shockwave/pkg/shockwave/socket"
 )
 
 // ResponseWriter writes HTTP/1.1 responses with zero allocations for common cases.
@@ -18,6 +23,9 @@ type ResponseWriter struct {
 	// Underlying writer
 	w io.Writer
 
+	// Connection the response belongs to (nil when not serving a connection)
+	owner *Connection
+
 	// Status code (default 200)
 	status int
 
@@ -144,6 +152,76 @@ func (rw *ResponseWriter) Flush() error {
 	return nil
 }
 
+// Conn returns the network connection the response is written to.
+// Returns nil when the writer isn't attached to a connection (tests, custom writers).
+//
+// Data written directly to the connection bypasses the response buffer:
+// call Flush first so the status line and headers precede it.
+func (rw *ResponseWriter) Conn() net.Conn {
+	if rw.owner == nil {
+		return nil
+	}
+	return rw.owner.conn
+}
+
+// Hijack takes over the connection, e.g. to switch protocols (WebSocket).
+//
+// Pending response output is flushed first. The returned ReadWriter holds any
+// data the server already read from the client, so read from it rather than
+// from the raw connection. After Hijack the server neither writes the
+// response nor reads further requests; the connection's read and write
+// deadlines are cleared.
+//
+// Unlike net/http, the connection stays owned by the server: it is closed
+// (and the server stops tracking it) when the handler returns, so the
+// handler must not return until it is done with the connection.
+func (rw *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if rw.owner == nil {
+		return nil, nil, ErrHijackUnsupported
+	}
+	if rw.owner.hijacked {
+		return nil, nil, ErrHijacked
+	}
+	if rw.headerWritten {
+		if err := rw.Flush(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return rw.owner.hijack()
+}
+
+// Hijacked reports whether the connection was hijacked.
+func (rw *ResponseWriter) Hijacked() bool {
+	return rw.owner != nil && rw.owner.hijacked
+}
+
+// SendFile writes count bytes of file, starting at offset, as response body.
+//
+// Headers (including Content-Length) must be set before calling SendFile.
+// When the writer is attached to a connection, pending output is flushed and
+// the file is transmitted with sendfile(2) on Linux (zero-copy); otherwise the
+// section is copied through the writer.
+func (rw *ResponseWriter) SendFile(file *os.File, offset, count int64) (int64, error) {
+	conn := rw.Conn()
+	if conn == nil {
+		if !rw.headerWritten {
+			if err := rw.writeHeaders(); err != nil {
+				return 0, err
+			}
+		}
+		n, err := io.Copy(rw.w, io.NewSectionReader(file, offset, count))
+		rw.bytesWritten += n
+		return n, err
+	}
+
+	if err := rw.Flush(); err != nil {
+		return 0, err
+	}
+	n, err := socket.SendFile(conn, file, offset, count)
+	rw.bytesWritten += n
+	return n, err
+}
+
 // Status returns the HTTP status code that was written.
 // If WriteHeader was not called, returns 200.
 func (rw *ResponseWriter) Status() int {
@@ -166,6 +244,7 @@ func (rw *ResponseWriter) HeaderWritten() bool {
 // Allocation behavior: 0 allocs/op
 func (rw *ResponseWriter) Reset(w io.Writer) {
 	rw.w = w
+	rw.owner = nil
 	rw.status = 200
 	rw.header.Reset()
 	rw.statusWritten = false
@@ -1,8 +1,13 @@
 package http11
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -981,3 +986,72 @@ func TestResponseWriterWriteChunkedPooling(t *testing.T) {
 		t.Errorf("Output missing final marker: %q", output)
 	}
 }
+
+func TestResponseWriterSendFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// Not attached to a connection: copied through the writer
+	var buf bytes.Buffer
+	rw := NewResponseWriter(&buf)
+	rw.Header().Set([]byte("Content-Length"), []byte("4"))
+	n, err := rw.SendFile(file, 2, 4)
+	if err != nil || n != 4 {
+		t.Fatalf("SendFile = %d, %v", n, err)
+	}
+	if !strings.HasSuffix(buf.String(), "\r\n\r\n2345") || rw.BytesWritten() != 4 {
+		t.Errorf("unexpected output %q (%d bytes)", buf.String(), rw.BytesWritten())
+	}
+
+	// Attached to a TCP connection: headers flushed before the file
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw = GetResponseWriter(bufio.NewWriter(conn))
+	rw.owner = &Connection{conn: conn}
+	if rw.Conn() != conn {
+		t.Error("Conn() should return the attached connection")
+	}
+	rw.WriteHeader(206)
+	rw.Header().Set([]byte("Content-Length"), []byte("3"))
+	if _, err := rw.SendFile(file, 7, 3); err != nil {
+		t.Fatalf("SendFile failed: %v", err)
+	}
+	conn.Close()
+
+	output := <-received
+	if !strings.HasPrefix(output, "HTTP/1.1 206 Partial Content\r\n") || !strings.HasSuffix(output, "\r\n\r\n789") {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	PutResponseWriter(rw)
+	if rw.Conn() != nil {
+		t.Error("Reset should detach the connection")
+	}
+}
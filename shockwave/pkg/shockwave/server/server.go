@@ -244,8 +244,9 @@ type BaseServer struct {
 	wg       sync.WaitGroup
 
 	// Connection tracking
-	conns   map[net.Conn]struct{}
-	connsMu sync.Mutex
+	conns     map[net.Conn]struct{}
+	httpConns map[*http11.Connection]struct{} // Drained on Shutdown
+	connsMu   sync.Mutex
 
 	// Connection semaphore (for limiting concurrent connections)
 	connSem chan struct{}
@@ -285,8 +286,9 @@ func NewBaseServer(config Config) *BaseServer {
 
 	s := &BaseServer{
 		config: config,
-		done:   make(chan struct{}),
-		conns:  make(map[net.Conn]struct{}),
+		done:      make(chan struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		httpConns: make(map[*http11.Connection]struct{}),
 	}
 
 	s.stats.StartTime = time.Now()
@@ -323,6 +325,41 @@ func (s *BaseServer) untrackConnection(conn net.Conn) {
 	s.stats.ActiveConnections.Add(-1)
 }
 
+// trackHTTPConnection registers an HTTP/1.1 connection for graceful shutdown.
+// Connections registered after Shutdown started are shut down right away.
+func (s *BaseServer) trackHTTPConnection(conn *http11.Connection) {
+	s.connsMu.Lock()
+	s.httpConns[conn] = struct{}{}
+	shutdown := s.shutdown.Load()
+	s.connsMu.Unlock()
+
+	if shutdown {
+		conn.Shutdown()
+	}
+}
+
+// untrackHTTPConnection removes an HTTP/1.1 connection from tracking
+func (s *BaseServer) untrackHTTPConnection(conn *http11.Connection) {
+	s.connsMu.Lock()
+	delete(s.httpConns, conn)
+	s.connsMu.Unlock()
+}
+
+// shutdownHTTPConnections closes idle HTTP/1.1 connections and makes the
+// others close after their current request.
+func (s *BaseServer) shutdownHTTPConnections() {
+	s.connsMu.Lock()
+	conns := make([]*http11.Connection, 0, len(s.httpConns))
+	for conn := range s.httpConns {
+		conns = append(conns, conn)
+	}
+	s.connsMu.Unlock()
+
+	for _, conn := range conns {
+		conn.Shutdown()
+	}
+}
+
 // closeAllConnections closes all tracked connections
 func (s *BaseServer) closeAllConnections() {
 	s.connsMu.Lock()
@@ -351,6 +388,9 @@ func (s *BaseServer) Shutdown(ctx context.Context) error {
 	// Signal shutdown
 	close(s.done)
 
+	// Close idle keep-alive connections, drain active ones
+	s.shutdownHTTPConnections()
+
 	// Wait for connections to close or context to expire
 	shutdownComplete := make(chan struct{})
 	go func() {
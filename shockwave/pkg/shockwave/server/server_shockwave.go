@@ -225,6 +225,10 @@ func (s *ShockwaveServer) handleConnection(netConn net.Conn) {
 	conn := http11.NewConnection(netConn, connConfig, handler)
 	defer conn.Close()
 
+	// Track for graceful shutdown
+	s.trackHTTPConnection(conn)
+	defer s.untrackHTTPConnection(conn)
+
 	// Serve requests on this connection (handles keep-alive internally)
 	err := conn.Serve()
 
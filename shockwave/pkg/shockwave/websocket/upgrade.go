@@ -148,7 +148,13 @@ func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error
 		writeBufSize = 4096
 	}
 
-	return newConn(netConn, true, readBufSize, writeBufSize, subprotocol), nil
+	conn := newConn(netConn, true, readBufSize, writeBufSize, subprotocol)
+	if brw.Reader.Buffered() > 0 {
+		// Frames sent right after the handshake were already read by the server
+		conn.frameReader.Close()
+		conn.frameReader = NewFrameReader(brw.Reader)
+	}
+	return conn, nil
 }
 
 // Dial establishes a WebSocket client connection to the given URL.
@@ -261,7 +267,13 @@ func Dial(url string, headers http.Header) (*Conn, error) {
 	subprotocol := resp.Header.Get("Sec-WebSocket-Protocol")
 
 	// Create WebSocket connection (client mode)
-	return newConn(netConn, false, 4096, 4096, subprotocol), nil
+	conn := newConn(netConn, false, 4096, 4096, subprotocol)
+	if br.Buffered() > 0 {
+		// Frames sent right after the handshake response were read along with it
+		conn.frameReader.Close()
+		conn.frameReader = NewFrameReader(br)
+	}
+	return conn, nil
 }
 
 // Helper functions